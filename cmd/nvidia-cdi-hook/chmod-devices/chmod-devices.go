@@ -0,0 +1,168 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package chmoddevices
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	paths         cli.StringSlice
+	modeStr       string
+	mode          fs.FileMode
+	containerSpec string
+}
+
+// NewCommand constructs a chmod-devices command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the chmod-devices command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	c := cli.Command{
+		Name:  "chmod-devices",
+		Usage: "Set the permissions of device nodes in the container. The container root is prefixed to the specified paths.",
+		Before: func(c *cli.Context) error {
+			return validateFlags(c, &cfg)
+		},
+		Action: func(c *cli.Context) error {
+			return m.run(c, &cfg)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "path",
+			Usage:       "Specify a device node to apply the specified mode to",
+			Destination: &cfg.paths,
+		},
+		&cli.StringFlag{
+			Name:        "mode",
+			Usage:       "Specify the permissions to set as an octal mode such as 0666",
+			Destination: &cfg.modeStr,
+		},
+		&cli.StringFlag{
+			Name:        "container-spec",
+			Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+			Destination: &cfg.containerSpec,
+		},
+	}
+
+	return &c
+}
+
+func validateFlags(c *cli.Context, cfg *config) error {
+	mode, err := parseMode(cfg.modeStr)
+	if err != nil {
+		return err
+	}
+	cfg.mode = mode
+
+	for _, p := range cfg.paths.Value() {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("paths must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// parseMode parses the specified octal permission string. Only the permission
+// bits may be set.
+func parseMode(modeStr string) (fs.FileMode, error) {
+	modeStr = strings.TrimSpace(modeStr)
+	if modeStr == "" {
+		return 0, fmt.Errorf("a non-empty mode must be specified")
+	}
+	modeInt, err := strconv.ParseUint(modeStr, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse mode as octal: %v", err)
+	}
+	if modeInt&^uint64(fs.ModePerm) != 0 {
+		return 0, fmt.Errorf("invalid mode %v: only permission bits may be set", modeStr)
+	}
+	return fs.FileMode(modeInt), nil
+}
+
+func (m command) run(c *cli.Context, cfg *config) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %v", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %v", err)
+	}
+	if containerRoot == "" {
+		return fmt.Errorf("empty container root detected")
+	}
+
+	return m.chmodDevices(containerRoot, cfg.paths.Value(), cfg.mode)
+}
+
+// chmodDevices sets the permissions of the specified device nodes in the
+// container root. Paths that resolve to a location outside the container root
+// are rejected. Paths that do not exist or are not device nodes are skipped.
+func (m command) chmodDevices(containerRoot string, paths []string, mode fs.FileMode) error {
+	var errs []error
+	for _, p := range paths {
+		path, err := symlinks.ResolveInRoot(containerRoot, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			m.logger.Debugf("Skipping path %q: %v", path, err)
+			continue
+		}
+		if info.Mode()&fs.ModeDevice == 0 {
+			m.logger.Debugf("Skipping path %q: not a device node", path)
+			continue
+		}
+		if info.Mode().Perm() == mode {
+			m.logger.Debugf("Skipping path %q: already desired mode", path)
+			continue
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			errs = append(errs, fmt.Errorf("failed to set mode of %v: %w", path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,34 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package chmoddevices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMode(t *testing.T) {
+	for _, valid := range []string{"0666", "666", "0600", " 0644 "} {
+		_, err := parseMode(valid)
+		require.NoError(t, err, valid)
+	}
+	for _, invalid := range []string{"", "rw-rw-rw-", "0999", "4755", "10666"} {
+		_, err := parseMode(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -20,8 +20,13 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/chmod"
+	chmoddevices "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/chmod-devices"
+	devchar "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/create-dev-char-symlinks"
 	symlinks "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/create-symlinks"
+	relabel "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/relabel-devices"
 	ldcache "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/update-ldcache"
+	mig "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/validate-mig-device"
+	verify "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-cdi-hook/verify-mounts"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
@@ -32,5 +37,10 @@ func New(logger logger.Interface) []*cli.Command {
 		ldcache.NewCommand(logger),
 		symlinks.NewCommand(logger),
 		chmod.NewCommand(logger),
+		verify.NewCommand(logger),
+		mig.NewCommand(logger),
+		relabel.NewCommand(logger),
+		devchar.NewCommand(logger),
+		chmoddevices.NewCommand(logger),
 	}
 }
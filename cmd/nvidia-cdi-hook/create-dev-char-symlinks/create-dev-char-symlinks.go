@@ -0,0 +1,180 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package devchar
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sys/unix"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+)
+
+const (
+	devCharPath = "/dev/char"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	paths         cli.StringSlice
+	containerSpec string
+}
+
+// NewCommand constructs a create-dev-char-symlinks command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the create-dev-char-symlinks command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	c := cli.Command{
+		Name:  "create-dev-char-symlinks",
+		Usage: "Create the /dev/char/<major>:<minor> symlinks for device nodes in the container. The container root is prefixed to the specified paths.",
+		Before: func(c *cli.Context) error {
+			return validateFlags(c, &cfg)
+		},
+		Action: func(c *cli.Context) error {
+			return m.run(c, &cfg)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "path",
+			Usage:       "Specify a device node for which a /dev/char symlink is created",
+			Destination: &cfg.paths,
+		},
+		&cli.StringFlag{
+			Name:        "container-spec",
+			Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+			Destination: &cfg.containerSpec,
+		},
+	}
+
+	return &c
+}
+
+func validateFlags(c *cli.Context, cfg *config) error {
+	for _, p := range cfg.paths.Value() {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("paths must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (m command) run(c *cli.Context, cfg *config) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %v", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %v", err)
+	}
+	if containerRoot == "" {
+		return fmt.Errorf("empty container root detected")
+	}
+
+	return m.createSymlinks(containerRoot, cfg.paths.Value())
+}
+
+// createSymlinks creates a /dev/char/<major>:<minor> symlink in the specified
+// container root for each of the specified device nodes. Paths that do not
+// exist in the container or that are not character devices are skipped. Paths
+// that resolve outside the container root are rejected.
+func (m command) createSymlinks(containerRoot string, paths []string) error {
+	charDir, err := resolveDevCharDir(containerRoot)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range paths {
+		path, err := symlinks.ResolveInRoot(containerRoot, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var stat unix.Stat_t
+		if err := unix.Stat(path, &stat); err != nil {
+			m.logger.Debugf("Skipping path %q: %v", path, err)
+			continue
+		}
+		if stat.Mode&unix.S_IFMT != unix.S_IFCHR {
+			m.logger.Debugf("Skipping path %q: not a character device", path)
+			continue
+		}
+
+		name := fmt.Sprintf("%d:%d", unix.Major(uint64(stat.Rdev)), unix.Minor(uint64(stat.Rdev)))
+		if err := m.createSymlink(filepath.Join(charDir, name), path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to create /dev/char symlink for %v: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// resolveDevCharDir returns the path of the /dev/char folder in the specified
+// container root. The /dev folder is resolved first so that a /dev/char folder
+// that does not yet exist is created in the container root.
+func resolveDevCharDir(containerRoot string) (string, error) {
+	if _, err := symlinks.ResolveInRoot(containerRoot, filepath.Dir(devCharPath)); err != nil {
+		return "", err
+	}
+	return symlinks.ResolveInRoot(containerRoot, devCharPath)
+}
+
+// createSymlink creates the specified link pointing to the specified device
+// path. Both paths are in the container root and the link target is relative
+// so that it is valid both in and outside the container. Existing links are
+// not modified.
+func (m command) createSymlink(linkPath string, devicePath string) error {
+	if _, err := os.Lstat(linkPath); err == nil {
+		m.logger.Debugf("Skipping existing link %q", linkPath)
+		return nil
+	}
+
+	target, err := filepath.Rel(filepath.Dir(linkPath), devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to determine link target: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(linkPath), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	m.logger.Infof("Creating symlink %v -> %v", linkPath, target)
+	return os.Symlink(target, linkPath)
+}
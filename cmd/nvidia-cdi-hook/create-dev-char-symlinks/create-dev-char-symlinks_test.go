@@ -0,0 +1,154 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package devchar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestCreateSymlinks(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	// Creating a character device requires privileges that are not available
+	// in all test environments.
+	probe := filepath.Join(t.TempDir(), "probe")
+	devicesSupported := unix.Mknod(probe, unix.S_IFCHR|0666, int(unix.Mkdev(195, 0))) == nil
+
+	testCases := []struct {
+		description   string
+		prepare       func(containerRoot string, outside string) error
+		paths         []string
+		expectedError bool
+		expectedLinks map[string]string
+	}{
+		{
+			description: "link is created for device in root",
+			prepare: func(containerRoot string, _ string) error {
+				return mknod(filepath.Join(containerRoot, "dev/nvidia0"), 195, 0)
+			},
+			paths:         []string{"/dev/nvidia0"},
+			expectedLinks: map[string]string{"195:0": "../nvidia0"},
+		},
+		{
+			description: "link is created at the target of a relative link",
+			prepare: func(containerRoot string, _ string) error {
+				if err := mknod(filepath.Join(containerRoot, "dev/nvidia1"), 195, 1); err != nil {
+					return err
+				}
+				return os.Symlink("nvidia1", filepath.Join(containerRoot, "dev/nvidia-link"))
+			},
+			paths:         []string{"/dev/nvidia-link"},
+			expectedLinks: map[string]string{"195:1": "../nvidia1"},
+		},
+		{
+			description: "existing link is not modified",
+			prepare: func(containerRoot string, _ string) error {
+				if err := mknod(filepath.Join(containerRoot, "dev/nvidia0"), 195, 0); err != nil {
+					return err
+				}
+				if err := os.MkdirAll(filepath.Join(containerRoot, "dev/char"), 0755); err != nil {
+					return err
+				}
+				return os.Symlink("existing", filepath.Join(containerRoot, "dev/char/195:0"))
+			},
+			paths:         []string{"/dev/nvidia0"},
+			expectedLinks: map[string]string{"195:0": "existing"},
+		},
+		{
+			description: "missing path is skipped",
+			paths:       []string{"/dev/nvidia0"},
+		},
+		{
+			description: "regular file is skipped",
+			prepare: func(containerRoot string, _ string) error {
+				return os.WriteFile(filepath.Join(containerRoot, "dev/nvidia0"), nil, 0644)
+			},
+			paths: []string{"/dev/nvidia0"},
+		},
+		{
+			description:   "path escaping the root is rejected",
+			paths:         []string{"../../../dev/null"},
+			expectedError: true,
+		},
+		{
+			description: "link resolving outside the root is rejected",
+			prepare: func(containerRoot string, _ string) error {
+				return os.Symlink("/dev/null", filepath.Join(containerRoot, "dev/nvidia0"))
+			},
+			paths:         []string{"/dev/nvidia0"},
+			expectedError: true,
+		},
+		{
+			description: "/dev/char resolving outside the root is rejected",
+			prepare: func(containerRoot string, outside string) error {
+				return os.Symlink(outside, filepath.Join(containerRoot, "dev/char"))
+			},
+			paths:         []string{"/dev/nvidia0"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			if len(tc.expectedLinks) > 0 && !devicesSupported {
+				t.Skip("character devices cannot be created")
+			}
+			containerRoot := t.TempDir()
+			outside := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(containerRoot, "dev"), 0755))
+			if tc.prepare != nil {
+				require.NoError(t, tc.prepare(containerRoot, outside))
+			}
+
+			m := command{logger: logger}
+			err := m.createSymlinks(containerRoot, tc.paths)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			links := make(map[string]string)
+			entries, _ := os.ReadDir(filepath.Join(containerRoot, "dev/char"))
+			for _, entry := range entries {
+				target, err := os.Readlink(filepath.Join(containerRoot, "dev/char", entry.Name()))
+				require.NoError(t, err)
+				links[entry.Name()] = target
+			}
+			if len(tc.expectedLinks) == 0 {
+				require.Empty(t, links)
+			} else {
+				require.EqualValues(t, tc.expectedLinks, links)
+			}
+
+			// Nothing must be created outside the root.
+			outsideEntries, err := os.ReadDir(outside)
+			require.NoError(t, err)
+			require.Empty(t, outsideEntries)
+		})
+	}
+}
+
+func mknod(path string, major uint32, minor uint32) error {
+	return unix.Mknod(path, unix.S_IFCHR|0666, int(unix.Mkdev(major, minor)))
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/urfave/cli/v2"
 
@@ -40,6 +41,7 @@ type config struct {
 	filenames     cli.StringSlice
 	links         cli.StringSlice
 	containerSpec string
+	containerRoot string
 }
 
 // NewCommand constructs a hook command with the specified logger
@@ -81,23 +83,23 @@ func (m command) build() *cli.Command {
 		},
 		&cli.StringFlag{
 			Name:        "container-spec",
-			Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+			Usage:       "Specify the path to the OCI container spec. This may be a regular file or a named pipe. If empty or '-' the spec will be read from STDIN",
 			Destination: &cfg.containerSpec,
 		},
+		&cli.StringFlag{
+			Name:        "container-root",
+			Usage:       "Specify the root of the container filesystem directly. If set, the container spec is not used to determine the container root",
+			Destination: &cfg.containerRoot,
+		},
 	}
 
 	return &c
 }
 
 func (m command) run(c *cli.Context, cfg *config) error {
-	s, err := oci.LoadContainerState(cfg.containerSpec)
-	if err != nil {
-		return fmt.Errorf("failed to load container state: %v", err)
-	}
-
-	containerRoot, err := s.GetContainerRoot()
+	containerRoot, err := m.getContainerRoot(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to determined container root: %v", err)
+		return err
 	}
 
 	csvFiles := cfg.filenames.Value()
@@ -127,7 +129,7 @@ func (m command) run(c *cli.Context, cfg *config) error {
 		}
 	}
 
-	created := make(map[string]bool)
+	var links []link
 	// candidates is a list of absolute paths to symlinks in a chain, or the final target of the chain.
 	for _, candidate := range candidates {
 		target, err := symlinks.Resolve(candidate)
@@ -138,40 +140,112 @@ func (m command) run(c *cli.Context, cfg *config) error {
 			m.logger.Debugf("%v is not a symlink", candidate)
 			continue
 		}
-
-		err = m.createLink(created, cfg.hostRoot, containerRoot, target, candidate)
-		if err != nil {
-			m.logger.Warningf("Failed to create link %v: %v", []string{target, candidate}, err)
-		}
+		links = append(links, link{target: target, path: candidate})
 	}
 
-	links := cfg.links.Value()
-	for _, l := range links {
+	for _, l := range cfg.links.Value() {
 		parts := strings.Split(l, "::")
 		if len(parts) != 2 {
 			m.logger.Warningf("Invalid link specification %v", l)
 			continue
 		}
+		links = append(links, link{target: parts[0], path: parts[1]})
+	}
+
+	m.createLinks(cfg.hostRoot, containerRoot, links)
+
+	return nil
+
+}
 
-		err := m.createLink(created, cfg.hostRoot, containerRoot, parts[0], parts[1])
+// getContainerRoot returns the root of the container filesystem. If an
+// explicit container root is specified this is used as is, otherwise the root
+// is determined from the OCI container state.
+func (m command) getContainerRoot(cfg *config) (string, error) {
+	if cfg.containerRoot != "" {
+		info, err := os.Stat(cfg.containerRoot)
 		if err != nil {
-			m.logger.Warningf("Failed to create link %v: %v", parts, err)
+			return "", fmt.Errorf("invalid container root: %v", err)
 		}
+		if !info.IsDir() {
+			return "", fmt.Errorf("invalid container root: %v is not a directory", cfg.containerRoot)
+		}
+		m.logger.Debugf("Using specified container root %v", cfg.containerRoot)
+		return cfg.containerRoot, nil
 	}
 
-	return nil
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return "", fmt.Errorf("failed to load container state: %v", err)
+	}
 
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return "", fmt.Errorf("failed to determined container root: %v", err)
+	}
+	return containerRoot, nil
+}
+
+// maxConcurrentLinks is the maximum number of symlinks that are created
+// concurrently.
+const maxConcurrentLinks = 8
+
+// A link represents a symlink to create in the container.
+type link struct {
+	target string
+	path   string
 }
 
-func (m command) createLink(created map[string]bool, hostRoot string, containerRoot string, target string, link string) error {
+// createLinks creates the specified links in the container. Links that resolve
+// to the same path in the container are only created once. The links are
+// created concurrently using a bounded number of workers and failures to
+// create a link are logged.
+func (m command) createLinks(hostRoot string, containerRoot string, links []link) {
+	created := make(map[string]bool)
+	var unique []link
+	for _, l := range links {
+		linkPath, err := changeRoot(hostRoot, containerRoot, l.path)
+		if err != nil {
+			m.logger.Warningf("Failed to resolve path for link %v relative to %v: %v", l.path, containerRoot, err)
+		}
+		if created[linkPath] {
+			m.logger.Debugf("Link %v already created", linkPath)
+			continue
+		}
+		created[linkPath] = true
+		unique = append(unique, l)
+	}
+
+	errs := make([]error, len(unique))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < maxConcurrentLinks && w < len(unique); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = m.createLink(hostRoot, containerRoot, unique[i].target, unique[i].path)
+			}
+		}()
+	}
+	for i := range unique {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			m.logger.Warningf("Failed to create link %v: %v", []string{unique[i].target, unique[i].path}, err)
+		}
+	}
+}
+
+func (m command) createLink(hostRoot string, containerRoot string, target string, link string) error {
 	linkPath, err := changeRoot(hostRoot, containerRoot, link)
 	if err != nil {
 		m.logger.Warningf("Failed to resolve path for link %v relative to %v: %v", link, containerRoot, err)
 	}
-	if created[linkPath] {
-		m.logger.Debugf("Link %v already created", linkPath)
-		return nil
-	}
 
 	targetPath, err := changeRoot(hostRoot, "/", target)
 	if err != nil {
@@ -0,0 +1,151 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package symlinks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestGetContainerRoot(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	bundleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "config.json"), []byte(`{"root": {"path": "rootfs"}}`), 0600))
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(stateFile, []byte(`{"id": "ctr", "bundle": "`+bundleDir+`"}`), 0600))
+
+	explicitRoot := t.TempDir()
+	regularFile := filepath.Join(explicitRoot, "file")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0600))
+
+	testCases := []struct {
+		description   string
+		cfg           config
+		expectedError bool
+		expectedRoot  string
+	}{
+		{
+			description:  "root is derived from the container state",
+			cfg:          config{containerSpec: stateFile},
+			expectedRoot: filepath.Join(bundleDir, "rootfs"),
+		},
+		{
+			description:  "explicit root bypasses the container state",
+			cfg:          config{containerSpec: filepath.Join(t.TempDir(), "missing.json"), containerRoot: explicitRoot},
+			expectedRoot: explicitRoot,
+		},
+		{
+			description:   "explicit root must exist",
+			cfg:           config{containerRoot: filepath.Join(explicitRoot, "missing")},
+			expectedError: true,
+		},
+		{
+			description:   "explicit root must be a directory",
+			cfg:           config{containerRoot: regularFile},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := command{logger: logger}
+
+			root, err := m.getContainerRoot(&tc.cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRoot, root)
+		})
+	}
+}
+
+func TestRunWithContainerRoot(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	containerRoot := t.TempDir()
+
+	m := command{logger: logger}
+	cfg := config{
+		links:         *cli.NewStringSlice("libcuda.so.1::/usr/lib64/libcuda.so"),
+		containerRoot: containerRoot,
+	}
+	require.NoError(t, m.run(nil, &cfg))
+
+	target, err := os.Readlink(filepath.Join(containerRoot, "/usr/lib64/libcuda.so"))
+	require.NoError(t, err)
+	require.Equal(t, "libcuda.so.1", target)
+}
+
+func TestCreateLinks(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	containerRoot := t.TempDir()
+
+	var links []link
+	for i := 0; i < 200; i++ {
+		l := link{
+			target: fmt.Sprintf("libnvidia-test%d.so.1", i),
+			path:   fmt.Sprintf("/usr/lib/dir%d/libnvidia-test%d.so", i%10, i),
+		}
+		// Include each link twice to ensure that duplicates are only
+		// created once.
+		links = append(links, l, l)
+	}
+	// Include a link that cannot be created to ensure that failures do not
+	// affect the remaining links.
+	require.NoError(t, os.WriteFile(filepath.Join(containerRoot, "file"), nil, 0600))
+	links = append(links, link{target: "target", path: "/file/link"})
+
+	m := command{logger: logger}
+	m.createLinks("", containerRoot, links)
+
+	for i := 0; i < 200; i++ {
+		target, err := os.Readlink(filepath.Join(containerRoot, fmt.Sprintf("/usr/lib/dir%d/libnvidia-test%d.so", i%10, i)))
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("libnvidia-test%d.so.1", i), target)
+	}
+	for i := 0; i < 10; i++ {
+		entries, err := os.ReadDir(filepath.Join(containerRoot, fmt.Sprintf("/usr/lib/dir%d", i)))
+		require.NoError(t, err)
+		require.Len(t, entries, 20)
+	}
+}
+
+func BenchmarkCreateLinks(b *testing.B) {
+	logger, _ := testlog.NewNullLogger()
+	m := command{logger: logger}
+
+	var links []link
+	for i := 0; i < 500; i++ {
+		links = append(links, link{
+			target: fmt.Sprintf("libnvidia-test%d.so.1", i),
+			path:   fmt.Sprintf("/usr/lib64/libnvidia-test%d.so", i),
+		})
+	}
+
+	for i := 0; i < b.N; i++ {
+		containerRoot := b.TempDir()
+		m.createLinks("", containerRoot, links)
+	}
+}
@@ -17,11 +17,13 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	log "github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 
 	cli "github.com/urfave/cli/v2"
 
@@ -35,6 +37,8 @@ type options struct {
 	Debug bool
 	// Quiet indicates whether the CLI is started in "quiet" mode
 	Quiet bool
+	// LogFormat specifies the format of the log output
+	LogFormat string
 }
 
 func main() {
@@ -66,6 +70,13 @@ func main() {
 			Destination: &opts.Quiet,
 			EnvVars:     []string{"NVIDIA_CDI_QUIET"},
 		},
+		&cli.StringFlag{
+			Name:        "log-format",
+			Usage:       "The format of the log output. One of [text | json]",
+			Value:       log.FormatText,
+			Destination: &opts.LogFormat,
+			EnvVars:     []string{"NVIDIA_CDI_LOG_FORMAT"},
+		},
 	}
 
 	// Set log-level for all subcommands
@@ -78,6 +89,14 @@ func main() {
 			logLevel = logrus.ErrorLevel
 		}
 		logger.SetLevel(logLevel)
+
+		fields := logrus.Fields{
+			"component": "nvidia-cdi-hook",
+			"operation": c.Args().First(),
+		}
+		if err := log.SetFormat(logger, opts.LogFormat, fields); err != nil {
+			return fmt.Errorf("invalid --log-format option: %v", err)
+		}
 		return nil
 	}
 
@@ -0,0 +1,149 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package relabel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"golang.org/x/sys/unix"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+)
+
+const (
+	selinuxXattr = "security.selinux"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	paths         cli.StringSlice
+	label         string
+	containerSpec string
+}
+
+// NewCommand constructs a relabel-devices command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the relabel-devices command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	c := cli.Command{
+		Name:  "relabel-devices",
+		Usage: "Set the SELinux label of device nodes in the container. The container root is prefixed to the specified paths.",
+		Before: func(c *cli.Context) error {
+			return validateFlags(c, &cfg)
+		},
+		Action: func(c *cli.Context) error {
+			return m.run(c, &cfg)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "path",
+			Usage:       "Specify a device node to relabel",
+			Destination: &cfg.paths,
+		},
+		&cli.StringFlag{
+			Name:        "label",
+			Usage:       "Specify the SELinux label to apply to the device nodes",
+			Destination: &cfg.label,
+		},
+		&cli.StringFlag{
+			Name:        "container-spec",
+			Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+			Destination: &cfg.containerSpec,
+		},
+	}
+
+	return &c
+}
+
+func validateFlags(c *cli.Context, cfg *config) error {
+	if strings.TrimSpace(cfg.label) == "" {
+		return fmt.Errorf("a non-empty label must be specified")
+	}
+
+	for _, p := range cfg.paths.Value() {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("paths must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (m command) run(c *cli.Context, cfg *config) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %v", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %v", err)
+	}
+	if containerRoot == "" {
+		return fmt.Errorf("empty container root detected")
+	}
+
+	return m.relabelDevices(containerRoot, cfg.paths.Value(), cfg.label)
+}
+
+// relabelDevices sets the SELinux label of the specified device nodes in the
+// container root. Paths that resolve to a location outside the container root
+// are rejected. Paths that do not exist are skipped.
+func (m command) relabelDevices(containerRoot string, paths []string, label string) error {
+	var errs []error
+	for _, p := range paths {
+		path, err := symlinks.ResolveInRoot(containerRoot, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := os.Lstat(path); err != nil {
+			m.logger.Debugf("Skipping path %q: %v", path, err)
+			continue
+		}
+		err = unix.Lsetxattr(path, selinuxXattr, []byte(label), 0)
+		// SELinux labels are not supported on all filesystems.
+		if errors.Is(err, unix.ENOTSUP) {
+			m.logger.Debugf("Ignoring unsupported SELinux label for %q: %v", path, err)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to set label for %v: %w", path, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
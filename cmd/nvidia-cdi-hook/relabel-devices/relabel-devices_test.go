@@ -0,0 +1,156 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package relabel
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestValidateFlags(t *testing.T) {
+	testCases := []struct {
+		description   string
+		label         string
+		paths         []string
+		expectedError bool
+	}{
+		{
+			description: "label and paths are valid",
+			label:       "system_u:object_r:container_file_t:s0",
+			paths:       []string{"/dev/nvidia0"},
+		},
+		{
+			description:   "empty label is an error",
+			label:         " ",
+			paths:         []string{"/dev/nvidia0"},
+			expectedError: true,
+		},
+		{
+			description:   "empty path is an error",
+			label:         "system_u:object_r:container_file_t:s0",
+			paths:         []string{"/dev/nvidia0", " "},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := config{label: tc.label}
+			for _, p := range tc.paths {
+				require.NoError(t, cfg.paths.Set(p))
+			}
+			err := validateFlags(nil, &cfg)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRelabelDevices(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	const label = "system_u:object_r:container_file_t:s0"
+
+	containerRoot := t.TempDir()
+	outside := t.TempDir()
+
+	devDir := filepath.Join(containerRoot, "dev")
+	require.NoError(t, os.MkdirAll(devDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(devDir, "nvidia0"), nil, 0644))
+	require.NoError(t, os.Symlink("nvidia0", filepath.Join(devDir, "relative-link")))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "target"), nil, 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "target"), filepath.Join(devDir, "escaping-link")))
+
+	// Setting an SELinux label requires support from the filesystem and
+	// privileges that are not available in all test environments.
+	probe := filepath.Join(t.TempDir(), "probe")
+	require.NoError(t, os.WriteFile(probe, nil, 0644))
+	labelsSupported := unix.Lsetxattr(probe, selinuxXattr, []byte(label), 0) == nil
+
+	testCases := []struct {
+		description      string
+		paths            []string
+		expectedError    bool
+		expectedLabelled []string
+	}{
+		{
+			description:      "device in root is labelled",
+			paths:            []string{"/dev/nvidia0"},
+			expectedLabelled: []string{filepath.Join(devDir, "nvidia0")},
+		},
+		{
+			description:      "relative link in root is labelled at its target",
+			paths:            []string{"/dev/relative-link"},
+			expectedLabelled: []string{filepath.Join(devDir, "nvidia0")},
+		},
+		{
+			description: "missing path is skipped",
+			paths:       []string{"/dev/nvidia1"},
+		},
+		{
+			description:   "path escaping the root is rejected",
+			paths:         []string{"../../../etc/shadow"},
+			expectedError: true,
+		},
+		{
+			description:   "link resolving outside the root is rejected",
+			paths:         []string{"/dev/escaping-link"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			if len(tc.expectedLabelled) > 0 && !labelsSupported {
+				t.Skip("SELinux labels are not supported")
+			}
+			for _, path := range []string{filepath.Join(devDir, "nvidia0"), filepath.Join(outside, "target")} {
+				err := unix.Lremovexattr(path, selinuxXattr)
+				if err != nil && !errors.Is(err, unix.ENODATA) && !errors.Is(err, unix.ENOTSUP) {
+					require.NoError(t, err)
+				}
+			}
+
+			m := command{logger: logger}
+			err := m.relabelDevices(containerRoot, tc.paths, label)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			for _, path := range tc.expectedLabelled {
+				value := make([]byte, 256)
+				n, err := unix.Lgetxattr(path, selinuxXattr, value)
+				require.NoError(t, err)
+				require.Equal(t, label, string(value[:n]))
+			}
+
+			// The target outside the root must never be modified.
+			_, err = unix.Lgetxattr(filepath.Join(outside, "target"), selinuxXattr, nil)
+			require.Error(t, err)
+		})
+	}
+}
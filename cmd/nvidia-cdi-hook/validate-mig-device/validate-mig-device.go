@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package mig
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvcaps"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	uuid            string
+	gpu             int
+	gpuInstance     int
+	computeInstance int
+}
+
+// NewCommand constructs a validate-mig-device command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the validate-mig-device command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	// Create the 'validate-mig-device' command
+	c := cli.Command{
+		Name:  "validate-mig-device",
+		Usage: "Check that the specified MIG device exists. This allows container creation to fail if the MIG device was destroyed after the CDI specification was generated.",
+		Action: func(c *cli.Context) error {
+			return m.run(c, &cfg)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "uuid",
+			Usage:       "Specify the UUID of the MIG device. This is used for reporting only.",
+			Destination: &cfg.uuid,
+		},
+		&cli.IntFlag{
+			Name:        "gpu",
+			Usage:       "Specify the minor number of the parent GPU",
+			Required:    true,
+			Destination: &cfg.gpu,
+		},
+		&cli.IntFlag{
+			Name:        "gpu-instance",
+			Usage:       "Specify the GPU instance ID of the MIG device",
+			Required:    true,
+			Destination: &cfg.gpuInstance,
+		},
+		&cli.IntFlag{
+			Name:        "compute-instance",
+			Usage:       "Specify the compute instance ID of the MIG device",
+			Required:    true,
+			Destination: &cfg.computeInstance,
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(c *cli.Context, cfg *config) error {
+	caps := []nvcaps.MigCap{
+		nvcaps.NewGPUInstanceCap(cfg.gpu, cfg.gpuInstance),
+		nvcaps.NewComputeInstanceCap(cfg.gpu, cfg.gpuInstance, cfg.computeInstance),
+	}
+
+	for _, cap := range caps {
+		path := cap.ProcPath()
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("MIG device %v (gpu=%d, gi=%d, ci=%d) does not exist: %v", cfg.uuid, cfg.gpu, cfg.gpuInstance, cfg.computeInstance, err)
+		}
+		m.logger.Debugf("Found MIG capability %v", path)
+	}
+
+	return nil
+}
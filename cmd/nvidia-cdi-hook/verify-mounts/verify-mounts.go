@@ -0,0 +1,154 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package verify
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type config struct {
+	paths         cli.StringSlice
+	containerSpec string
+}
+
+// NewCommand constructs a verify-mounts command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build the verify-mounts command
+func (m command) build() *cli.Command {
+	cfg := config{}
+
+	// Create the 'verify-mounts' command
+	c := cli.Command{
+		Name:  "verify-mounts",
+		Usage: "Verify that the specified paths exist in the container. Libraries are also checked to be ELF shared objects. The specified paths are resolved in the container root.",
+		Action: func(c *cli.Context) error {
+			return m.run(c, &cfg)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "path",
+			Usage:       "Specify a path that is expected to exist in the container",
+			Destination: &cfg.paths,
+		},
+		&cli.StringFlag{
+			Name:        "container-spec",
+			Usage:       "Specify the path to the OCI container spec. If empty or '-' the spec will be read from STDIN",
+			Destination: &cfg.containerSpec,
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(c *cli.Context, cfg *config) error {
+	s, err := oci.LoadContainerState(cfg.containerSpec)
+	if err != nil {
+		return fmt.Errorf("failed to load container state: %v", err)
+	}
+
+	containerRoot, err := s.GetContainerRoot()
+	if err != nil {
+		return fmt.Errorf("failed to determined container root: %v", err)
+	}
+	if containerRoot == "" {
+		return fmt.Errorf("empty container root detected")
+	}
+
+	missing := m.getMissingPaths(containerRoot, cfg.paths.Value())
+	if len(missing) > 0 {
+		return fmt.Errorf("%d of %d expected paths are missing or invalid in the container: %v", len(missing), len(cfg.paths.Value()), missing)
+	}
+	m.logger.Infof("Verified %d paths in the container", len(cfg.paths.Value()))
+	return nil
+}
+
+// getMissingPaths returns the paths that do not exist in the specified root.
+// Paths that resolve outside the root are also considered missing. Each
+// problem is logged as it is encountered.
+func (m command) getMissingPaths(root string, paths []string) []string {
+	var missing []string
+	for _, p := range paths {
+		if err := verifyPath(root, p); err != nil {
+			m.logger.Warningf("Invalid path %q: %v", p, err)
+			missing = append(missing, p)
+			continue
+		}
+		m.logger.Debugf("Verified path %q", p)
+	}
+	return missing
+}
+
+// verifyPath checks whether the specified path exists in the specified root.
+// For libraries, it is further checked that the file is an ELF shared object
+// with a dynamic segment.
+func verifyPath(root string, p string) error {
+	path, err := symlinks.ResolveInRoot(root, p)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || !isLibName(path) {
+		return nil
+	}
+
+	f, err := elf.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read ELF file: %v", err)
+	}
+	defer f.Close()
+
+	if f.Type != elf.ET_DYN {
+		return fmt.Errorf("unexpected ELF type %v", f.Type)
+	}
+	for _, prog := range f.Progs {
+		if prog.Type == elf.PT_DYNAMIC {
+			return nil
+		}
+	}
+	return fmt.Errorf("no dynamic segment")
+}
+
+// isLibName checks whether the specified filename is a shared library.
+func isLibName(filename string) bool {
+	base := filepath.Base(filename)
+	return strings.HasSuffix(base, ".so") || strings.Contains(base, ".so.")
+}
@@ -0,0 +1,135 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package verify
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMissingPaths(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	containerRoot := t.TempDir()
+	outside := t.TempDir()
+
+	libDir := filepath.Join(containerRoot, "usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(containerRoot, "usr/bin"), 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), newELFFile(elf.ET_DYN, elf.PT_DYNAMIC), 0644))
+	require.NoError(t, os.Symlink("libcuda.so.999.88.77", filepath.Join(libDir, "libcuda.so.1")))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libnvidia-ml.so.999.88.77"), []byte("not an ELF file"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libnvidia-exec.so.999.88.77"), newELFFile(elf.ET_EXEC, elf.PT_DYNAMIC), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libnvidia-static.so.999.88.77"), newELFFile(elf.ET_DYN, elf.PT_LOAD), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(containerRoot, "usr/bin/nvidia-smi"), nil, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "libnvidia-escaped.so.999.88.77"), newELFFile(elf.ET_DYN, elf.PT_DYNAMIC), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "libnvidia-escaped.so.999.88.77"), filepath.Join(libDir, "libnvidia-escaped.so.999.88.77")))
+
+	testCases := []struct {
+		description     string
+		paths           []string
+		expectedMissing []string
+	}{
+		{
+			description: "existing files and libraries are verified",
+			paths: []string{
+				"/usr/lib64/libcuda.so.999.88.77",
+				"/usr/bin/nvidia-smi",
+				"/usr/lib64",
+			},
+		},
+		{
+			description: "library symlink in root is verified at its target",
+			paths:       []string{"/usr/lib64/libcuda.so.1"},
+		},
+		{
+			description:     "missing path is reported",
+			paths:           []string{"/usr/lib64/libcuda.so.999.88.77", "/usr/lib64/libnvidia-ptxjitcompiler.so.999.88.77"},
+			expectedMissing: []string{"/usr/lib64/libnvidia-ptxjitcompiler.so.999.88.77"},
+		},
+		{
+			description:     "library that is not an ELF file is reported",
+			paths:           []string{"/usr/lib64/libnvidia-ml.so.999.88.77"},
+			expectedMissing: []string{"/usr/lib64/libnvidia-ml.so.999.88.77"},
+		},
+		{
+			description:     "library that is not a shared object is reported",
+			paths:           []string{"/usr/lib64/libnvidia-exec.so.999.88.77"},
+			expectedMissing: []string{"/usr/lib64/libnvidia-exec.so.999.88.77"},
+		},
+		{
+			description:     "library without a dynamic segment is reported",
+			paths:           []string{"/usr/lib64/libnvidia-static.so.999.88.77"},
+			expectedMissing: []string{"/usr/lib64/libnvidia-static.so.999.88.77"},
+		},
+		{
+			description:     "path escaping the root is reported",
+			paths:           []string{"../" + filepath.Base(outside) + "/libnvidia-escaped.so.999.88.77"},
+			expectedMissing: []string{"../" + filepath.Base(outside) + "/libnvidia-escaped.so.999.88.77"},
+		},
+		{
+			description:     "link resolving outside the root is reported",
+			paths:           []string{"/usr/lib64/libnvidia-escaped.so.999.88.77"},
+			expectedMissing: []string{"/usr/lib64/libnvidia-escaped.so.999.88.77"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := command{logger: logger}
+			missing := m.getMissingPaths(containerRoot, tc.paths)
+			require.EqualValues(t, tc.expectedMissing, missing)
+		})
+	}
+}
+
+// newELFFile returns the contents of a minimal 64-bit ELF file of the
+// specified type with a single program header of the specified type.
+func newELFFile(fileType elf.Type, progType elf.ProgType) []byte {
+	header := elf.Header64{
+		Type:      uint16(fileType),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     uint64(binary.Size(elf.Header64{})),
+		Ehsize:    uint16(binary.Size(elf.Header64{})),
+		Phentsize: uint16(binary.Size(elf.Prog64{})),
+		Phnum:     1,
+		Shentsize: uint16(binary.Size(elf.Section64{})),
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	prog := elf.Prog64{
+		Type: uint32(progType),
+	}
+
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, header)
+	_ = binary.Write(&b, binary.LittleEndian, prog)
+	return b.Bytes()
+}
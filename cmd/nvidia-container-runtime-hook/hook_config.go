@@ -110,3 +110,10 @@ func (c *HookConfig) getSwarmResourceEnvvars() []string {
 
 	return envvars
 }
+
+// isInjectionDisabled checks whether GPU injection is disabled for all
+// containers. This is the case if the NVIDIA Container Runtime is configured
+// in "none" mode, regardless of whether mode detection is skipped.
+func (c *HookConfig) isInjectionDisabled() bool {
+	return c.NVIDIAContainerRuntimeConfig.Mode == "none"
+}
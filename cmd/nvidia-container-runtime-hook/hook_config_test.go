@@ -153,3 +153,26 @@ func TestGetSwarmResourceEnvvars(t *testing.T) {
 		})
 	}
 }
+
+func TestIsInjectionDisabled(t *testing.T) {
+	testCases := []struct {
+		mode              string
+		skipModeDetection bool
+		expected          bool
+	}{
+		{mode: "auto"},
+		{mode: "legacy"},
+		{mode: "none", expected: true},
+		{mode: "none", skipModeDetection: true, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode, func(t *testing.T) {
+			c := &HookConfig{}
+			c.NVIDIAContainerRuntimeConfig.Mode = tc.mode
+			c.NVIDIAContainerRuntimeHookConfig.SkipModeDetection = tc.skipModeDetection
+
+			require.Equal(t, tc.expected, c.isInjectionDisabled())
+		})
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -87,3 +89,17 @@ func TestIsPrivileged(t *testing.T) {
 		require.Equal(t, tc.expected, privileged, "%d: %v", i, tc)
 	}
 }
+
+func TestCreateDebugLogDirectory(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "nested/cli/nvidia-container-cli.log")
+
+	require.NoError(t, createDebugLogDirectory(logPath))
+
+	info, err := os.Stat(filepath.Dir(logPath))
+	require.NoError(t, err)
+	require.True(t, info.IsDir())
+	_, err = os.Stat(logPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, createDebugLogDirectory("nvidia-container-cli.log"))
+}
@@ -63,6 +63,17 @@ func getRootfsPath(config containerConfig) string {
 	return rootfs
 }
 
+// createDebugLogDirectory creates the parent directory of the specified debug
+// log file. Without this directory nvidia-container-cli silently does not write
+// the log.
+func createDebugLogDirectory(path string) error {
+	dir := filepath.Dir(filepath.Clean(path))
+	if dir == "." {
+		return nil
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
 func doPrestart() {
 	var err error
 
@@ -73,6 +84,10 @@ func doPrestart() {
 	if err != nil || hook == nil {
 		log.Panicln("error getting hook config:", err)
 	}
+	if hook.isInjectionDisabled() {
+		// GPU injection is disabled for all containers.
+		return
+	}
 	cli := hook.NVIDIAContainerCLIConfig
 
 	container := getContainerConfig(*hook)
@@ -101,6 +116,9 @@ func doPrestart() {
 	if *debugflag {
 		args = append(args, "--debug=/dev/stderr")
 	} else if cli.Debug != "" {
+		if err := createDebugLogDirectory(cli.Debug); err != nil {
+			log.Printf("couldn't create directory for debug log %v: %v", cli.Debug, err)
+		}
 		args = append(args, fmt.Sprintf("--debug=%s", cli.Debug))
 	}
 	if cli.Ldcache != "" {
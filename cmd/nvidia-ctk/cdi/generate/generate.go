@@ -45,6 +45,7 @@ type options struct {
 	output               string
 	format               string
 	deviceNameStrategies cli.StringSlice
+	deviceNameSeparator  string
 	driverRoot           string
 	devRoot              string
 	nvidiaCDIHookPath    string
@@ -53,9 +54,30 @@ type options struct {
 	vendor               string
 	class                string
 
+	librarySizeBudget       int64
+	librarySizeBudgetStrict bool
+
 	configSearchPaths  cli.StringSlice
 	librarySearchPaths cli.StringSlice
 
+	versionLibraryPattern string
+	sonameFallback        bool
+	ldsoconfFolders       bool
+	ldcacheUpdateMode     string
+
+	ldcacheUpdateHookLifecycle string
+	ldcacheIncludedLibraries   cli.StringSlice
+	ldcacheExcludedLibraries   cli.StringSlice
+
+	populateDeviceNodeInfo  bool
+	tolerateDiscoveryErrors bool
+	simplifyEdits           bool
+	readOnlyLibraries       bool
+	verifyHookPaths         bool
+	verifyMounts            bool
+	excludedHooks           cli.StringSlice
+	generationMetadata      bool
+
 	csv struct {
 		files          cli.StringSlice
 		ignorePatterns cli.StringSlice
@@ -106,7 +128,7 @@ func (m command) build() *cli.Command {
 		&cli.StringFlag{
 			Name:        "mode",
 			Aliases:     []string{"discovery-mode"},
-			Usage:       "The mode to use when discovering the available entities. One of [auto | nvml | wsl]. If mode is set to 'auto' the mode will be determined based on the system configuration.",
+			Usage:       "The mode to use when discovering the available entities. One of [auto | nvml | wsl | vfio]. If mode is set to 'auto' the mode will be determined based on the system configuration. In vfio mode, a device is generated for each GPU bound to the vfio-pci driver.",
 			Value:       nvcdi.ModeAuto,
 			Destination: &opts.mode,
 		},
@@ -121,6 +143,22 @@ func (m command) build() *cli.Command {
 			Value:       cli.NewStringSlice(nvcdi.DeviceNameStrategyIndex, nvcdi.DeviceNameStrategyUUID),
 			Destination: &opts.deviceNameStrategies,
 		},
+		&cli.StringFlag{
+			Name:        "device-name-separator",
+			Usage:       "Specify the separator used between the GPU and MIG indices in the names of MIG devices for index-based device name strategies. One of [: | _ | - | .]",
+			Value:       nvcdi.DefaultDeviceNameSeparator,
+			Destination: &opts.deviceNameSeparator,
+		},
+		&cli.Int64Flag{
+			Name:        "library-size-budget",
+			Usage:       "Specify a budget in bytes for the total size of the injected driver libraries. A warning is logged if the budget is exceeded. If this is not positive, no check is performed.",
+			Destination: &opts.librarySizeBudget,
+		},
+		&cli.BoolFlag{
+			Name:        "library-size-budget-strict",
+			Usage:       "Fail spec generation instead of logging a warning if the --library-size-budget is exceeded.",
+			Destination: &opts.librarySizeBudgetStrict,
+		},
 		&cli.StringFlag{
 			Name:        "driver-root",
 			Usage:       "Specify the NVIDIA GPU driver root to use when discovering the entities that should be included in the CDI specification.",
@@ -131,6 +169,84 @@ func (m command) build() *cli.Command {
 			Usage:       "Specify the path to search for libraries when discovering the entities that should be included in the CDI specification.\n\tNote: This option only applies to CSV mode.",
 			Destination: &opts.librarySearchPaths,
 		},
+		&cli.StringFlag{
+			Name:        "version-library-pattern",
+			Usage:       "Specify the pattern used to locate the library from which the driver version is determined. This allows, for example, a libcuda.so from a CUDA forward compatibility package to be used.",
+			Value:       "libcuda.so.*.*",
+			Destination: &opts.versionLibraryPattern,
+		},
+		&cli.BoolFlag{
+			Name:        "library-soname-fallback",
+			Usage:       "Locate driver libraries that are not found by filename by matching the SONAME in their ELF headers instead.",
+			Destination: &opts.sonameFallback,
+		},
+		&cli.BoolFlag{
+			Name:        "include-ldsoconf-folders",
+			Usage:       "Include the library folders declared in the /etc/ld.so.conf.d files of the driver root in the ldcache update. Only folders that contain one of the driver libraries are included.",
+			Destination: &opts.ldsoconfFolders,
+		},
+		&cli.StringFlag{
+			Name:        "ldcache-update-mode",
+			Usage:       "Specify how the driver libraries are made available to the dynamic linker in the container. One of [ldconfig | env]. In env mode, LD_LIBRARY_PATH is set to the library folders instead of updating the ldcache.",
+			Value:       "ldconfig",
+			Destination: &opts.ldcacheUpdateMode,
+		},
+		&cli.StringFlag{
+			Name:        "ldcache-update-hook-lifecycle",
+			Usage:       "Specify the lifecycle at which the ldcache update hook is run. One of [createContainer | startContainer].",
+			Value:       "createContainer",
+			Destination: &opts.ldcacheUpdateHookLifecycle,
+		},
+		&cli.StringSliceFlag{
+			Name:        "ldcache-include-library",
+			Usage:       "Specify a pattern for the names of the driver libraries considered when determining the folders for the ldcache update. If no patterns are specified, all libraries are considered.",
+			Destination: &opts.ldcacheIncludedLibraries,
+		},
+		&cli.StringSliceFlag{
+			Name:        "ldcache-exclude-library",
+			Usage:       "Specify a pattern for the names of the driver libraries not considered when determining the folders for the ldcache update.",
+			Destination: &opts.ldcacheExcludedLibraries,
+		},
+		&cli.BoolFlag{
+			Name:        "populate-device-node-info",
+			Usage:       "Include the type, major, and minor numbers of device nodes in the generated spec. By default these are determined by the runtime.",
+			Destination: &opts.populateDeviceNodeInfo,
+		},
+		&cli.BoolFlag{
+			Name:        "tolerate-discovery-errors",
+			Usage:       "Log errors discovering the devices, mounts, or hooks for the generated spec as warnings instead of failing. Note that the generated spec may be incomplete.",
+			Destination: &opts.tolerateDiscoveryErrors,
+		},
+		&cli.BoolFlag{
+			Name:        "simplify-edits",
+			Usage:       "Remove redundant entries, such as shadowed mounts, from the generated container edits and sort the remaining entries.",
+			Destination: &opts.simplifyEdits,
+		},
+		&cli.BoolFlag{
+			Name:        "read-only-libraries",
+			Usage:       "Ensure that the mounts for shared libraries in the generated spec are read-only.",
+			Destination: &opts.readOnlyLibraries,
+		},
+		&cli.BoolFlag{
+			Name:        "verify-hook-paths",
+			Usage:       "Require the paths of the hooks in the generated spec to exist and be executable on this host.",
+			Destination: &opts.verifyHookPaths,
+		},
+		&cli.BoolFlag{
+			Name:        "verify-mounts",
+			Usage:       "Include a hook in the generated spec that verifies that the mounted driver files exist in the container when it is created.",
+			Destination: &opts.verifyMounts,
+		},
+		&cli.StringSliceFlag{
+			Name:        "exclude-hook",
+			Usage:       "Specify the name of a hook to exclude from the generated spec. This matches either the hook subcommand (e.g. update-ldcache) or the name of the hook executable.",
+			Destination: &opts.excludedHooks,
+		},
+		&cli.BoolFlag{
+			Name:        "generation-metadata",
+			Usage:       "Include annotations recording the toolkit version, generation time, mode, and driver version in the generated spec. This requires a CDI spec version of at least v0.6.0.",
+			Destination: &opts.generationMetadata,
+		},
 		&cli.StringFlag{
 			Name:    "nvidia-cdi-hook-path",
 			Aliases: []string{"nvidia-ctk-path"},
@@ -154,8 +270,7 @@ func (m command) build() *cli.Command {
 		&cli.StringFlag{
 			Name:        "class",
 			Aliases:     []string{"cdi-class"},
-			Usage:       "the class string to use for the generated CDI specification.",
-			Value:       "gpu",
+			Usage:       "the class string to use for the generated CDI specification. If not specified, the default for the mode is used (e.g. gpu, or pgpu in vfio mode).",
 			Destination: &opts.class,
 		},
 		&cli.StringSliceFlag{
@@ -190,12 +305,25 @@ func (m command) validateFlags(c *cli.Context, opts *options) error {
 	case nvcdi.ModeNvml:
 	case nvcdi.ModeWsl:
 	case nvcdi.ModeManagement:
+	case nvcdi.ModeVfio:
 	default:
 		return fmt.Errorf("invalid discovery mode: %v", opts.mode)
 	}
 
+	switch opts.ldcacheUpdateMode {
+	case "ldconfig", "env":
+	default:
+		return fmt.Errorf("invalid ldcache update mode: %v", opts.ldcacheUpdateMode)
+	}
+
+	switch opts.ldcacheUpdateHookLifecycle {
+	case "createContainer", "startContainer":
+	default:
+		return fmt.Errorf("invalid ldcache update hook lifecycle: %v", opts.ldcacheUpdateHookLifecycle)
+	}
+
 	for _, strategy := range opts.deviceNameStrategies.Value() {
-		_, err := nvcdi.NewDeviceNamer(strategy)
+		_, err := nvcdi.NewDeviceNamer(strategy, nvcdi.WithDeviceNameSeparator(opts.deviceNameSeparator))
 		if err != nil {
 			return err
 		}
@@ -215,8 +343,10 @@ func (m command) validateFlags(c *cli.Context, opts *options) error {
 	if err := cdi.ValidateVendorName(opts.vendor); err != nil {
 		return fmt.Errorf("invalid CDI vendor name: %v", err)
 	}
-	if err := cdi.ValidateClassName(opts.class); err != nil {
-		return fmt.Errorf("invalid CDI class name: %v", err)
+	if opts.class != "" {
+		if err := cdi.ValidateClassName(opts.class); err != nil {
+			return fmt.Errorf("invalid CDI class name: %v", err)
+		}
 	}
 	return nil
 }
@@ -254,14 +384,14 @@ func formatFromFilename(filename string) string {
 func (m command) generateSpec(opts *options) (spec.Interface, error) {
 	var deviceNamers []nvcdi.DeviceNamer
 	for _, strategy := range opts.deviceNameStrategies.Value() {
-		deviceNamer, err := nvcdi.NewDeviceNamer(strategy)
+		deviceNamer, err := nvcdi.NewDeviceNamer(strategy, nvcdi.WithDeviceNameSeparator(opts.deviceNameSeparator))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create device namer: %v", err)
 		}
 		deviceNamers = append(deviceNamers, deviceNamer)
 	}
 
-	cdilib, err := nvcdi.New(
+	cdilibOptions := []nvcdi.Option{
 		nvcdi.WithLogger(m.logger),
 		nvcdi.WithDriverRoot(opts.driverRoot),
 		nvcdi.WithDevRoot(opts.devRoot),
@@ -271,33 +401,44 @@ func (m command) generateSpec(opts *options) (spec.Interface, error) {
 		nvcdi.WithMode(opts.mode),
 		nvcdi.WithConfigSearchPaths(opts.configSearchPaths.Value()),
 		nvcdi.WithLibrarySearchPaths(opts.librarySearchPaths.Value()),
+		nvcdi.WithVersionLibraryPattern(opts.versionLibraryPattern),
+		nvcdi.WithSonameFallback(opts.sonameFallback),
+		nvcdi.WithLdsoconfFolders(opts.ldsoconfFolders),
+		nvcdi.WithLDCacheUpdateMode(opts.ldcacheUpdateMode),
+		nvcdi.WithLDCacheUpdateHookLifecycle(opts.ldcacheUpdateHookLifecycle),
+		nvcdi.WithLDCacheIncludedLibraries(opts.ldcacheIncludedLibraries.Value()...),
+		nvcdi.WithLDCacheExcludedLibraries(opts.ldcacheExcludedLibraries.Value()...),
 		nvcdi.WithCSVFiles(opts.csv.files.Value()),
 		nvcdi.WithCSVIgnorePatterns(opts.csv.ignorePatterns.Value()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create CDI library: %v", err)
+		nvcdi.WithLibrarySizeBudget(opts.librarySizeBudget, opts.librarySizeBudgetStrict),
+		nvcdi.WithPopulateDeviceNodeInfo(opts.populateDeviceNodeInfo),
+		nvcdi.WithTolerateDiscoveryErrors(opts.tolerateDiscoveryErrors),
+		nvcdi.WithSimplifyEdits(opts.simplifyEdits),
+		nvcdi.WithReadOnlyLibraries(opts.readOnlyLibraries),
+		nvcdi.WithVerifyHookPaths(opts.verifyHookPaths),
+		nvcdi.WithVerifyMounts(opts.verifyMounts),
+		nvcdi.WithExcludedHooks(opts.excludedHooks.Value()...),
+		nvcdi.WithGenerationMetadata(opts.generationMetadata),
+		nvcdi.WithVendor(opts.vendor),
+		nvcdi.WithMergedDeviceOptions(
+			transform.WithName(allDeviceName),
+			transform.WithSkipIfExists(true),
+		),
+		nvcdi.WithSpecOptions(
+			spec.WithFormat(opts.format),
+			spec.WithPermissions(0644),
+		),
 	}
-
-	deviceSpecs, err := cdilib.GetAllDeviceSpecs()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create device CDI specs: %v", err)
+	// The class is only set if specified so that the default class for the
+	// mode (e.g. pgpu for the vfio mode) is used otherwise.
+	if opts.class != "" {
+		cdilibOptions = append(cdilibOptions, nvcdi.WithClass(opts.class))
 	}
 
-	commonEdits, err := cdilib.GetCommonEdits()
+	cdilib, err := nvcdi.New(cdilibOptions...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create edits common for entities: %v", err)
+		return nil, fmt.Errorf("failed to create CDI library: %v", err)
 	}
 
-	return spec.New(
-		spec.WithVendor(opts.vendor),
-		spec.WithClass(opts.class),
-		spec.WithDeviceSpecs(deviceSpecs),
-		spec.WithEdits(*commonEdits.ContainerEdits),
-		spec.WithFormat(opts.format),
-		spec.WithMergedDeviceOptions(
-			transform.WithName(allDeviceName),
-			transform.WithSkipIfExists(true),
-		),
-		spec.WithPermissions(0644),
-	)
+	return cdilib.GetSpec()
 }
@@ -0,0 +1,176 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	input    string
+	hostRoot string
+}
+
+// NewCommand constructs a validate command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build creates the CLI command
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "validate",
+		Usage: "Check that the device nodes, mounts, and hooks referenced by a CDI specification exist on the host",
+		Action: func(c *cli.Context) error {
+			return m.run(c, &opts)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "input",
+			Usage:       "Specify the file to read the CDI specification from. If this is '-' the specification is read from STDIN",
+			Value:       "-",
+			Destination: &opts.input,
+		},
+		&cli.StringFlag{
+			Name:        "host-root",
+			Usage:       "Specify the root on the host relative to which the paths in the CDI specification are checked",
+			Value:       "/",
+			Destination: &opts.hostRoot,
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(c *cli.Context, opts *options) error {
+	contents, err := opts.getContents()
+	if err != nil {
+		return fmt.Errorf("failed to read spec contents: %v", err)
+	}
+
+	raw, err := cdi.ParseSpec(contents)
+	if err != nil {
+		return fmt.Errorf("failed to parse CDI spec: %v", err)
+	}
+
+	if err := New(m.logger, opts.hostRoot).Validate(raw); err != nil {
+		return fmt.Errorf("CDI specification is invalid for the host:\n%w", err)
+	}
+	m.logger.Infof("CDI specification for %v is valid for the host", raw.Kind)
+	return nil
+}
+
+func (o options) getContents() ([]byte, error) {
+	if o.input == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(o.input)
+}
+
+// Validator checks the host paths referenced by a CDI specification.
+type Validator struct {
+	deviceNodes lookup.Locator
+	mounts      lookup.Locator
+	hooks       lookup.Locator
+}
+
+// New creates a validator that checks the paths in a CDI specification
+// relative to the specified host root.
+func New(logger logger.Interface, hostRoot string) *Validator {
+	v := Validator{
+		deviceNodes: lookup.NewCharDeviceLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(hostRoot),
+		),
+		mounts: lookup.NewFileLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(hostRoot),
+			lookup.WithFilter(assertAccessible),
+		),
+		hooks: lookup.NewExecutableLocator(logger, hostRoot),
+	}
+	return &v
+}
+
+// Validate checks that the device nodes and mounts referenced by the
+// specified spec exist on the host and that the hooks are executable.
+// All problems are reported in the returned error.
+func (v *Validator) Validate(raw *specs.Spec) error {
+	var errs []error
+	if err := v.validateEdits(&raw.ContainerEdits); err != nil {
+		errs = append(errs, fmt.Errorf("common edits: %w", err))
+	}
+	for _, device := range raw.Devices {
+		d := device
+		if err := v.validateEdits(&d.ContainerEdits); err != nil {
+			errs = append(errs, fmt.Errorf("device %q: %w", d.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateEdits(edits *specs.ContainerEdits) error {
+	var errs []error
+	for _, dn := range edits.DeviceNodes {
+		hostPath := dn.HostPath
+		if hostPath == "" {
+			hostPath = dn.Path
+		}
+		if _, err := v.deviceNodes.Locate(hostPath); err != nil {
+			errs = append(errs, fmt.Errorf("device node %v: %w", hostPath, err))
+		}
+	}
+	for _, m := range edits.Mounts {
+		if _, err := v.mounts.Locate(m.HostPath); err != nil {
+			errs = append(errs, fmt.Errorf("mount %v: %w", m.HostPath, err))
+		}
+	}
+	for _, h := range edits.Hooks {
+		if _, err := v.hooks.Locate(h.Path); err != nil {
+			errs = append(errs, fmt.Errorf("%v hook %v: %w", h.HookName, h.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// assertAccessible checks whether the specified path exists and can be
+// accessed.
+func assertAccessible(filename string) error {
+	_, err := os.Stat(filename)
+	return err
+}
@@ -0,0 +1,118 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestValidate(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	hostRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "lib"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(hostRoot, "lib/libcuda.so.1"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "bin"), 0755))
+	hookPath := filepath.Join(hostRoot, "bin/nvidia-cdi-hook")
+	require.NoError(t, os.WriteFile(hookPath, nil, 0755))
+	notExecutable := filepath.Join(hostRoot, "bin/not-executable")
+	require.NoError(t, os.WriteFile(notExecutable, nil, 0644))
+
+	testCases := []struct {
+		description    string
+		spec           *specs.Spec
+		expectedErrors []string
+	}{
+		{
+			description: "present paths are valid",
+			spec: &specs.Spec{
+				ContainerEdits: specs.ContainerEdits{
+					Mounts: []*specs.Mount{
+						{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1"},
+						{HostPath: "/lib", ContainerPath: "/lib"},
+					},
+					Hooks: []*specs.Hook{
+						{HookName: "createContainer", Path: hookPath},
+					},
+				},
+				Devices: []specs.Device{
+					{
+						Name: "0",
+						ContainerEdits: specs.ContainerEdits{
+							DeviceNodes: []*specs.DeviceNode{
+								{Path: "/dev/null"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			description: "all missing paths are reported",
+			spec: &specs.Spec{
+				ContainerEdits: specs.ContainerEdits{
+					Mounts: []*specs.Mount{
+						{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1"},
+						{HostPath: "/lib/libnvidia-ml.so.1", ContainerPath: "/lib/libnvidia-ml.so.1"},
+					},
+					Hooks: []*specs.Hook{
+						{HookName: "createContainer", Path: notExecutable},
+					},
+				},
+				Devices: []specs.Device{
+					{
+						Name: "0",
+						ContainerEdits: specs.ContainerEdits{
+							DeviceNodes: []*specs.DeviceNode{
+								{Path: "/dev/nvidia0", HostPath: "/dev/missing-nvidia0"},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: []string{
+				"mount /lib/libnvidia-ml.so.1",
+				"createContainer hook " + notExecutable,
+				`device "0": device node /dev/missing-nvidia0`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			v := New(logger, hostRoot)
+			// Device nodes are checked on the actual host.
+			v.deviceNodes = New(logger, "/").deviceNodes
+
+			err := v.Validate(tc.spec)
+			if len(tc.expectedErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
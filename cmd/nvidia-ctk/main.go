@@ -17,6 +17,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -27,7 +28,9 @@ import (
 	infoCLI "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/info"
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/runtime"
 	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system"
+	"github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/toolkit"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+	log "github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 
 	cli "github.com/urfave/cli/v2"
 )
@@ -39,6 +42,8 @@ type options struct {
 	Debug bool
 	// Quiet indicates whether the CLI is started in "quiet" mode
 	Quiet bool
+	// LogFormat specifies the format of the log output
+	LogFormat string
 }
 
 func main() {
@@ -70,6 +75,13 @@ func main() {
 			Destination: &opts.Quiet,
 			EnvVars:     []string{"NVIDIA_CTK_QUIET"},
 		},
+		&cli.StringFlag{
+			Name:        "log-format",
+			Usage:       "The format of the log output. One of [text | json]",
+			Value:       log.FormatText,
+			Destination: &opts.LogFormat,
+			EnvVars:     []string{"NVIDIA_CTK_LOG_FORMAT"},
+		},
 	}
 
 	// Set log-level for all subcommands
@@ -82,6 +94,14 @@ func main() {
 			logLevel = logrus.ErrorLevel
 		}
 		logger.SetLevel(logLevel)
+
+		fields := logrus.Fields{
+			"component": "nvidia-ctk",
+			"operation": c.Args().First(),
+		}
+		if err := log.SetFormat(logger, opts.LogFormat, fields); err != nil {
+			return fmt.Errorf("invalid --log-format option: %v", err)
+		}
 		return nil
 	}
 
@@ -93,6 +113,7 @@ func main() {
 		cdi.NewCommand(logger),
 		system.NewCommand(logger),
 		config.NewCommand(logger),
+		toolkit.NewCommand(logger),
 	}
 
 	// Run the CLI
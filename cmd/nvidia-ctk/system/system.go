@@ -22,6 +22,7 @@ import (
 	devchar "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-dev-char-symlinks"
 	devicenodes "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/create-device-nodes"
 	ldcache "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/print-ldcache"
+	validatedriverroot "github.com/NVIDIA/nvidia-container-toolkit/cmd/nvidia-ctk/system/validate-driver-root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
@@ -48,6 +49,7 @@ func (m command) build() *cli.Command {
 		devchar.NewCommand(m.logger),
 		devicenodes.NewCommand(m.logger),
 		ldcache.NewCommand(m.logger),
+		validatedriverroot.NewCommand(m.logger),
 	}
 
 	return &system
@@ -0,0 +1,256 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validatedriverroot
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+// sonameLinks are the SONAME symlinks that are expected to point to the
+// versioned driver libraries.
+var sonameLinks = []string{
+	"libcuda.so.1",
+	"libnvidia-ml.so.1",
+}
+
+// driverLibraryPatterns match the names of the versioned libraries that are
+// installed by the driver.
+var driverLibraryPatterns = []string{
+	"libcuda.so.*",
+	"libcudadebugger.so.*",
+	"libnvcuvid.so.*",
+	"libnvidia-*.so.*",
+	"libnvoptix.so.*",
+	"lib*_nvidia.so.*",
+}
+
+// driverVersionSuffix matches library suffixes that are driver versions such
+// as 550.54.15. This excludes libraries such as libnvidia-egl-wayland.so.1.1.13
+// that are versioned independently of the driver.
+var driverVersionSuffix = regexp.MustCompile(`^[0-9]{3}\.[0-9]+(\.[0-9]+)?$`)
+
+type command struct {
+	logger logger.Interface
+}
+
+type options struct {
+	driverRoot            string
+	librarySearchPaths    cli.StringSlice
+	versionLibraryPattern string
+	layers                cli.StringSlice
+}
+
+// NewCommand constructs a validate-driver-root command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "validate-driver-root",
+		Usage: "Check that the driver installation at the specified root is consistent",
+		Action: func(c *cli.Context) error {
+			return m.run(c, &opts)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "driver-root",
+			Usage:       "the path to the driver root to validate",
+			Value:       "/",
+			Destination: &opts.driverRoot,
+			EnvVars:     []string{"NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "library-search-path",
+			Usage:       "Specify the path to search for libraries in the driver root.",
+			Destination: &opts.librarySearchPaths,
+		},
+		&cli.StringFlag{
+			Name:        "version-library-pattern",
+			Usage:       "Specify the pattern used to locate the library from which the driver version is determined.",
+			Destination: &opts.versionLibraryPattern,
+		},
+		&cli.StringSliceFlag{
+			Name:        "layer",
+			Usage:       "Specify the layer directories if the driver root is an overlay mount, ordered from the uppermost to the lowest layer. Driver libraries in a lower layer that are shadowed by an upper layer are reported.",
+			Destination: &opts.layers,
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(c *cli.Context, opts *options) error {
+	driver := root.New(
+		root.WithLogger(m.logger),
+		root.WithDriverRoot(opts.driverRoot),
+		root.WithLibrarySearchPaths(opts.librarySearchPaths.Value()...),
+		root.WithVersionLibraryPattern(opts.versionLibraryPattern),
+	)
+
+	if layers := opts.layers.Value(); len(layers) > 0 {
+		reportShadowedLibraries(m.logger, opts.librarySearchPaths.Value(), layers)
+	}
+
+	if err := validate(driver); err != nil {
+		return fmt.Errorf("driver root %v is inconsistent:\n%w", opts.driverRoot, err)
+	}
+	m.logger.Infof("Driver root %v is consistent", opts.driverRoot)
+	return nil
+}
+
+// validate checks the consistency of the specified driver root. All problems
+// that are found are returned.
+func validate(driver *root.Driver) error {
+	var errs []error
+	version, err := driver.Version()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	libRoot, err := getLibraryRoot(driver)
+	if err != nil {
+		errs = append(errs, err)
+		return errors.Join(errs...)
+	}
+
+	errs = append(errs, checkLibraryVersions(libRoot, version)...)
+	errs = append(errs, checkSonameLinks(libRoot, version)...)
+	return errors.Join(errs...)
+}
+
+// reportShadowedLibraries locates the driver libraries in the specified layers
+// of an overlay-mounted driver root. A warning is logged for each library in a
+// lower layer that is shadowed by an upper layer since the merged driver root
+// may then contain libraries from different driver installations.
+func reportShadowedLibraries(logger logger.Interface, searchPaths []string, layers []string) {
+	locator := lookup.NewLibraryLocator(
+		lookup.WithLogger(logger),
+		lookup.WithSearchPaths(searchPaths...),
+		lookup.WithLayers(layers...),
+	)
+	for _, pattern := range driverLibraryPatterns {
+		if _, err := locator.Locate(pattern); err != nil {
+			logger.Debugf("No %v libraries found in layers: %v", pattern, err)
+		}
+	}
+}
+
+// getLibraryRoot returns the resolved directory containing the driver
+// libraries. This is the directory of the version library or, if this cannot
+// be located, of one of the expected SONAME symlinks.
+func getLibraryRoot(driver *root.Driver) (string, error) {
+	libraryPath, err := driver.VersionLibraryPath()
+	if err != nil {
+		for _, link := range sonameLinks {
+			candidates, err := driver.Libraries().Locate(link)
+			if err != nil || len(candidates) == 0 {
+				continue
+			}
+			libraryPath = candidates[0]
+			break
+		}
+	}
+	if libraryPath == "" {
+		return "", fmt.Errorf("failed to determine library root: no driver libraries found")
+	}
+
+	libRoot, err := filepath.EvalSymlinks(filepath.Dir(libraryPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve library root: %w", err)
+	}
+	return libRoot, nil
+}
+
+// checkLibraryVersions checks that all driver libraries in the library root
+// have the specified version. Only libraries that match the names of driver
+// libraries are considered so that other libraries in a shared library root
+// such as /usr/lib64 are ignored. If the version is not known, the libraries
+// are checked for mixed versions instead.
+func checkLibraryVersions(libRoot string, version string) []error {
+	var libraries []string
+	for _, pattern := range driverLibraryPatterns {
+		matches, err := filepath.Glob(filepath.Join(libRoot, pattern))
+		if err != nil {
+			return []error{err}
+		}
+		libraries = append(libraries, matches...)
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for _, library := range libraries {
+		if seen[library] {
+			continue
+		}
+		seen[library] = true
+
+		_, suffix, _ := strings.Cut(filepath.Base(library), ".so.")
+		if !driverVersionSuffix.MatchString(suffix) {
+			continue
+		}
+		if version == "" {
+			version = suffix
+			continue
+		}
+		if suffix == version {
+			continue
+		}
+		errs = append(errs, fmt.Errorf("library %v has version %v; expected %v", library, suffix, version))
+	}
+	return errs
+}
+
+// checkSonameLinks checks that the expected SONAME symlinks exist in the
+// library root and point to the libraries with the specified version.
+func checkSonameLinks(libRoot string, version string) []error {
+	var errs []error
+	for _, link := range sonameLinks {
+		linkPath := filepath.Join(libRoot, link)
+		target, err := filepath.EvalSymlinks(linkPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve %v: %w", linkPath, err))
+			continue
+		}
+		if version == "" {
+			continue
+		}
+		expected := strings.TrimSuffix(link, ".1") + "." + version
+		if filepath.Base(target) != expected {
+			errs = append(errs, fmt.Errorf("%v resolves to %v; expected %v", linkPath, target, expected))
+		}
+	}
+	return errs
+}
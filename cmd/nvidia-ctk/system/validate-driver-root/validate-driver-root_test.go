@@ -0,0 +1,160 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validatedriverroot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestValidate(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		files          []string
+		symlinks       map[string]string
+		expectedErrors []string
+	}{
+		{
+			description: "consistent driver root",
+			files: []string{
+				"libcuda.so.550.54.15",
+				"libnvidia-ml.so.550.54.15",
+				"libnvidia-egl-wayland.so.1.1.13",
+			},
+			symlinks: map[string]string{
+				"libcuda.so.1":      "libcuda.so.550.54.15",
+				"libnvidia-ml.so.1": "libnvidia-ml.so.550.54.15",
+			},
+		},
+		{
+			description: "mixed versions and missing symlinks are reported",
+			files: []string{
+				"libcuda.so.550.54.15",
+				"libnvidia-ml.so.535.161.08",
+				"libnvidia-ptxjitcompiler.so.535.161.08",
+			},
+			symlinks: map[string]string{
+				"libnvidia-ml.so.1": "libnvidia-ml.so.535.161.08",
+			},
+			expectedErrors: []string{
+				"libnvidia-ml.so.535.161.08 has version 535.161.08; expected 550.54.15",
+				"libnvidia-ptxjitcompiler.so.535.161.08 has version 535.161.08; expected 550.54.15",
+				"failed to resolve",
+				"expected libnvidia-ml.so.550.54.15",
+			},
+		},
+		{
+			description: "unrelated libraries are ignored",
+			files: []string{
+				"libcuda.so.550.54.15",
+				"libnvidia-ml.so.550.54.15",
+				"libicudata.so.740.1",
+				"libfoo.so.123.4.5",
+			},
+			symlinks: map[string]string{
+				"libcuda.so.1":      "libcuda.so.550.54.15",
+				"libnvidia-ml.so.1": "libnvidia-ml.so.550.54.15",
+			},
+		},
+		{
+			description: "missing libcuda is reported",
+			files:       []string{"libnvidia-ml.so.550.54.15"},
+			expectedErrors: []string{
+				"libcuda.so.*.*",
+				"failed to determine library root",
+			},
+		},
+		{
+			description: "all problems are reported if libcuda is missing",
+			files: []string{
+				"libnvidia-ml.so.550.54.15",
+				"libnvidia-ptxjitcompiler.so.535.161.08",
+			},
+			symlinks: map[string]string{
+				"libnvidia-ml.so.1": "libnvidia-ml.so.550.54.15",
+			},
+			expectedErrors: []string{
+				"libcuda.so.*.*",
+				"libnvidia-ptxjitcompiler.so.535.161.08 has version 535.161.08; expected 550.54.15",
+				"libcuda.so.1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			libRoot := filepath.Join(driverRoot, "usr/lib64")
+			require.NoError(t, os.MkdirAll(libRoot, 0755))
+			for _, file := range tc.files {
+				require.NoError(t, os.WriteFile(filepath.Join(libRoot, file), nil, 0644))
+			}
+			for link, target := range tc.symlinks {
+				require.NoError(t, os.Symlink(target, filepath.Join(libRoot, link)))
+			}
+
+			err := validate(root.New(
+				root.WithLogger(logger),
+				root.WithDriverRoot(driverRoot),
+			))
+			if len(tc.expectedErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tc.expectedErrors {
+				require.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestReportShadowedLibraries(t *testing.T) {
+	logger, hook := testlog.NewNullLogger()
+
+	testDir := t.TempDir()
+	upper := filepath.Join(testDir, "upper")
+	lower := filepath.Join(testDir, "lower")
+	for _, library := range []string{
+		filepath.Join(upper, "usr/lib64/libcuda.so.550.54.15"),
+		filepath.Join(lower, "usr/lib64/libcuda.so.550.54.15"),
+		filepath.Join(lower, "usr/lib64/libnvidia-ml.so.550.54.15"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(library), 0755))
+		require.NoError(t, os.WriteFile(library, nil, 0644))
+	}
+
+	reportShadowedLibraries(logger, nil, []string{upper, lower})
+
+	var warnings []string
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warnings = append(warnings, entry.Message)
+		}
+	}
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], filepath.Join(lower, "usr/lib64/libcuda.so.550.54.15"))
+}
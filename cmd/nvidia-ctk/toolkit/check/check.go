@@ -0,0 +1,219 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package check
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc/devices"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvdevices"
+)
+
+const (
+	defaultToolkitRoot = "/usr/local/nvidia/toolkit"
+)
+
+type command struct {
+	logger logger.Interface
+	// devices overrides the NVIDIA device info read from /proc/devices.
+	devices devices.Devices
+}
+
+type options struct {
+	toolkitRoot string
+	configPath  string
+	devRoot     string
+}
+
+// result records the outcome of a single check.
+type result struct {
+	name string
+	err  error
+}
+
+// NewCommand constructs a check command with the specified logger
+func NewCommand(logger logger.Interface) *cli.Command {
+	c := command{
+		logger: logger,
+	}
+	return c.build()
+}
+
+// build
+func (m command) build() *cli.Command {
+	opts := options{}
+
+	c := cli.Command{
+		Name:  "check",
+		Usage: "Check that an installation of the NVIDIA Container Toolkit is functional",
+		Action: func(c *cli.Context) error {
+			return m.run(c, &opts)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "toolkit-root",
+			Usage:       "the path where the NVIDIA Container Toolkit is installed",
+			Value:       defaultToolkitRoot,
+			Destination: &opts.toolkitRoot,
+			EnvVars:     []string{"TOOLKIT_ROOT"},
+		},
+		&cli.StringFlag{
+			Name:        "config",
+			Usage:       "the path to the installed config file. If this is not specified, the config in the toolkit root is used.",
+			Destination: &opts.configPath,
+		},
+		&cli.StringFlag{
+			Name:        "dev-root",
+			Usage:       "the root where the NVIDIA control device nodes are expected",
+			Value:       "/",
+			Destination: &opts.devRoot,
+			EnvVars:     []string{"NVIDIA_DEV_ROOT", "DEV_ROOT"},
+		},
+	}
+
+	return &c
+}
+
+func (m command) run(c *cli.Context, opts *options) error {
+	results := m.check(opts)
+	if err := printSummary(c.App.Writer, results); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// check runs the checks against the installed toolkit. The config is checked
+// first and the executables referenced by the config are only checked if it
+// can be loaded.
+func (m command) check(opts *options) []result {
+	configPath := opts.configPath
+	if configPath == "" {
+		configPath = filepath.Join(opts.toolkitRoot, ".config", "nvidia-container-runtime", "config.toml")
+	}
+
+	var results []result
+	cfg, err := loadConfig(configPath)
+	results = append(results, result{name: "config " + configPath, err: err})
+	if cfg != nil {
+		locator := lookup.NewExecutableLocator(m.logger, "")
+		executables := []struct {
+			key  string
+			path string
+		}{
+			{"nvidia-container-cli.path", cfg.NVIDIAContainerCLIConfig.Path},
+			{"nvidia-ctk.path", cfg.NVIDIACTKConfig.Path},
+			{"nvidia-container-runtime-hook.path", cfg.NVIDIAContainerRuntimeHookConfig.Path},
+		}
+		for _, e := range executables {
+			results = append(results, result{
+				name: fmt.Sprintf("%v %v", e.key, e.path),
+				err:  checkExecutable(locator, e.path),
+			})
+		}
+	}
+
+	results = append(results, result{
+		name: "control device nodes in " + opts.devRoot,
+		err:  m.checkControlDeviceNodes(opts.devRoot),
+	})
+
+	return results
+}
+
+// loadConfig loads the config file at the specified path. The file is required
+// to exist.
+func loadConfig(path string) (*config.Config, error) {
+	toml, err := config.New(
+		config.WithConfigFile(path),
+		config.WithRequired(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return toml.Config()
+}
+
+// checkExecutable checks that the specified path resolves to an executable
+// file.
+func checkExecutable(locator lookup.Locator, path string) error {
+	if path == "" {
+		return fmt.Errorf("path is not set")
+	}
+	located, err := locator.Locate(path)
+	if err != nil {
+		return err
+	}
+	if len(located) == 0 {
+		return fmt.Errorf("%v not found", path)
+	}
+	return nil
+}
+
+// checkControlDeviceNodes checks that the NVIDIA control device nodes exist at
+// the specified dev root.
+func (m command) checkControlDeviceNodes(devRoot string) error {
+	nvidiaDevices, err := nvdevices.New(
+		nvdevices.WithLogger(m.logger),
+		nvdevices.WithDevRoot(devRoot),
+		nvdevices.WithDevices(m.devices),
+	)
+	if err != nil {
+		return err
+	}
+	missing, err := nvidiaDevices.MissingNVIDIAControlDevices()
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %v", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// printSummary writes a PASS or FAIL line for each of the results.
+func printSummary(w io.Writer, results []result) error {
+	for _, r := range results {
+		line := fmt.Sprintf("PASS: %v", r.name)
+		if r.err != nil {
+			line = fmt.Sprintf("FAIL: %v: %v", r.name, r.err)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package check
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc/devices"
+)
+
+func TestCheck(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	controlDevices := []string{"nvidiactl", "nvidia-modeset", "nvidia-uvm", "nvidia-uvm-tools"}
+
+	testCases := []struct {
+		description     string
+		config          string
+		executables     map[string]os.FileMode
+		devices         []string
+		expectedSummary []string
+	}{
+		{
+			description: "complete installation",
+			executables: map[string]os.FileMode{
+				"nvidia-container-cli":          0755,
+				"nvidia-ctk":                    0755,
+				"nvidia-container-runtime-hook": 0755,
+			},
+			devices: controlDevices,
+			expectedSummary: []string{
+				"PASS: config {{.toolkitRoot}}/.config/nvidia-container-runtime/config.toml",
+				"PASS: nvidia-container-cli.path {{.toolkitRoot}}/nvidia-container-cli",
+				"PASS: nvidia-ctk.path {{.toolkitRoot}}/nvidia-ctk",
+				"PASS: nvidia-container-runtime-hook.path {{.toolkitRoot}}/nvidia-container-runtime-hook",
+				"PASS: control device nodes in {{.devRoot}}",
+			},
+		},
+		{
+			description: "missing and non-executable files",
+			executables: map[string]os.FileMode{
+				"nvidia-container-cli": 0755,
+				"nvidia-ctk":           0644,
+			},
+			devices: []string{"nvidiactl", "nvidia-modeset", "nvidia-uvm"},
+			expectedSummary: []string{
+				"PASS: config {{.toolkitRoot}}/.config/nvidia-container-runtime/config.toml",
+				"PASS: nvidia-container-cli.path {{.toolkitRoot}}/nvidia-container-cli",
+				"FAIL: nvidia-ctk.path {{.toolkitRoot}}/nvidia-ctk: absolute path {{.toolkitRoot}}/nvidia-ctk is not an executable file",
+				"FAIL: nvidia-container-runtime-hook.path {{.toolkitRoot}}/nvidia-container-runtime-hook: absolute path {{.toolkitRoot}}/nvidia-container-runtime-hook is not an executable file",
+				"FAIL: control device nodes in {{.devRoot}}: missing {{.devRoot}}/dev/nvidia-uvm-tools",
+			},
+		},
+		{
+			description: "invalid config",
+			config:      "nvidia-container-cli = [",
+			devices:     controlDevices,
+			expectedSummary: []string{
+				"FAIL: config {{.toolkitRoot}}/.config/nvidia-container-runtime/config.toml:",
+				"PASS: control device nodes in {{.devRoot}}",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toolkitRoot := t.TempDir()
+			devRoot := t.TempDir()
+
+			config := tc.config
+			if config == "" {
+				config = fmt.Sprintf(`
+[nvidia-container-cli]
+path = "%[1]v/nvidia-container-cli"
+
+[nvidia-ctk]
+path = "%[1]v/nvidia-ctk"
+
+[nvidia-container-runtime-hook]
+path = "%[1]v/nvidia-container-runtime-hook"
+`, toolkitRoot)
+			}
+			configPath := filepath.Join(toolkitRoot, ".config", "nvidia-container-runtime", "config.toml")
+			require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+			require.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+			for name, mode := range tc.executables {
+				require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, name), nil, mode))
+			}
+			require.NoError(t, os.MkdirAll(filepath.Join(devRoot, "dev"), 0755))
+			for _, device := range tc.devices {
+				require.NoError(t, os.WriteFile(filepath.Join(devRoot, "dev", device), nil, 0644))
+			}
+
+			m := command{logger: logger, devices: &devices.DevicesMock{}}
+			results := m.check(&options{toolkitRoot: toolkitRoot, devRoot: devRoot})
+
+			summary := &bytes.Buffer{}
+			require.NoError(t, printSummary(summary, results))
+
+			lines := strings.Split(strings.TrimSuffix(summary.String(), "\n"), "\n")
+			require.Len(t, lines, len(tc.expectedSummary))
+
+			replacer := strings.NewReplacer("{{.toolkitRoot}}", toolkitRoot, "{{.devRoot}}", devRoot)
+			for i, expected := range tc.expectedSummary {
+				require.True(t, strings.HasPrefix(lines[i], replacer.Replace(expected)), "%q does not start with %q", lines[i], expected)
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@
 
 package config
 
+import "strings"
+
 type featureName string
 
 const (
@@ -23,6 +25,11 @@ const (
 	FeatureMOFED    = featureName("mofed")
 	FeatureNVSWITCH = featureName("nvswitch")
 	FeatureGDRCopy  = featureName("gdrcopy")
+	FeatureMPS      = featureName("mps")
+
+	FeatureSELinuxRelabel  = featureName("selinux-relabel")
+	FeatureMIGCaps         = featureName("mig-caps")
+	FeatureDevCharSymlinks = featureName("dev-char-symlinks")
 )
 
 // features specifies a set of named features.
@@ -31,6 +38,11 @@ type features struct {
 	MOFED    *feature `toml:"mofed,omitempty"`
 	NVSWITCH *feature `toml:"nvswitch,omitempty"`
 	GDRCopy  *feature `toml:"gdrcopy,omitempty"`
+	MPS      *feature `toml:"mps,omitempty"`
+
+	SELinuxRelabel  *feature `toml:"selinux-relabel,omitempty"`
+	MIGCaps         *feature `toml:"mig-caps,omitempty"`
+	DevCharSymlinks *feature `toml:"dev-char-symlinks,omitempty"`
 }
 
 type feature bool
@@ -39,25 +51,46 @@ type feature bool
 // An optional list of environments to check for feature-specific environment
 // variables can also be supplied.
 func (fs features) IsEnabled(n featureName, in ...getenver) bool {
-	featureEnvvars := map[featureName]string{
-		FeatureGDS:      "NVIDIA_GDS",
-		FeatureMOFED:    "NVIDIA_MOFED",
-		FeatureNVSWITCH: "NVIDIA_NVSWITCH",
-		FeatureGDRCopy:  "NVIDIA_GDRCOPY",
+	for _, f := range fs.all() {
+		if f.name == n {
+			return f.value.isEnabled(f.envvar, in...)
+		}
 	}
+	return false
+}
 
-	envvar := featureEnvvars[n]
-	switch n {
-	case FeatureGDS:
-		return fs.GDS.isEnabled(envvar, in...)
-	case FeatureMOFED:
-		return fs.MOFED.isEnabled(envvar, in...)
-	case FeatureNVSWITCH:
-		return fs.NVSWITCH.isEnabled(envvar, in...)
-	case FeatureGDRCopy:
-		return fs.GDRCopy.isEnabled(envvar, in...)
-	default:
-		return false
+// AllEnabled returns whether each of the known features is enabled.
+// An optional list of environments to check for feature-specific environment
+// variables can also be supplied.
+func (fs features) AllEnabled(in ...getenver) map[featureName]bool {
+	enabled := make(map[featureName]bool)
+	for _, f := range fs.all() {
+		enabled[f.name] = f.value.isEnabled(f.envvar, in...)
+	}
+	return enabled
+}
+
+// namedFeature associates a named feature with its configured value and the
+// environment variable that can be used to enable it.
+type namedFeature struct {
+	name   featureName
+	value  *feature
+	envvar string
+}
+
+// all returns the known features. A new feature must be added here to be
+// considered by IsEnabled and AllEnabled.
+func (fs features) all() []namedFeature {
+	return []namedFeature{
+		{FeatureGDS, fs.GDS, "NVIDIA_GDS"},
+		{FeatureMOFED, fs.MOFED, "NVIDIA_MOFED"},
+		{FeatureNVSWITCH, fs.NVSWITCH, "NVIDIA_NVSWITCH"},
+		{FeatureGDRCopy, fs.GDRCopy, "NVIDIA_GDRCOPY"},
+		{FeatureMPS, fs.MPS, "NVIDIA_MPS"},
+
+		{FeatureSELinuxRelabel, fs.SELinuxRelabel, "NVIDIA_SELINUX_RELABEL"},
+		{FeatureMIGCaps, fs.MIGCaps, "NVIDIA_MIG_CAPS"},
+		{FeatureDevCharSymlinks, fs.DevCharSymlinks, "NVIDIA_DEV_CHAR_SYMLINKS"},
 	}
 }
 
@@ -65,6 +98,8 @@ func (fs features) IsEnabled(n featureName, in ...getenver) bool {
 // If the enabled value is explicitly set, this is returned, otherwise the
 // associated envvar is checked in the specified getenver for the string "enabled"
 // A CUDA container / image can be passed here.
+// The envvar value is matched ignoring case and surrounding whitespace. Other
+// values such as "true" or "1" do not enable the feature.
 func (f *feature) isEnabled(envvar string, ins ...getenver) bool {
 	if f != nil {
 		return bool(*f)
@@ -73,13 +108,19 @@ func (f *feature) isEnabled(envvar string, ins ...getenver) bool {
 		return false
 	}
 	for _, in := range ins {
-		if in.Getenv(envvar) == "enabled" {
+		if normalizeFeatureEnvvarValue(in.Getenv(envvar)) == "enabled" {
 			return true
 		}
 	}
 	return false
 }
 
+// normalizeFeatureEnvvarValue trims surrounding whitespace from the specified
+// value and converts it to lowercase.
+func normalizeFeatureEnvvarValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
 type getenver interface {
 	Getenv(string) string
 }
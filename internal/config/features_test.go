@@ -0,0 +1,146 @@
+/**
+# Copyright 2024 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv map[string]string
+
+func (e testEnv) Getenv(key string) string {
+	return e[key]
+}
+
+func TestFeatureIsEnabledEnvvar(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{value: "", expected: false},
+		{value: "enabled", expected: true},
+		{value: "Enabled", expected: true},
+		{value: "ENABLED", expected: true},
+		{value: " enabled\t", expected: true},
+		{value: "disabled", expected: false},
+		{value: " true ", expected: false},
+		{value: "1", expected: false},
+		{value: "0", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			var f *feature
+			env := testEnv{"NVIDIA_TEST_FEATURE": tc.value}
+			require.Equal(t, tc.expected, f.isEnabled("NVIDIA_TEST_FEATURE", env))
+		})
+	}
+}
+
+func TestFeaturesAllEnabled(t *testing.T) {
+	enabled := feature(true)
+	disabled := feature(false)
+
+	testCases := []struct {
+		description string
+		features    features
+		env         testEnv
+		expected    map[featureName]bool
+	}{
+		{
+			description: "all features are disabled by default",
+			expected: map[featureName]bool{
+				FeatureGDS:             false,
+				FeatureMOFED:           false,
+				FeatureNVSWITCH:        false,
+				FeatureGDRCopy:         false,
+				FeatureMPS:             false,
+				FeatureSELinuxRelabel:  false,
+				FeatureMIGCaps:         false,
+				FeatureDevCharSymlinks: false,
+			},
+		},
+		{
+			description: "config values are used",
+			features: features{
+				GDS:     &enabled,
+				MIGCaps: &enabled,
+			},
+			expected: map[featureName]bool{
+				FeatureGDS:             true,
+				FeatureMOFED:           false,
+				FeatureNVSWITCH:        false,
+				FeatureGDRCopy:         false,
+				FeatureMPS:             false,
+				FeatureSELinuxRelabel:  false,
+				FeatureMIGCaps:         true,
+				FeatureDevCharSymlinks: false,
+			},
+		},
+		{
+			description: "envvars enable features",
+			env: testEnv{
+				"NVIDIA_MOFED":           "enabled",
+				"NVIDIA_SELINUX_RELABEL": "enabled",
+				"NVIDIA_NVSWITCH":        "disabled",
+				"NVIDIA_MPS":             "enabled",
+			},
+			expected: map[featureName]bool{
+				FeatureGDS:             false,
+				FeatureMOFED:           true,
+				FeatureNVSWITCH:        false,
+				FeatureGDRCopy:         false,
+				FeatureMPS:             true,
+				FeatureSELinuxRelabel:  true,
+				FeatureMIGCaps:         false,
+				FeatureDevCharSymlinks: false,
+			},
+		},
+		{
+			description: "config values take precedence over envvars",
+			features: features{
+				GDRCopy: &disabled,
+			},
+			env: testEnv{
+				"NVIDIA_GDRCOPY": "enabled",
+			},
+			expected: map[featureName]bool{
+				FeatureGDS:             false,
+				FeatureMOFED:           false,
+				FeatureNVSWITCH:        false,
+				FeatureGDRCopy:         false,
+				FeatureMPS:             false,
+				FeatureSELinuxRelabel:  false,
+				FeatureMIGCaps:         false,
+				FeatureDevCharSymlinks: false,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			all := tc.features.AllEnabled(tc.env)
+			require.EqualValues(t, tc.expected, all)
+
+			for name, expected := range tc.expected {
+				require.Equal(t, expected, tc.features.IsEnabled(name, tc.env), name)
+			}
+		})
+	}
+}
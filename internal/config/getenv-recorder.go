@@ -0,0 +1,79 @@
+/**
+# Copyright 2024 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"sort"
+	"sync"
+)
+
+// GetenvRecorder wraps a getenver and records each key that is queried along
+// with the value that was returned. This allows the set of environment
+// variables that were consulted, for example when checking whether features
+// are enabled, to be logged.
+type GetenvRecorder struct {
+	in getenver
+
+	lock      sync.Mutex
+	consulted map[string]string
+}
+
+// NewGetenvRecorder creates a recorder for the specified getenver.
+func NewGetenvRecorder(in getenver) *GetenvRecorder {
+	return &GetenvRecorder{
+		in:        in,
+		consulted: make(map[string]string),
+	}
+}
+
+// Getenv returns the value of the specified key from the wrapped getenver and
+// records that the key was queried.
+func (r *GetenvRecorder) Getenv(key string) string {
+	value := r.in.Getenv(key)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.consulted[key] = value
+
+	return value
+}
+
+// Consulted returns a copy of the keys that have been queried along with their
+// values.
+func (r *GetenvRecorder) Consulted() map[string]string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	consulted := make(map[string]string, len(r.consulted))
+	for k, v := range r.consulted {
+		consulted[k] = v
+	}
+	return consulted
+}
+
+// Keys returns the sorted list of keys that have been queried.
+func (r *GetenvRecorder) Keys() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var keys []string
+	for k := range r.consulted {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
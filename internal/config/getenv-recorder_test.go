@@ -0,0 +1,102 @@
+/**
+# Copyright 2024 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetenvRecorder(t *testing.T) {
+	enabled := feature(true)
+
+	testCases := []struct {
+		description       string
+		features          features
+		env               testEnv
+		names             []featureName
+		expectedConsulted map[string]string
+	}{
+		{
+			description:       "no features evaluated",
+			expectedConsulted: map[string]string{},
+		},
+		{
+			description: "evaluated features are recorded",
+			env: testEnv{
+				"NVIDIA_GDS":   "enabled",
+				"NVIDIA_MOFED": "disabled",
+				"OTHER":        "value",
+			},
+			names: []featureName{FeatureGDS, FeatureMOFED, FeatureMPS},
+			expectedConsulted: map[string]string{
+				"NVIDIA_GDS":   "enabled",
+				"NVIDIA_MOFED": "disabled",
+				"NVIDIA_MPS":   "",
+			},
+		},
+		{
+			description: "features set in the config do not consult the envvar",
+			features: features{
+				GDS: &enabled,
+			},
+			env: testEnv{
+				"NVIDIA_GDS": "enabled",
+			},
+			names: []featureName{FeatureGDS, FeatureGDRCopy},
+			expectedConsulted: map[string]string{
+				"NVIDIA_GDRCOPY": "",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			recorder := NewGetenvRecorder(tc.env)
+			for _, name := range tc.names {
+				tc.features.IsEnabled(name, recorder)
+			}
+
+			require.EqualValues(t, tc.expectedConsulted, recorder.Consulted())
+
+			var expectedKeys []string
+			for k := range tc.expectedConsulted {
+				expectedKeys = append(expectedKeys, k)
+			}
+			require.ElementsMatch(t, expectedKeys, recorder.Keys())
+		})
+	}
+}
+
+func TestGetenvRecorderAllEnabled(t *testing.T) {
+	recorder := NewGetenvRecorder(testEnv{"NVIDIA_MIG_CAPS": "enabled"})
+
+	all := features{}.AllEnabled(recorder)
+	require.True(t, all[FeatureMIGCaps])
+
+	require.Equal(t, []string{
+		"NVIDIA_DEV_CHAR_SYMLINKS",
+		"NVIDIA_GDRCOPY",
+		"NVIDIA_GDS",
+		"NVIDIA_MIG_CAPS",
+		"NVIDIA_MOFED",
+		"NVIDIA_MPS",
+		"NVIDIA_NVSWITCH",
+		"NVIDIA_SELINUX_RELABEL",
+	}, recorder.Keys())
+}
@@ -25,6 +25,20 @@ type RuntimeConfig struct {
 	Runtimes []string    `toml:"runtimes"`
 	Mode     string      `toml:"mode"`
 	Modes    modesConfig `toml:"modes"`
+	// DeviceNodeMode defines the octal permissions (e.g. 0666) that are set
+	// on the NVIDIA device nodes injected into a container. If this is empty,
+	// the permissions of the device nodes are not modified.
+	DeviceNodeMode string `toml:"device-node-mode,omitempty"`
+	// MPS defines the host directories of the NVIDIA MPS control daemon that
+	// are mounted into a container when the mps feature is enabled.
+	MPS MPSConfig `toml:"mps,omitempty"`
+}
+
+// MPSConfig defines the directories used by the NVIDIA MPS control daemon.
+// If a directory is empty, the default is used.
+type MPSConfig struct {
+	PipeDirectory string `toml:"pipe-directory,omitempty"`
+	LogDirectory  string `toml:"log-directory,omitempty"`
 }
 
 // modesConfig defines (optional) per-mode configs
@@ -44,6 +58,15 @@ type cdiModeConfig struct {
 
 type csvModeConfig struct {
 	MountSpecPath string `toml:"mount-spec-path"`
+	// LDCacheUpdateMode defines how the injected libraries are made available
+	// to the dynamic linker. This is one of ldconfig (the default) or env. In
+	// env mode, the library folders are appended to LD_LIBRARY_PATH instead
+	// of updating the ldcache, which allows for read-only container roots.
+	LDCacheUpdateMode string `toml:"ldcache-update-mode,omitempty"`
+	// LDCacheUpdateHookLifecycle defines the lifecycle at which the ldcache
+	// update hook is run. This is one of createContainer (the default) or
+	// startContainer.
+	LDCacheUpdateHookLifecycle string `toml:"ldcache-update-hook-lifecycle,omitempty"`
 }
 
 // GetDefaultRuntimeConfig defines the default values for the config
@@ -0,0 +1,143 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// DefaultSELinuxDeviceLabel is the SELinux label applied to device nodes in
+// the container if no label is specified.
+const DefaultSELinuxDeviceLabel = "system_u:object_r:container_file_t:s0"
+
+// deviceHook is a discoverer that generates an nvidia-cdi-hook subcommand that
+// is applied to the device nodes discovered by the wrapped discoverer. The
+// paths of the device nodes are passed to the hook using the --path flag.
+type deviceHook struct {
+	None
+	logger            logger.Interface
+	nvidiaCDIHookPath string
+	hookName          string
+	args              []string
+	devicesFrom       Discover
+}
+
+// NewSELinuxRelabelHook creates a discoverer that relabels the device nodes
+// discovered by the specified discoverer in the container.
+func NewSELinuxRelabelHook(logger logger.Interface, devices Discover, nvidiaCDIHookPath string, label string) Discover {
+	if label == "" {
+		label = DefaultSELinuxDeviceLabel
+	}
+	return &deviceHook{
+		logger:            logger,
+		nvidiaCDIHookPath: nvidiaCDIHookPath,
+		hookName:          "relabel-devices",
+		args:              []string{"--label", label},
+		devicesFrom:       devices,
+	}
+}
+
+// NewDevCharSymlinksHook creates a discoverer that creates /dev/char symlinks
+// for the device nodes discovered by the specified discoverer in the
+// container.
+func NewDevCharSymlinksHook(logger logger.Interface, devices Discover, nvidiaCDIHookPath string) Discover {
+	return &deviceHook{
+		logger:            logger,
+		nvidiaCDIHookPath: nvidiaCDIHookPath,
+		hookName:          "create-dev-char-symlinks",
+		devicesFrom:       devices,
+	}
+}
+
+// NewChmodDevicesHook creates a discoverer that sets the permissions of the
+// device nodes discovered by the specified discoverer in the container to the
+// specified octal mode. If the mode is empty, no hooks are generated.
+func NewChmodDevicesHook(logger logger.Interface, devices Discover, nvidiaCDIHookPath string, mode string) (Discover, error) {
+	if mode == "" {
+		return None{}, nil
+	}
+	modeInt, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mode %q as octal: %v", mode, err)
+	}
+	if modeInt&^uint64(fs.ModePerm) != 0 {
+		return nil, fmt.Errorf("invalid mode %v: only permission bits may be set", mode)
+	}
+	d := &deviceHook{
+		logger:            logger,
+		nvidiaCDIHookPath: nvidiaCDIHookPath,
+		hookName:          "chmod-devices",
+		args:              []string{"--mode", mode},
+		devicesFrom:       devices,
+	}
+	return d, nil
+}
+
+// Hooks returns a hook that is applied to the discovered device nodes.
+func (d deviceHook) Hooks() ([]Hook, error) {
+	devices, err := d.devicesFrom.Devices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover devices for %v hook: %v", d.hookName, err)
+	}
+	if len(devices) == 0 {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, device := range devices {
+		paths = append(paths, device.Path)
+	}
+	h := createDeviceHook(d.nvidiaCDIHookPath, d.hookName, d.args, paths)
+	return []Hook{h}, nil
+}
+
+// CreateRelabelDevicesHook creates a hook that sets the SELinux label of the
+// specified device nodes in the container.
+func CreateRelabelDevicesHook(nvidiaCDIHookPath string, label string, devicePaths []string) Hook {
+	return createDeviceHook(nvidiaCDIHookPath, "relabel-devices", []string{"--label", label}, devicePaths)
+}
+
+// CreateDevCharSymlinksHook creates a hook that creates the
+// /dev/char/<major>:<minor> symlinks for the specified device nodes in the
+// container.
+func CreateDevCharSymlinksHook(nvidiaCDIHookPath string, devicePaths []string) Hook {
+	return createDeviceHook(nvidiaCDIHookPath, "create-dev-char-symlinks", nil, devicePaths)
+}
+
+// CreateChmodDevicesHook creates a hook that sets the permissions of the
+// specified device nodes in the container.
+func CreateChmodDevicesHook(nvidiaCDIHookPath string, mode string, devicePaths []string) Hook {
+	return createDeviceHook(nvidiaCDIHookPath, "chmod-devices", []string{"--mode", mode}, devicePaths)
+}
+
+// createDeviceHook creates the specified nvidia-cdi-hook subcommand with the
+// specified arguments followed by a --path flag for each device path.
+func createDeviceHook(nvidiaCDIHookPath string, hookName string, args []string, devicePaths []string) Hook {
+	args = append([]string{}, args...)
+	for _, path := range devicePaths {
+		args = append(args, "--path", path)
+	}
+	return CreateNvidiaCDIHook(
+		nvidiaCDIHookPath,
+		hookName,
+		args...,
+	)
+}
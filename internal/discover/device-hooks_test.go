@@ -0,0 +1,199 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceHooks(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	relabel := func(label string) func(Discover) (Discover, error) {
+		return func(devices Discover) (Discover, error) {
+			return NewSELinuxRelabelHook(logger, devices, testNvidiaCDIHookPath, label), nil
+		}
+	}
+	devChar := func(devices Discover) (Discover, error) {
+		return NewDevCharSymlinksHook(logger, devices, testNvidiaCDIHookPath), nil
+	}
+	chmod := func(mode string) func(Discover) (Discover, error) {
+		return func(devices Discover) (Discover, error) {
+			return NewChmodDevicesHook(logger, devices, testNvidiaCDIHookPath, mode)
+		}
+	}
+
+	devices := []Device{
+		{Path: "/dev/nvidiactl", HostPath: "/host/dev/nvidiactl"},
+		{Path: "/dev/nvidia0"},
+	}
+
+	testCases := []struct {
+		description        string
+		newDiscoverer      func(Discover) (Discover, error)
+		devices            []Device
+		devicesError       error
+		expectedError      bool
+		expectedHooksError error
+		expectedHooks      []Hook
+	}{
+		{
+			description:   "relabel-devices: no devices returns no hooks",
+			newDiscoverer: relabel(""),
+		},
+		{
+			description:        "relabel-devices: devices error is returned",
+			newDiscoverer:      relabel(""),
+			devicesError:       fmt.Errorf("devicesError"),
+			expectedHooksError: fmt.Errorf("devicesError"),
+		},
+		{
+			description:   "relabel-devices: default label and device paths are added to args",
+			newDiscoverer: relabel(""),
+			devices:       devices,
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args: []string{"nvidia-cdi-hook", "relabel-devices",
+						"--label", DefaultSELinuxDeviceLabel,
+						"--path", "/dev/nvidiactl",
+						"--path", "/dev/nvidia0",
+					},
+				},
+			},
+		},
+		{
+			description:   "relabel-devices: label is added to args",
+			newDiscoverer: relabel("system_u:object_r:container_file_t:s0:c1,c2"),
+			devices:       []Device{{Path: "/dev/nvidia-uvm"}},
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args: []string{"nvidia-cdi-hook", "relabel-devices",
+						"--label", "system_u:object_r:container_file_t:s0:c1,c2",
+						"--path", "/dev/nvidia-uvm",
+					},
+				},
+			},
+		},
+		{
+			description:   "create-dev-char-symlinks: no devices returns no hooks",
+			newDiscoverer: devChar,
+		},
+		{
+			description:        "create-dev-char-symlinks: devices error is returned",
+			newDiscoverer:      devChar,
+			devicesError:       fmt.Errorf("devicesError"),
+			expectedHooksError: fmt.Errorf("devicesError"),
+		},
+		{
+			description:   "create-dev-char-symlinks: device paths are added to args",
+			newDiscoverer: devChar,
+			devices:       append(devices, Device{Path: "/dev/nvidia-caps/nvidia-cap1"}),
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args: []string{"nvidia-cdi-hook", "create-dev-char-symlinks",
+						"--path", "/dev/nvidiactl",
+						"--path", "/dev/nvidia0",
+						"--path", "/dev/nvidia-caps/nvidia-cap1",
+					},
+				},
+			},
+		},
+		{
+			description:   "chmod-devices: empty mode returns no hooks",
+			newDiscoverer: chmod(""),
+			devices:       devices,
+		},
+		{
+			description:   "chmod-devices: no devices returns no hooks",
+			newDiscoverer: chmod("0666"),
+		},
+		{
+			description:        "chmod-devices: devices error is returned",
+			newDiscoverer:      chmod("0666"),
+			devicesError:       fmt.Errorf("devicesError"),
+			expectedHooksError: fmt.Errorf("devicesError"),
+		},
+		{
+			description:   "chmod-devices: invalid mode is an error",
+			newDiscoverer: chmod("rw-rw-rw-"),
+			expectedError: true,
+		},
+		{
+			description:   "chmod-devices: mode with non-permission bits is an error",
+			newDiscoverer: chmod("4755"),
+			expectedError: true,
+		},
+		{
+			description:   "chmod-devices: mode and device paths are added to args",
+			newDiscoverer: chmod("0666"),
+			devices:       devices,
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args: []string{"nvidia-cdi-hook", "chmod-devices",
+						"--mode", "0666",
+						"--path", "/dev/nvidiactl",
+						"--path", "/dev/nvidia0",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			devices := &DiscoverMock{
+				DevicesFunc: func() ([]Device, error) {
+					return tc.devices, tc.devicesError
+				},
+			}
+			d, err := tc.newDiscoverer(devices)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			hooks, err := d.Hooks()
+			if tc.expectedHooksError != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedHooks == nil {
+				require.Empty(t, hooks)
+			} else {
+				require.EqualValues(t, tc.expectedHooks, hooks)
+			}
+
+			devs, err := d.Devices()
+			require.NoError(t, err)
+			require.Empty(t, devs)
+		})
+	}
+}
@@ -27,7 +27,6 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info/proc"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/cuda"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 )
 
@@ -200,19 +199,16 @@ func (d graphicsDriverLibraries) isDriverLibrary(filename string, libraryName st
 // If the library cannot be located an empty root is returned.
 // If the version string cannot be extracted, the generic *.* pattern is returned.
 func getCUDALibRootAndVersionPattern(logger logger.Interface, driver *root.Driver) (string, string) {
-	libCudaPaths, err := cuda.New(
-		driver.Libraries(),
-	).Locate(".*.*")
+	libcudaPath, err := driver.VersionLibraryPath()
 	if err != nil {
 		logger.Warningf("failed to locate libcuda.so: %v; using *.*", err)
 		return "", "*.*"
 	}
-	libcudaPath := libCudaPaths[0]
 
 	libRoot := filepath.Dir(libcudaPath)
-	version := strings.TrimPrefix(filepath.Base(libcudaPath), "libcuda.so.")
-	if version == "" {
-		logger.Warningf("failed to extract version from %v; using *.*", libcudaPath)
+	version, err := driver.Version()
+	if err != nil {
+		logger.Warningf("%v; using *.*", err)
 		version = "*.*"
 	}
 
@@ -29,6 +29,11 @@ func (h Hook) Devices() ([]Device, error) {
 	return nil, nil
 }
 
+// EnvVars returns an empty list of environment variables for a Hook discoverer.
+func (h Hook) EnvVars() ([]EnvVar, error) {
+	return nil, nil
+}
+
 // Mounts returns an empty list of mounts for a Hook discoverer.
 func (h Hook) Mounts() ([]Mount, error) {
 	return nil, nil
@@ -57,6 +62,24 @@ func CreateCreateSymlinkHook(nvidiaCDIHookPath string, links []string) Discover
 	)
 }
 
+// CreateVerifyMountsHook creates a hook which verifies that the container paths
+// of the specified mounts exist in the container.
+func CreateVerifyMountsHook(nvidiaCDIHookPath string, mounts []Mount) Discover {
+	if len(mounts) == 0 {
+		return None{}
+	}
+
+	var args []string
+	for _, m := range mounts {
+		args = append(args, "--path", m.Path)
+	}
+	return CreateNvidiaCDIHook(
+		nvidiaCDIHookPath,
+		"verify-mounts",
+		args...,
+	)
+}
+
 // CreateNvidiaCDIHook creates a hook which invokes the NVIDIA Container CLI hook subcommand.
 func CreateNvidiaCDIHook(nvidiaCDIHookPath string, hookName string, additionalArgs ...string) Hook {
 	return cdiHook(nvidiaCDIHookPath).Create(hookName, additionalArgs...)
@@ -73,8 +96,31 @@ func (c cdiHook) Create(name string, args ...string) Hook {
 }
 func (c cdiHook) requiredArgs(name string) []string {
 	base := filepath.Base(string(c))
-	if base == "nvidia-ctk" {
+	if c.isNvidiaCTK() {
 		return []string{base, "hook", name}
 	}
 	return []string{base, name}
 }
+
+// isNvidiaCTK checks whether hooks are invoked as subcommands of the
+// nvidia-ctk hook command instead of the nvidia-cdi-hook executable.
+func (c cdiHook) isNvidiaCTK() bool {
+	return filepath.Base(string(c)) == "nvidia-ctk"
+}
+
+// Subcommand returns the subcommand invoked by the hook. This is the first
+// argument after the executable, or the argument after "hook" for hooks that
+// are invoked through nvidia-ctk. An empty string is returned if the hook
+// does not specify a subcommand.
+func (h Hook) Subcommand() string {
+	if cdiHook(h.Path).isNvidiaCTK() {
+		if len(h.Args) < 3 || h.Args[1] != "hook" {
+			return ""
+		}
+		return h.Args[2]
+	}
+	if len(h.Args) < 2 {
+		return ""
+	}
+	return h.Args[1]
+}
@@ -25,20 +25,8 @@ type ipcMounts mounts
 
 // NewIPCDiscoverer creats a discoverer for NVIDIA IPC sockets.
 func NewIPCDiscoverer(logger logger.Interface, driverRoot string) (Discover, error) {
-	sockets := newMounts(
-		logger,
-		lookup.NewFileLocator(
-			lookup.WithLogger(logger),
-			lookup.WithRoot(driverRoot),
-			lookup.WithSearchPaths("/run", "/var/run"),
-			lookup.WithCount(1),
-		),
-		driverRoot,
-		[]string{
-			"/nvidia-persistenced/socket",
-			"/nvidia-fabricmanager/socket",
-		},
-	)
+	persistenced := NewPersistencedSocketDiscoverer(logger, driverRoot)
+	fabricmanager := newSocketMounts(logger, driverRoot, "/nvidia-fabricmanager/socket")
 
 	mps := newMounts(
 		logger,
@@ -54,7 +42,8 @@ func NewIPCDiscoverer(logger logger.Interface, driverRoot string) (Discover, err
 	)
 
 	d := Merge(
-		(*ipcMounts)(sockets),
+		persistenced,
+		fabricmanager,
 		(*ipcMounts)(mps),
 	)
 	return d, nil
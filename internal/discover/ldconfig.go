@@ -18,19 +18,58 @@ package discover
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
+// LDCacheUpdateMode defines how the folders of the discovered libraries are
+// made available to the dynamic linker in the container.
+type LDCacheUpdateMode string
+
+const (
+	// LDCacheUpdateModeLdconfig runs ldconfig in the container using an
+	// update-ldcache hook.
+	LDCacheUpdateModeLdconfig = LDCacheUpdateMode("ldconfig")
+	// LDCacheUpdateModeEnv sets LD_LIBRARY_PATH to the container paths of the
+	// library folders instead of updating the ldcache. This is useful if the
+	// container root is read-only. When the edits are applied by the NVIDIA
+	// Container Runtime, the folders are appended to any LD_LIBRARY_PATH
+	// defined by the image. Other CDI-enabled runtimes replace the value.
+	LDCacheUpdateModeEnv = LDCacheUpdateMode("env")
+)
+
 // NewLDCacheUpdateHook creates a discoverer that updates the ldcache for the specified mounts. A logger can also be specified
-func NewLDCacheUpdateHook(logger logger.Interface, mounts Discover, nvidiaCDIHookPath, ldconfigPath string) (Discover, error) {
+func NewLDCacheUpdateHook(logger logger.Interface, mounts Discover, nvidiaCDIHookPath, ldconfigPath string, opts ...LDCacheUpdateHookOption) (Discover, error) {
 	d := ldconfig{
 		logger:            logger,
 		nvidiaCDIHookPath: nvidiaCDIHookPath,
 		ldconfigPath:      ldconfigPath,
 		mountsFrom:        mounts,
+		mode:              LDCacheUpdateModeLdconfig,
+		lifecycle:         cdi.CreateContainerHook,
+	}
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	switch d.mode {
+	case LDCacheUpdateModeLdconfig, LDCacheUpdateModeEnv:
+	default:
+		return nil, fmt.Errorf("invalid ldcache update mode %q", d.mode)
+	}
+
+	// The update-ldcache hook operates on the container root and must be run
+	// in the container namespace. This is also required for the container
+	// paths in its arguments to be transformed correctly.
+	switch d.lifecycle {
+	case cdi.CreateContainerHook, cdi.StartContainerHook:
+	default:
+		return nil, fmt.Errorf("unsupported ldcache update hook lifecycle %q; expected %v or %v", d.lifecycle, cdi.CreateContainerHook, cdi.StartContainerHook)
 	}
 
 	return &d, nil
@@ -42,31 +81,179 @@ type ldconfig struct {
 	nvidiaCDIHookPath string
 	ldconfigPath      string
 	mountsFrom        Discover
+	mode              LDCacheUpdateMode
+	lifecycle         string
+
+	includedLibraries []string
+	excludedLibraries []string
+	driverRoot        string
+	driverFolders     []string
+}
+
+// LDCacheUpdateHookOption defines a function for passing options to the NewLDCacheUpdateHook call.
+type LDCacheUpdateHookOption func(*ldconfig)
+
+// WithIncludedLibraries sets the patterns of library names that are considered
+// when determining the folders for the ldcache update. If no patterns are
+// specified, all libraries are considered.
+func WithIncludedLibraries(patterns ...string) LDCacheUpdateHookOption {
+	return func(l *ldconfig) {
+		l.includedLibraries = patterns
+	}
+}
+
+// WithExcludedLibraries sets the patterns of library names that are not
+// considered when determining the folders for the ldcache update.
+func WithExcludedLibraries(patterns ...string) LDCacheUpdateHookOption {
+	return func(l *ldconfig) {
+		l.excludedLibraries = patterns
+	}
+}
+
+// WithDriverFolders sets candidate folders, relative to the specified driver
+// root, that are included in the ldcache update in addition to the folders of
+// the discovered libraries. A candidate folder is only included if the folder
+// in the driver root contains one of the discovered libraries. This allows,
+// for example, the folders declared in the ld.so.conf.d files of a driver root
+// to be included without adding unrelated library folders.
+func WithDriverFolders(driverRoot string, folders ...string) LDCacheUpdateHookOption {
+	return func(l *ldconfig) {
+		l.driverRoot = driverRoot
+		l.driverFolders = folders
+	}
+}
+
+// WithLDCacheUpdateMode sets the mode used to make the discovered libraries
+// available in the container. If this is not specified, the ldcache is
+// updated using ldconfig.
+func WithLDCacheUpdateMode(mode LDCacheUpdateMode) LDCacheUpdateHookOption {
+	return func(l *ldconfig) {
+		l.mode = mode
+	}
+}
+
+// WithHookLifecycle sets the lifecycle at which the ldcache update hook is run.
+// This must be createContainer or startContainer. If this is not specified, the
+// hook is run as a createContainer hook.
+func WithHookLifecycle(lifecycle string) LDCacheUpdateHookOption {
+	return func(l *ldconfig) {
+		l.lifecycle = lifecycle
+	}
+}
+
+// EnvVars returns LD_LIBRARY_PATH set to the discovered library folders if the
+// env mode is selected.
+func (d ldconfig) EnvVars() ([]EnvVar, error) {
+	if d.mode != LDCacheUpdateModeEnv {
+		return nil, nil
+	}
+	folders, err := d.getFolders()
+	if err != nil {
+		return nil, err
+	}
+	if len(folders) == 0 {
+		return nil, nil
+	}
+	e := EnvVar{
+		Name:  "LD_LIBRARY_PATH",
+		Value: strings.Join(folders, ":"),
+	}
+	return []EnvVar{e}, nil
 }
 
 // Hooks checks the required mounts for libraries and returns a hook to update the LDcache for the discovered paths.
+// No hooks are returned if the env mode is selected or if there are no library folders to update.
 func (d ldconfig) Hooks() ([]Hook, error) {
-	mounts, err := d.mountsFrom.Mounts()
+	if d.mode == LDCacheUpdateModeEnv {
+		return nil, nil
+	}
+	folders, err := d.getFolders()
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover mounts for ldcache update: %v", err)
+		return nil, err
+	}
+	if len(folders) == 0 {
+		d.logger.Debugf("No library folders discovered; skipping ldcache update")
+		return nil, nil
 	}
-	h := CreateLDCacheUpdateHook(
+	h := createLDCacheUpdateHookForFolders(
 		d.nvidiaCDIHookPath,
 		d.ldconfigPath,
-		getLibraryPaths(mounts),
+		folders,
 	)
+	h.Lifecycle = d.lifecycle
 	return []Hook{h}, nil
 }
 
+// getFolders returns the unique folders of the discovered libraries followed
+// by the additional folders.
+func (d ldconfig) getFolders() ([]string, error) {
+	mounts, err := d.mountsFrom.Mounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover mounts for ldcache update: %v", err)
+	}
+	libraries := d.getLibraryMounts(mounts)
+
+	var paths []string
+	for _, m := range libraries {
+		paths = append(paths, m.Path)
+	}
+	folders := uniqueFolders(paths)
+	seen := make(map[string]bool)
+	for _, folder := range folders {
+		seen[folder] = true
+	}
+	for _, folder := range d.driverFolders {
+		if seen[folder] {
+			continue
+		}
+		if !containsLibrary(filepath.Join(d.driverRoot, folder), libraries) {
+			d.logger.Debugf("Ignoring folder %v for ldcache update: no discovered libraries in %v", folder, d.driverRoot)
+			continue
+		}
+		seen[folder] = true
+		folders = append(folders, folder)
+	}
+	return folders, nil
+}
+
+// containsLibrary checks whether the specified host directory contains one of
+// the specified library mounts, either directly or as a link to the library.
+func containsLibrary(dir string, libraries []Mount) bool {
+	for _, m := range libraries {
+		candidate := filepath.Join(dir, filepath.Base(m.HostPath))
+		if candidate == m.HostPath {
+			return true
+		}
+		candidateInfo, err := os.Stat(candidate)
+		if err != nil {
+			continue
+		}
+		libraryInfo, err := os.Stat(m.HostPath)
+		if err != nil {
+			continue
+		}
+		if os.SameFile(candidateInfo, libraryInfo) {
+			return true
+		}
+	}
+	return false
+}
+
 // CreateLDCacheUpdateHook locates the NVIDIA Container Toolkit CLI and creates a hook for updating the LD Cache
 func CreateLDCacheUpdateHook(executable string, ldconfig string, libraries []string) Hook {
+	return createLDCacheUpdateHookForFolders(executable, ldconfig, uniqueFolders(libraries))
+}
+
+// createLDCacheUpdateHookForFolders creates a hook for updating the LD Cache
+// with the specified folders.
+func createLDCacheUpdateHookForFolders(executable string, ldconfig string, folders []string) Hook {
 	var args []string
 
 	if ldconfig != "" {
 		args = append(args, "--ldconfig-path", ldconfig)
 	}
 
-	for _, f := range uniqueFolders(libraries) {
+	for _, f := range folders {
 		args = append(args, "--folder", f)
 	}
 
@@ -79,16 +266,45 @@ func CreateLDCacheUpdateHook(executable string, ldconfig string, libraries []str
 	return hook
 }
 
-// getLibraryPaths extracts the library dirs from the specified mounts
-func getLibraryPaths(mounts []Mount) []string {
-	var paths []string
+// getLibraryMounts returns the mounts for libraries from the specified mounts.
+// Only libraries that match the configured include and exclude patterns are
+// considered.
+func (d ldconfig) getLibraryMounts(mounts []Mount) []Mount {
+	var libraries []Mount
 	for _, m := range mounts {
 		if !isLibName(m.Path) {
 			continue
 		}
-		paths = append(paths, m.Path)
+		if !d.isSelected(m.Path) {
+			d.logger.Debugf("Ignoring library %v for ldcache update", m.Path)
+			continue
+		}
+		libraries = append(libraries, m)
 	}
-	return paths
+	return libraries
+}
+
+// isSelected checks whether the specified library matches the include and
+// exclude patterns.
+func (d ldconfig) isSelected(library string) bool {
+	base := filepath.Base(library)
+	if matchesAny(base, d.excludedLibraries) {
+		return false
+	}
+	if len(d.includedLibraries) == 0 {
+		return true
+	}
+	return matchesAny(base, d.includedLibraries)
+}
+
+// matchesAny checks whether the specified name matches any of the patterns.
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if match, _ := filepath.Match(pattern, name); match {
+			return true
+		}
+	}
+	return false
 }
 
 // isLibName checks if the specified filename is a library (i.e. ends in `.so*`)
@@ -18,6 +18,8 @@ package discover
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	testlog "github.com/sirupsen/logrus/hooks/test"
@@ -35,14 +37,25 @@ func TestLDCacheUpdateHook(t *testing.T) {
 	testCases := []struct {
 		description   string
 		ldconfigPath  string
+		options       []LDCacheUpdateHookOption
 		mounts        []Mount
 		mountError    error
 		expectedError error
 		expectedArgs  []string
 	}{
 		{
-			description:  "empty mounts",
-			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache"},
+			description: "empty mounts returns no hook",
+		},
+		{
+			description: "mounts without libraries returns no hook",
+			mounts: []Mount{
+				{
+					Path: "/usr/bin/notlib",
+				},
+				{
+					Path: "/usr/local/lib/notlib.txt",
+				},
+			},
 		},
 		{
 			description:   "mount error",
@@ -77,10 +90,60 @@ func TestLDCacheUpdateHook(t *testing.T) {
 			},
 			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib"},
 		},
+		{
+			description: "excluded libraries are ignored",
+			options:     []LDCacheUpdateHookOption{WithExcludedLibraries("libother*.so*")},
+			mounts: []Mount{
+				{
+					Path: "/usr/local/lib/libcuda.so",
+				},
+				{
+					Path: "/usr/local/libother/libother.so.1",
+				},
+			},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib"},
+		},
+		{
+			description: "only included libraries are considered",
+			options:     []LDCacheUpdateHookOption{WithIncludedLibraries("libcuda.so*", "libnvidia-*.so*")},
+			mounts: []Mount{
+				{
+					Path: "/usr/local/lib/libcuda.so",
+				},
+				{
+					Path: "/usr/local/nvidia/libnvidia-ml.so.1",
+				},
+				{
+					Path: "/usr/local/libother/libfoo.so",
+				},
+			},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib", "--folder", "/usr/local/nvidia"},
+		},
+		{
+			description: "exclude takes precedence over include",
+			options: []LDCacheUpdateHookOption{
+				WithIncludedLibraries("libnvidia-*.so*"),
+				WithExcludedLibraries("libnvidia-gl*.so*"),
+			},
+			mounts: []Mount{
+				{
+					Path: "/usr/local/nvidia/libnvidia-ml.so.1",
+				},
+				{
+					Path: "/usr/local/gl/libnvidia-glcore.so.1",
+				},
+			},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/nvidia"},
+		},
 		{
 			description:  "explicit ldconfig path is passed",
 			ldconfigPath: testLdconfigPath,
-			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--ldconfig-path", testLdconfigPath},
+			mounts: []Mount{
+				{
+					Path: "/usr/local/lib/libfoo.so",
+				},
+			},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--ldconfig-path", testLdconfigPath, "--folder", "/usr/local/lib"},
 		},
 	}
 
@@ -97,7 +160,7 @@ func TestLDCacheUpdateHook(t *testing.T) {
 				Lifecycle: "createContainer",
 			}
 
-			d, err := NewLDCacheUpdateHook(logger, mountMock, testNvidiaCDIHookPath, tc.ldconfigPath)
+			d, err := NewLDCacheUpdateHook(logger, mountMock, testNvidiaCDIHookPath, tc.ldconfigPath, tc.options...)
 			require.NoError(t, err)
 
 			hooks, err := d.Hooks()
@@ -110,9 +173,12 @@ func TestLDCacheUpdateHook(t *testing.T) {
 			}
 
 			require.NoError(t, err)
-			require.Len(t, hooks, 1)
-
-			require.EqualValues(t, hooks[0], expectedHook)
+			if tc.expectedArgs == nil {
+				require.Empty(t, hooks)
+			} else {
+				require.Len(t, hooks, 1)
+				require.EqualValues(t, hooks[0], expectedHook)
+			}
 
 			devices, err := d.Devices()
 			require.NoError(t, err)
@@ -125,6 +191,214 @@ func TestLDCacheUpdateHook(t *testing.T) {
 	}
 }
 
+func TestLDCacheUpdateHookDriverFolders(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	for _, dir := range []string{"usr/lib64", "opt/nvidia/lib", "usr/local/lib"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, dir), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(driverRoot, "usr/lib64/libcuda.so.1"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(driverRoot, "usr/local/lib/libfoo.so.1"), nil, 0644))
+	require.NoError(t, os.Symlink("../../../usr/lib64/libcuda.so.1", filepath.Join(driverRoot, "opt/nvidia/lib/libcuda.so.1")))
+
+	mounts := []Mount{
+		{
+			HostPath: filepath.Join(driverRoot, "usr/lib64/libcuda.so.1"),
+			Path:     "/usr/lib64/libcuda.so.1",
+		},
+	}
+
+	testCases := []struct {
+		description  string
+		folders      []string
+		expectedArgs []string
+	}{
+		{
+			description:  "no driver folders",
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+		},
+		{
+			description:  "folder with a link to a discovered library is included",
+			folders:      []string{"/opt/nvidia/lib"},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--folder", "/opt/nvidia/lib"},
+		},
+		{
+			description:  "folder without discovered libraries is ignored",
+			folders:      []string{"/usr/local/lib", "/missing"},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+		},
+		{
+			description:  "library folders are not duplicated",
+			folders:      []string{"/usr/lib64"},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mountMock := &DiscoverMock{
+				MountsFunc: func() ([]Mount, error) {
+					return mounts, nil
+				},
+			}
+
+			d, err := NewLDCacheUpdateHook(logger, mountMock, testNvidiaCDIHookPath, "",
+				WithDriverFolders(driverRoot, tc.folders...),
+			)
+			require.NoError(t, err)
+
+			hooks, err := d.Hooks()
+			require.NoError(t, err)
+			require.Len(t, hooks, 1)
+			require.EqualValues(t, tc.expectedArgs, hooks[0].Args)
+		})
+	}
+}
+
+func TestLDCacheUpdateMode(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	mounts := []Mount{
+		{
+			Path: "/usr/local/lib/libfoo.so",
+		},
+		{
+			Path: "/usr/local/libother/libbar.so",
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		options         []LDCacheUpdateHookOption
+		expectedError   bool
+		expectedHooks   []Hook
+		expectedEnvVars []EnvVar
+	}{
+		{
+			description: "ldconfig mode is the default",
+			expectedHooks: []Hook{
+				{
+					Path:      testNvidiaCDIHookPath,
+					Args:      []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib", "--folder", "/usr/local/libother"},
+					Lifecycle: "createContainer",
+				},
+			},
+		},
+		{
+			description: "ldconfig mode returns a hook",
+			options:     []LDCacheUpdateHookOption{WithLDCacheUpdateMode(LDCacheUpdateModeLdconfig)},
+			expectedHooks: []Hook{
+				{
+					Path:      testNvidiaCDIHookPath,
+					Args:      []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/local/lib", "--folder", "/usr/local/libother"},
+					Lifecycle: "createContainer",
+				},
+			},
+		},
+		{
+			description: "env mode returns LD_LIBRARY_PATH",
+			options: []LDCacheUpdateHookOption{
+				WithLDCacheUpdateMode(LDCacheUpdateModeEnv),
+			},
+			expectedEnvVars: []EnvVar{
+				{
+					Name:  "LD_LIBRARY_PATH",
+					Value: "/usr/local/lib:/usr/local/libother",
+				},
+			},
+		},
+		{
+			description:   "invalid mode returns an error",
+			options:       []LDCacheUpdateHookOption{WithLDCacheUpdateMode("invalid")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mountMock := &DiscoverMock{
+				MountsFunc: func() ([]Mount, error) {
+					return mounts, nil
+				},
+			}
+
+			d, err := NewLDCacheUpdateHook(logger, mountMock, testNvidiaCDIHookPath, "", tc.options...)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			hooks, err := d.Hooks()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedHooks, hooks)
+
+			envVars, err := d.EnvVars()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedEnvVars, envVars)
+		})
+	}
+}
+
+func TestLDCacheUpdateHookLifecycle(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description       string
+		options           []LDCacheUpdateHookOption
+		expectedError     bool
+		expectedLifecycle string
+	}{
+		{
+			description:       "createContainer is the default",
+			expectedLifecycle: "createContainer",
+		},
+		{
+			description:       "lifecycle can be overridden",
+			options:           []LDCacheUpdateHookOption{WithHookLifecycle("startContainer")},
+			expectedLifecycle: "startContainer",
+		},
+		{
+			description:   "invalid lifecycle returns an error",
+			options:       []LDCacheUpdateHookOption{WithHookLifecycle("beforeStart")},
+			expectedError: true,
+		},
+		{
+			description:   "lifecycle outside the container namespace returns an error",
+			options:       []LDCacheUpdateHookOption{WithHookLifecycle("createRuntime")},
+			expectedError: true,
+		},
+		{
+			description:   "poststop lifecycle returns an error",
+			options:       []LDCacheUpdateHookOption{WithHookLifecycle("poststop")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mountMock := &DiscoverMock{
+				MountsFunc: func() ([]Mount, error) {
+					return []Mount{{Path: "/usr/local/lib/libfoo.so"}}, nil
+				},
+			}
+
+			d, err := NewLDCacheUpdateHook(logger, mountMock, testNvidiaCDIHookPath, "", tc.options...)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			hooks, err := d.Hooks()
+			require.NoError(t, err)
+			require.Len(t, hooks, 1)
+			require.Equal(t, tc.expectedLifecycle, hooks[0].Lifecycle)
+		})
+	}
+}
+
 func TestIsLibName(t *testing.T) {
 	testCases := []struct {
 		name  string
@@ -0,0 +1,94 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// librarySizeBudget checks the total size of the libraries mounted by a
+// discoverer against a configured budget.
+type librarySizeBudget struct {
+	Discover
+	logger logger.Interface
+	budget int64
+	strict bool
+}
+
+// NewLibrarySizeBudget wraps the specified discoverer with a check on the
+// total size of the discovered library mounts. If the total exceeds the
+// specified budget (in bytes) a warning is logged, or an error is returned if
+// strict is set. If the budget is not positive, no check is performed.
+func NewLibrarySizeBudget(logger logger.Interface, d Discover, budget int64, strict bool) Discover {
+	if budget <= 0 {
+		return d
+	}
+	return &librarySizeBudget{
+		Discover: d,
+		logger:   logger,
+		budget:   budget,
+		strict:   strict,
+	}
+}
+
+// Mounts returns the mounts of the wrapped discoverer after checking the total
+// size of the library mounts.
+func (d *librarySizeBudget) Mounts() ([]Mount, error) {
+	mounts, err := d.Discover.Mounts()
+	if err != nil {
+		return nil, err
+	}
+
+	total := d.getLibrariesSize(mounts)
+	if total <= d.budget {
+		d.logger.Debugf("Total size of injected libraries is %d bytes (budget %d bytes)", total, d.budget)
+		return mounts, nil
+	}
+
+	if d.strict {
+		return nil, fmt.Errorf("total size of injected libraries (%d bytes) exceeds budget of %d bytes", total, d.budget)
+	}
+	d.logger.Warningf("Total size of injected libraries (%d bytes) exceeds budget of %d bytes", total, d.budget)
+	return mounts, nil
+}
+
+// getLibrariesSize returns the sum of the sizes of the libraries in the
+// specified mounts. Each library is only counted once.
+func (d *librarySizeBudget) getLibrariesSize(mounts []Mount) int64 {
+	seen := make(map[string]bool)
+	var total int64
+	for _, m := range mounts {
+		if !isLibName(m.Path) || seen[m.HostPath] {
+			continue
+		}
+		seen[m.HostPath] = true
+
+		info, err := os.Stat(m.HostPath)
+		if err != nil {
+			d.logger.Debugf("Failed to get size of %v: %v", m.HostPath, err)
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		total += info.Size()
+	}
+	return total
+}
@@ -0,0 +1,106 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLibrarySizeBudget(t *testing.T) {
+	root := t.TempDir()
+	libcuda := filepath.Join(root, "libcuda.so.1")
+	require.NoError(t, os.WriteFile(libcuda, make([]byte, 100), 0644))
+	libnvidiaml := filepath.Join(root, "libnvidia-ml.so.1")
+	require.NoError(t, os.WriteFile(libnvidiaml, make([]byte, 50), 0644))
+	config := filepath.Join(root, "config.json")
+	require.NoError(t, os.WriteFile(config, make([]byte, 1000), 0644))
+
+	mounts := []Mount{
+		{HostPath: libcuda, Path: "/usr/lib/libcuda.so.1"},
+		{HostPath: libnvidiaml, Path: "/usr/lib/libnvidia-ml.so.1"},
+		{HostPath: config, Path: "/etc/config.json"},
+	}
+
+	testCases := []struct {
+		description     string
+		budget          int64
+		strict          bool
+		expectedError   bool
+		expectedWarning bool
+	}{
+		{
+			description: "disabled budget is not checked",
+		},
+		{
+			description: "libraries within budget",
+			budget:      150,
+		},
+		{
+			description:     "exceeded budget logs a warning",
+			budget:          149,
+			expectedWarning: true,
+		},
+		{
+			description:   "exceeded strict budget is an error",
+			budget:        149,
+			strict:        true,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger, hook := testlog.NewNullLogger()
+			d := NewLibrarySizeBudget(
+				logger,
+				&DiscoverMock{
+					MountsFunc: func() ([]Mount, error) {
+						return mounts, nil
+					},
+				},
+				tc.budget,
+				tc.strict,
+			)
+
+			discovered, err := d.Mounts()
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, mounts, discovered)
+
+			var warnings int
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					warnings++
+				}
+			}
+			if tc.expectedWarning {
+				require.Equal(t, 1, warnings)
+			} else {
+				require.Zero(t, warnings)
+			}
+		})
+	}
+}
@@ -19,8 +19,9 @@ package discover
 import "fmt"
 
 // list is a discoverer that contains a list of Discoverers. The output of the
-// Mounts functions is the concatenation of the output for each of the
-// elements in the list.
+// Devices, EnvVars, Mounts, and Hooks functions is the concatenation of the
+// output for each of the elements in the list in the order that they were
+// specified. If any element returns an error, this error is returned.
 type list struct {
 	discoverers []Discover
 }
@@ -51,6 +52,21 @@ func (d list) Devices() ([]Device, error) {
 	return allDevices, nil
 }
 
+// EnvVars returns all environment variables from the included discoverers
+func (d list) EnvVars() ([]EnvVar, error) {
+	var allEnvVars []EnvVar
+
+	for i, di := range d.discoverers {
+		envVars, err := di.EnvVars()
+		if err != nil {
+			return nil, fmt.Errorf("error discovering environment variables for discoverer %v: %v", i, err)
+		}
+		allEnvVars = append(allEnvVars, envVars...)
+	}
+
+	return allEnvVars, nil
+}
+
 // Mounts returns all mounts from the included discoverers
 func (d list) Mounts() ([]Mount, error) {
 	var allMounts []Mount
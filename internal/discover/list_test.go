@@ -0,0 +1,113 @@
+/*
+# Copyright (c) 2021, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+*/
+
+package discover
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	first := &DiscoverMock{
+		DevicesFunc: func() ([]Device, error) {
+			return []Device{{Path: "/dev/nvidia0"}}, nil
+		},
+		EnvVarsFunc: func() ([]EnvVar, error) {
+			return []EnvVar{{Name: "FOO", Value: "foo"}}, nil
+		},
+		MountsFunc: func() ([]Mount, error) {
+			return []Mount{{HostPath: "/host/a", Path: "/a"}}, nil
+		},
+		HooksFunc: func() ([]Hook, error) {
+			return []Hook{{Lifecycle: "createContainer", Path: "/bin/first"}}, nil
+		},
+	}
+	second := &DiscoverMock{
+		DevicesFunc: func() ([]Device, error) {
+			return []Device{{Path: "/dev/nvidia1"}}, nil
+		},
+		EnvVarsFunc: func() ([]EnvVar, error) {
+			return []EnvVar{{Name: "BAR", Value: "bar"}}, nil
+		},
+		MountsFunc: func() ([]Mount, error) {
+			return []Mount{{HostPath: "/host/b", Path: "/b"}}, nil
+		},
+		HooksFunc: func() ([]Hook, error) {
+			return []Hook{{Lifecycle: "createContainer", Path: "/bin/second"}}, nil
+		},
+	}
+
+	d := Merge(first, second)
+
+	devices, err := d.Devices()
+	require.NoError(t, err)
+	require.EqualValues(t, []Device{{Path: "/dev/nvidia0"}, {Path: "/dev/nvidia1"}}, devices)
+
+	envVars, err := d.EnvVars()
+	require.NoError(t, err)
+	require.EqualValues(t, []EnvVar{{Name: "FOO", Value: "foo"}, {Name: "BAR", Value: "bar"}}, envVars)
+
+	mounts, err := d.Mounts()
+	require.NoError(t, err)
+	require.EqualValues(t, []Mount{{HostPath: "/host/a", Path: "/a"}, {HostPath: "/host/b", Path: "/b"}}, mounts)
+
+	hooks, err := d.Hooks()
+	require.NoError(t, err)
+	require.EqualValues(t, []Hook{{Lifecycle: "createContainer", Path: "/bin/first"}, {Lifecycle: "createContainer", Path: "/bin/second"}}, hooks)
+}
+
+func TestMergeErrors(t *testing.T) {
+	firstErr := fmt.Errorf("first")
+	secondErr := fmt.Errorf("second")
+
+	failing := func(err error) *DiscoverMock {
+		return &DiscoverMock{
+			DevicesFunc: func() ([]Device, error) {
+				return nil, err
+			},
+			EnvVarsFunc: func() ([]EnvVar, error) {
+				return nil, err
+			},
+			MountsFunc: func() ([]Mount, error) {
+				return nil, err
+			},
+			HooksFunc: func() ([]Hook, error) {
+				return nil, err
+			},
+		}
+	}
+
+	d := Merge(None{}, failing(firstErr), failing(secondErr))
+
+	devices, err := d.Devices()
+	require.ErrorContains(t, err, firstErr.Error())
+	require.Nil(t, devices)
+
+	envVars, err := d.EnvVars()
+	require.ErrorContains(t, err, firstErr.Error())
+	require.Nil(t, envVars)
+
+	mounts, err := d.Mounts()
+	require.ErrorContains(t, err, firstErr.Error())
+	require.Nil(t, mounts)
+
+	hooks, err := d.Hooks()
+	require.ErrorContains(t, err, firstErr.Error())
+	require.Nil(t, hooks)
+}
@@ -0,0 +1,45 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvcaps"
+)
+
+// NewMIGCapsDiscoverer creates a discoverer for the procfs files associated
+// with the specified MIG capabilities. These are found under
+// /proc/driver/nvidia/capabilities relative to the specified root and are
+// mounted read-only. Missing capability files are skipped.
+func NewMIGCapsDiscoverer(logger logger.Interface, root string, caps ...nvcaps.MigCap) Discover {
+	var procPaths []string
+	for _, cap := range caps {
+		procPaths = append(procPaths, cap.ProcPath())
+	}
+
+	return newMounts(
+		logger,
+		lookup.NewFileLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(root),
+			lookup.WithCount(1),
+		),
+		root,
+		procPaths,
+	)
+}
@@ -0,0 +1,96 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvcaps"
+)
+
+func TestMIGCapsDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	for _, cap := range []nvcaps.MigCap{
+		"config",
+		nvcaps.NewGPUInstanceCap(0, 1),
+		nvcaps.NewComputeInstanceCap(0, 1, 0),
+	} {
+		procPath := filepath.Join(root, cap.ProcPath())
+		require.NoError(t, os.MkdirAll(filepath.Dir(procPath), 0755))
+		require.NoError(t, os.WriteFile(procPath, nil, 0444))
+	}
+
+	testCases := []struct {
+		description    string
+		caps           []nvcaps.MigCap
+		expectedMounts []Mount
+	}{
+		{
+			description: "no caps returns no mounts",
+		},
+		{
+			description: "GI and CI caps are mounted",
+			caps: []nvcaps.MigCap{
+				nvcaps.NewGPUInstanceCap(0, 1),
+				nvcaps.NewComputeInstanceCap(0, 1, 0),
+			},
+			expectedMounts: []Mount{
+				{
+					HostPath: filepath.Join(root, "/proc/driver/nvidia/capabilities/gpu0/mig/gi1/access"),
+					Path:     "/proc/driver/nvidia/capabilities/gpu0/mig/gi1/access",
+					Options:  []string{"ro", "nosuid", "nodev", "bind"},
+				},
+				{
+					HostPath: filepath.Join(root, "/proc/driver/nvidia/capabilities/gpu0/mig/gi1/ci0/access"),
+					Path:     "/proc/driver/nvidia/capabilities/gpu0/mig/gi1/ci0/access",
+					Options:  []string{"ro", "nosuid", "nodev", "bind"},
+				},
+			},
+		},
+		{
+			description: "missing caps are skipped",
+			caps: []nvcaps.MigCap{
+				nvcaps.NewGPUInstanceCap(1, 2),
+				"config",
+			},
+			expectedMounts: []Mount{
+				{
+					HostPath: filepath.Join(root, "/proc/driver/nvidia/capabilities/mig/config"),
+					Path:     "/proc/driver/nvidia/capabilities/mig/config",
+					Options:  []string{"ro", "nosuid", "nodev", "bind"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := NewMIGCapsDiscoverer(logger, root, tc.caps...)
+
+			mounts, err := d.Mounts()
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expectedMounts, mounts)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"path/filepath"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+const (
+	// DefaultMPSPipeDirectory is the default pipe directory of the NVIDIA MPS
+	// control daemon.
+	DefaultMPSPipeDirectory = "/tmp/nvidia-mps"
+	// DefaultMPSLogDirectory is the default log directory of the NVIDIA MPS
+	// control daemon.
+	DefaultMPSLogDirectory = "/var/log/nvidia-mps"
+)
+
+// NewMPSDiscoverer creates a discoverer for the pipe and log directories of the
+// NVIDIA MPS control daemon in the specified driver root. If a directory is
+// not specified, the default is used. Directories that do not exist are
+// skipped.
+//
+// The directories are host paths and must not be taken from the container
+// image. The default pipe directory is not included since it is already
+// injected by the IPC discoverer.
+func NewMPSDiscoverer(logger logger.Interface, driverRoot string, pipeDirectory string, logDirectory string) Discover {
+	if logDirectory == "" {
+		logDirectory = DefaultMPSLogDirectory
+	}
+
+	var directories []string
+	if pipeDirectory != "" && filepath.Clean(pipeDirectory) != DefaultMPSPipeDirectory {
+		directories = append(directories, pipeDirectory)
+	}
+	directories = append(directories, logDirectory)
+
+	m := newMounts(
+		logger,
+		lookup.NewDirectoryLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(driverRoot),
+		),
+		driverRoot,
+		directories,
+	)
+	return (*ipcMounts)(m)
+}
@@ -0,0 +1,116 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMPSDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	mountOptions := []string{"ro", "nosuid", "nodev", "bind", "noexec"}
+
+	testCases := []struct {
+		description    string
+		directories    []string
+		pipeDirectory  string
+		logDirectory   string
+		expectedMounts func(string) []Mount
+	}{
+		{
+			description: "missing directories return no mounts",
+			expectedMounts: func(string) []Mount {
+				return nil
+			},
+		},
+		{
+			description: "default pipe directory is not mounted",
+			directories: []string{"/tmp/nvidia-mps", "/var/log/nvidia-mps"},
+			expectedMounts: func(driverRoot string) []Mount {
+				return []Mount{
+					{
+						HostPath: filepath.Join(driverRoot, "/var/log/nvidia-mps"),
+						Path:     "/var/log/nvidia-mps",
+						Options:  mountOptions,
+					},
+				}
+			},
+		},
+		{
+			description:   "explicit default pipe directory is not mounted",
+			directories:   []string{"/tmp/nvidia-mps"},
+			pipeDirectory: "/tmp/nvidia-mps/",
+			expectedMounts: func(string) []Mount {
+				return nil
+			},
+		},
+		{
+			description:   "configured pipe directory is mounted",
+			directories:   []string{"/tmp/nvidia-mps", "/run/nvidia/mps"},
+			pipeDirectory: "/run/nvidia/mps",
+			logDirectory:  "/run/nvidia/mps-log",
+			expectedMounts: func(driverRoot string) []Mount {
+				return []Mount{
+					{
+						HostPath: filepath.Join(driverRoot, "/run/nvidia/mps"),
+						Path:     "/run/nvidia/mps",
+						Options:  mountOptions,
+					},
+				}
+			},
+		},
+		{
+			description:  "configured log directory is mounted",
+			directories:  []string{"/var/log/nvidia-mps", "/run/nvidia/mps-log"},
+			logDirectory: "/run/nvidia/mps-log",
+			expectedMounts: func(driverRoot string) []Mount {
+				return []Mount{
+					{
+						HostPath: filepath.Join(driverRoot, "/run/nvidia/mps-log"),
+						Path:     "/run/nvidia/mps-log",
+						Options:  mountOptions,
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			for _, dir := range tc.directories {
+				require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, dir), 0755))
+			}
+
+			d := NewMPSDiscoverer(logger, driverRoot, tc.pipeDirectory, tc.logDirectory)
+
+			devices, err := d.Devices()
+			require.NoError(t, err)
+			require.Empty(t, devices)
+
+			mounts, err := d.Mounts()
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expectedMounts(driverRoot), mounts)
+		})
+	}
+}
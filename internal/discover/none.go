@@ -27,6 +27,11 @@ func (e None) Devices() ([]Device, error) {
 	return []Device{}, nil
 }
 
+// EnvVars returns an empty list of environment variables
+func (e None) EnvVars() ([]EnvVar, error) {
+	return []EnvVar{}, nil
+}
+
 // Mounts returns an empty list of mounts
 func (e None) Mounts() ([]Mount, error) {
 	return []Mount{}, nil
@@ -0,0 +1,46 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+// NewPersistencedSocketDiscoverer creates a discoverer for the NVIDIA
+// persistenced socket. The socket is located under /run or /var/run in the
+// specified driver root. If the socket does not exist, no mounts are returned.
+func NewPersistencedSocketDiscoverer(logger logger.Interface, driverRoot string) Discover {
+	return newSocketMounts(logger, driverRoot, "/nvidia-persistenced/socket")
+}
+
+// newSocketMounts creates a discoverer for the specified sockets under /run or
+// /var/run in the driver root.
+func newSocketMounts(logger logger.Interface, driverRoot string, sockets ...string) *ipcMounts {
+	m := newMounts(
+		logger,
+		lookup.NewFileLocator(
+			lookup.WithLogger(logger),
+			lookup.WithRoot(driverRoot),
+			lookup.WithSearchPaths("/run", "/var/run"),
+			lookup.WithCount(1),
+		),
+		driverRoot,
+		sockets,
+	)
+	return (*ipcMounts)(m)
+}
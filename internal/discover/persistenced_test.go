@@ -0,0 +1,86 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistencedSocketDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description    string
+		socketPath     string
+		expectedMounts func(string) []Mount
+	}{
+		{
+			description: "missing socket returns no mounts",
+			expectedMounts: func(string) []Mount {
+				return nil
+			},
+		},
+		{
+			description: "socket in /run is mounted",
+			socketPath:  "/run/nvidia-persistenced/socket",
+			expectedMounts: func(driverRoot string) []Mount {
+				return []Mount{
+					{
+						HostPath: filepath.Join(driverRoot, "/run/nvidia-persistenced/socket"),
+						Path:     "/run/nvidia-persistenced/socket",
+						Options:  []string{"ro", "nosuid", "nodev", "bind", "noexec"},
+					},
+				}
+			},
+		},
+		{
+			description: "socket in /var/run is mounted",
+			socketPath:  "/var/run/nvidia-persistenced/socket",
+			expectedMounts: func(driverRoot string) []Mount {
+				return []Mount{
+					{
+						HostPath: filepath.Join(driverRoot, "/var/run/nvidia-persistenced/socket"),
+						Path:     "/var/run/nvidia-persistenced/socket",
+						Options:  []string{"ro", "nosuid", "nodev", "bind", "noexec"},
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			if tc.socketPath != "" {
+				socket := filepath.Join(driverRoot, tc.socketPath)
+				require.NoError(t, os.MkdirAll(filepath.Dir(socket), 0755))
+				require.NoError(t, os.WriteFile(socket, nil, 0600))
+			}
+
+			d := NewPersistencedSocketDiscoverer(logger, driverRoot)
+
+			mounts, err := d.Mounts()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedMounts(driverRoot), mounts)
+		})
+	}
+}
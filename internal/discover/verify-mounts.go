@@ -0,0 +1,53 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// verifyMounts is a discoverer that generates a verify-mounts hook for the
+// mounts discovered by the wrapped discoverer.
+type verifyMounts struct {
+	None
+	logger            logger.Interface
+	nvidiaCDIHookPath string
+	mountsFrom        Discover
+}
+
+// NewVerifyMountsHook creates a discoverer that verifies that the container
+// paths of the mounts discovered by the specified discoverer exist in the
+// container once these have been applied. Libraries are also checked to be
+// shared object ELF files.
+func NewVerifyMountsHook(logger logger.Interface, mounts Discover, nvidiaCDIHookPath string) Discover {
+	return &verifyMounts{
+		logger:            logger,
+		nvidiaCDIHookPath: nvidiaCDIHookPath,
+		mountsFrom:        mounts,
+	}
+}
+
+// Hooks returns a hook that verifies the discovered mounts.
+func (d verifyMounts) Hooks() ([]Hook, error) {
+	mounts, err := d.mountsFrom.Mounts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover mounts for verify-mounts hook: %v", err)
+	}
+	return CreateVerifyMountsHook(d.nvidiaCDIHookPath, mounts).Hooks()
+}
@@ -0,0 +1,96 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package discover
+
+import (
+	"fmt"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyMountsHook(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description        string
+		mounts             []Mount
+		mountsError        error
+		expectedHooksError error
+		expectedHooks      []Hook
+	}{
+		{
+			description: "no mounts returns no hooks",
+		},
+		{
+			description:        "mounts error is returned",
+			mountsError:        fmt.Errorf("mountsError"),
+			expectedHooksError: fmt.Errorf("mountsError"),
+		},
+		{
+			description: "container paths are added to args",
+			mounts: []Mount{
+				{
+					Path:     "/usr/lib64/libcuda.so.999.88.77",
+					HostPath: "/run/nvidia/driver/usr/lib64/libcuda.so.999.88.77",
+				},
+				{
+					Path:     "/usr/bin/nvidia-smi",
+					HostPath: "/usr/bin/nvidia-smi",
+				},
+			},
+			expectedHooks: []Hook{
+				{
+					Lifecycle: "createContainer",
+					Path:      testNvidiaCDIHookPath,
+					Args: []string{"nvidia-cdi-hook", "verify-mounts",
+						"--path", "/usr/lib64/libcuda.so.999.88.77",
+						"--path", "/usr/bin/nvidia-smi",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mounts := &DiscoverMock{
+				MountsFunc: func() ([]Mount, error) {
+					return tc.mounts, tc.mountsError
+				},
+			}
+			d := NewVerifyMountsHook(logger, mounts, testNvidiaCDIHookPath)
+
+			hooks, err := d.Hooks()
+			if tc.expectedHooksError != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expectedHooks == nil {
+				require.Empty(t, hooks)
+			} else {
+				require.EqualValues(t, tc.expectedHooks, hooks)
+			}
+
+			m, err := d.Mounts()
+			require.NoError(t, err)
+			require.Empty(t, m)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"sort"
+
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+)
+
+// uniqueMounts returns the specified mounts with duplicates removed. Two
+// mounts are considered duplicates if their host paths, container paths, and
+// options (irrespective of order) match. The first occurrence of each mount is
+// kept.
+func uniqueMounts(logger logger.Interface, mounts []discover.Mount) []discover.Mount {
+	seen := make(map[string]bool)
+	var unique []discover.Mount
+	for _, m := range mounts {
+		key := mountKey(m.HostPath, m.Path, m.Options)
+		if seen[key] {
+			logger.Debugf("Dropping duplicate mount host_path=%v container_path=%v options=%v", m.HostPath, m.Path, m.Options)
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, m)
+	}
+	return unique
+}
+
+// uniqueDevices returns the specified devices with duplicates removed. Two
+// devices are considered duplicates if their paths in the container match.
+// The first occurrence of each device is kept.
+func uniqueDevices(logger logger.Interface, devices []discover.Device) []discover.Device {
+	seen := make(map[string]bool)
+	var unique []discover.Device
+	for _, d := range devices {
+		if seen[d.Path] {
+			logger.Debugf("Dropping duplicate device path=%v host_path=%v", d.Path, d.HostPath)
+			continue
+		}
+		seen[d.Path] = true
+		unique = append(unique, d)
+	}
+	return unique
+}
+
+// hookLifecycleOrder defines the order in which hooks for the different OCI
+// lifecycle stages are included in the generated container edits. Hooks with
+// an unknown lifecycle are ordered after all known lifecycles.
+var hookLifecycleOrder = map[string]int{
+	cdi.PrestartHook:        0,
+	cdi.CreateRuntimeHook:   1,
+	cdi.CreateContainerHook: 2,
+	cdi.StartContainerHook:  3,
+	cdi.PoststartHook:       4,
+	cdi.PoststopHook:        5,
+}
+
+// orderedUniqueHooks returns the specified hooks with duplicates removed and
+// in a stable order. Two hooks are considered duplicates if their lifecycle,
+// path, and arguments match. The first occurrence of each hook is kept.
+//
+// The returned hooks are ordered by lifecycle stage (as defined by the OCI
+// runtime specification). Hooks for the same lifecycle stage retain the order
+// in which they were discovered, since hooks such as update-ldcache may depend
+// on the effects of hooks that precede them.
+func orderedUniqueHooks(logger logger.Interface, hooks []discover.Hook) []discover.Hook {
+	seen := make(map[string]bool)
+	var unique []discover.Hook
+	for _, h := range hooks {
+		key := hookKey(h.Lifecycle, h.Path, h.Args)
+		if seen[key] {
+			logger.Debugf("Dropping duplicate hook name=%v path=%v args=%v", h.Lifecycle, h.Path, h.Args)
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, h)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		return hookLifecycleRank(unique[i].Lifecycle) < hookLifecycleRank(unique[j].Lifecycle)
+	})
+	return unique
+}
+
+func hookLifecycleRank(lifecycle string) int {
+	if rank, ok := hookLifecycleOrder[lifecycle]; ok {
+		return rank
+	}
+	return len(hookLifecycleOrder)
+}
@@ -17,6 +17,9 @@
 package edits
 
 import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 	"tags.cncf.io/container-device-interface/specs-go"
 
@@ -26,11 +29,14 @@ import (
 type device discover.Device
 
 // toEdits converts a discovered device to CDI Container Edits.
-func (d device) toEdits() (*cdi.ContainerEdits, error) {
+func (d device) toEdits(o *options) (*cdi.ContainerEdits, error) {
 	deviceNode, err := d.toSpec()
 	if err != nil {
 		return nil, err
 	}
+	if o.populateDeviceNodeInfo {
+		d.populateInfo(deviceNode)
+	}
 
 	e := cdi.ContainerEdits{
 		ContainerEdits: &specs.ContainerEdits{
@@ -59,3 +65,44 @@ func (d device) toSpec() (*specs.DeviceNode, error) {
 
 	return &s, nil
 }
+
+// populateInfo sets the type, major, and minor number of the specified device
+// node by querying the device node on the host. If this fails, the device node
+// is left unchanged and the missing info is filled in when the edits are
+// applied.
+func (d device) populateInfo(deviceNode *specs.DeviceNode) {
+	hostPath := d.HostPath
+	if hostPath == "" {
+		hostPath = d.Path
+	}
+	devType, major, minor, err := deviceInfoFromPath(hostPath)
+	if err != nil {
+		return
+	}
+	deviceNode.Type = devType
+	deviceNode.Major = major
+	deviceNode.Minor = minor
+}
+
+// deviceInfoFromPath returns the type, major, and minor number of the device
+// node at the specified path.
+func deviceInfoFromPath(path string) (string, int64, int64, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return "", 0, 0, err
+	}
+
+	var devType string
+	switch stat.Mode & unix.S_IFMT {
+	case unix.S_IFCHR:
+		devType = "c"
+	case unix.S_IFBLK:
+		devType = "b"
+	default:
+		return "", 0, 0, fmt.Errorf("%v is not a device node", path)
+	}
+
+	//nolint:unconvert // Rdev is uint32 on some platforms.
+	rdev := uint64(stat.Rdev)
+	return devType, int64(unix.Major(rdev)), int64(unix.Minor(rdev)), nil
+}
@@ -18,6 +18,8 @@ package edits
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -68,3 +70,82 @@ func TestDeviceToSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestDeviceToEditsPopulatesInfo(t *testing.T) {
+	regularFile := filepath.Join(t.TempDir(), "not-a-device")
+	require.NoError(t, os.WriteFile(regularFile, nil, 0600))
+
+	testCases := []struct {
+		description string
+		device      discover.Device
+		populate    bool
+		expected    *specs.DeviceNode
+	}{
+		{
+			description: "info is not populated by default",
+			device: discover.Device{
+				Path: "/dev/null",
+			},
+			expected: &specs.DeviceNode{
+				Path: "/dev/null",
+			},
+		},
+		{
+			description: "info is populated for char device",
+			device: discover.Device{
+				Path: "/dev/null",
+			},
+			populate: true,
+			expected: &specs.DeviceNode{
+				Path:  "/dev/null",
+				Type:  "c",
+				Major: 1,
+				Minor: 3,
+			},
+		},
+		{
+			description: "host path is used to populate info",
+			device: discover.Device{
+				HostPath: "/dev/null",
+				Path:     "/dev/nvidia0",
+			},
+			populate: true,
+			expected: &specs.DeviceNode{
+				HostPath: "/dev/null",
+				Path:     "/dev/nvidia0",
+				Type:     "c",
+				Major:    1,
+				Minor:    3,
+			},
+		},
+		{
+			description: "missing device falls back to lazy info",
+			device: discover.Device{
+				Path: "/dev/does-not-exist",
+			},
+			populate: true,
+			expected: &specs.DeviceNode{
+				Path: "/dev/does-not-exist",
+			},
+		},
+		{
+			description: "regular file falls back to lazy info",
+			device: discover.Device{
+				Path: regularFile,
+			},
+			populate: true,
+			expected: &specs.DeviceNode{
+				Path: regularFile,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			edits, err := device(tc.device).toEdits(&options{populateDeviceNodeInfo: tc.populate})
+			require.NoError(t, err)
+			require.Len(t, edits.DeviceNodes, 1)
+			require.EqualValues(t, tc.expected, edits.DeviceNodes[0])
+		})
+	}
+}
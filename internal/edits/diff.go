@@ -0,0 +1,119 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"sort"
+	"strings"
+
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+// Diff returns the container edits that are present in b but not in a.
+// This can be used to construct an incremental spec, for example when comparing
+// the edits for two driver versions.
+//
+// Entities are considered equal as follows:
+//   - device nodes are equal if their container paths are equal.
+//   - mounts are equal if their host paths, container paths, and options
+//     (irrespective of order) are equal.
+//   - hooks are equal if their hook names, paths, and arguments are equal.
+//   - environment variables are equal if their KEY=VALUE strings are equal.
+func Diff(a *cdi.ContainerEdits, b *cdi.ContainerEdits) *cdi.ContainerEdits {
+	diff := NewContainerEdits()
+	if b == nil || b.ContainerEdits == nil {
+		return diff
+	}
+	if a == nil || a.ContainerEdits == nil {
+		a = NewContainerEdits()
+	}
+
+	existingEnv := make(map[string]bool)
+	for _, e := range a.Env {
+		existingEnv[e] = true
+	}
+	for _, e := range b.Env {
+		if existingEnv[e] {
+			continue
+		}
+		diff.Env = append(diff.Env, e)
+	}
+
+	existingDeviceNodes := make(map[string]bool)
+	for _, d := range a.DeviceNodes {
+		existingDeviceNodes[deviceNodeKey(d)] = true
+	}
+	for _, d := range b.DeviceNodes {
+		if existingDeviceNodes[deviceNodeKey(d)] {
+			continue
+		}
+		diff.DeviceNodes = append(diff.DeviceNodes, d)
+	}
+
+	existingMounts := make(map[string]bool)
+	for _, m := range a.Mounts {
+		existingMounts[mountKey(m.HostPath, m.ContainerPath, m.Options)] = true
+	}
+	for _, m := range b.Mounts {
+		if existingMounts[mountKey(m.HostPath, m.ContainerPath, m.Options)] {
+			continue
+		}
+		diff.Mounts = append(diff.Mounts, m)
+	}
+
+	existingHooks := make(map[string]bool)
+	for _, h := range a.Hooks {
+		existingHooks[hookKey(h.HookName, h.Path, h.Args)] = true
+	}
+	for _, h := range b.Hooks {
+		if existingHooks[hookKey(h.HookName, h.Path, h.Args)] {
+			continue
+		}
+		diff.Hooks = append(diff.Hooks, h)
+	}
+
+	return diff
+}
+
+// deviceNodeKey returns the key used to compare device nodes.
+func deviceNodeKey(d *specs.DeviceNode) string {
+	return d.Path
+}
+
+// mountKey returns the key used to compare mounts. The options are sorted so
+// that mounts that only differ in the order of their options are equal.
+func mountKey(hostPath string, containerPath string, options []string) string {
+	sorted := append([]string{}, options...)
+	sort.Strings(sorted)
+	return strings.Join(
+		[]string{
+			hostPath,
+			containerPath,
+			strings.Join(sorted, ","),
+		},
+		"\x00",
+	)
+}
+
+// hookKey returns the key used to compare hooks.
+func hookKey(lifecycle string, path string, args []string) string {
+	return strings.Join(
+		append([]string{lifecycle, path}, args...),
+		"\x00",
+	)
+}
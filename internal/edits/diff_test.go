@@ -0,0 +1,146 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		description string
+		a           *specs.ContainerEdits
+		b           *specs.ContainerEdits
+		expected    *specs.ContainerEdits
+	}{
+		{
+			description: "nil edits produce empty diff",
+			expected:    &specs.ContainerEdits{},
+		},
+		{
+			description: "nil first edits returns second",
+			b: &specs.ContainerEdits{
+				Env:         []string{"FOO=bar"},
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+			},
+			expected: &specs.ContainerEdits{
+				Env:         []string{"FOO=bar"},
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+			},
+		},
+		{
+			description: "identical edits produce empty diff",
+			a: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+				Mounts:      []*specs.Mount{{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1", Options: []string{"ro"}}},
+				Hooks:       []*specs.Hook{{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache"}}},
+			},
+			b: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+				Mounts:      []*specs.Mount{{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1", Options: []string{"ro"}}},
+				Hooks:       []*specs.Hook{{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache"}}},
+			},
+			expected: &specs.ContainerEdits{},
+		},
+		{
+			description: "mounts with reordered options are equal",
+			a: &specs.ContainerEdits{
+				Mounts: []*specs.Mount{{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1", Options: []string{"ro", "nosuid", "bind"}}},
+			},
+			b: &specs.ContainerEdits{
+				Mounts: []*specs.Mount{{HostPath: "/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1", Options: []string{"bind", "ro", "nosuid"}}},
+			},
+			expected: &specs.ContainerEdits{},
+		},
+		{
+			description: "overlapping edits return new entries only",
+			a: &specs.ContainerEdits{
+				Env:         []string{"FOO=bar"},
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+				Mounts: []*specs.Mount{
+					{HostPath: "/lib/libcuda.so.535.1", ContainerPath: "/lib/libcuda.so.535.1", Options: []string{"ro"}},
+					{HostPath: "/bin/nvidia-smi", ContainerPath: "/bin/nvidia-smi", Options: []string{"ro"}},
+				},
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache", "--folder", "/lib"}},
+				},
+			},
+			b: &specs.ContainerEdits{
+				Env: []string{"FOO=bar", "FOO=baz"},
+				DeviceNodes: []*specs.DeviceNode{
+					{Path: "/dev/nvidia0", HostPath: "/host/dev/nvidia0"},
+					{Path: "/dev/nvidia1"},
+				},
+				Mounts: []*specs.Mount{
+					{HostPath: "/lib/libcuda.so.550.1", ContainerPath: "/lib/libcuda.so.550.1", Options: []string{"ro"}},
+					{HostPath: "/bin/nvidia-smi", ContainerPath: "/bin/nvidia-smi", Options: []string{"ro", "nosuid"}},
+				},
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache", "--folder", "/lib"}},
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "create-symlinks"}},
+				},
+			},
+			expected: &specs.ContainerEdits{
+				Env:         []string{"FOO=baz"},
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia1"}},
+				Mounts: []*specs.Mount{
+					{HostPath: "/lib/libcuda.so.550.1", ContainerPath: "/lib/libcuda.so.550.1", Options: []string{"ro"}},
+					{HostPath: "/bin/nvidia-smi", ContainerPath: "/bin/nvidia-smi", Options: []string{"ro", "nosuid"}},
+				},
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "create-symlinks"}},
+				},
+			},
+		},
+		{
+			description: "disjoint edits return all of second",
+			a: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia0"}},
+				Mounts:      []*specs.Mount{{HostPath: "/a", ContainerPath: "/a"}},
+			},
+			b: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia1"}},
+				Mounts:      []*specs.Mount{{HostPath: "/b", ContainerPath: "/b"}},
+				Hooks:       []*specs.Hook{{HookName: "createContainer", Path: "/bin/hook"}},
+			},
+			expected: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/nvidia1"}},
+				Mounts:      []*specs.Mount{{HostPath: "/b", ContainerPath: "/b"}},
+				Hooks:       []*specs.Hook{{HookName: "createContainer", Path: "/bin/hook"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var a, b *cdi.ContainerEdits
+			if tc.a != nil {
+				a = &cdi.ContainerEdits{ContainerEdits: tc.a}
+			}
+			if tc.b != nil {
+				b = &cdi.ContainerEdits{ContainerEdits: tc.b}
+			}
+
+			diff := Diff(a, b)
+			require.EqualValues(t, tc.expected, diff.ContainerEdits)
+		})
+	}
+}
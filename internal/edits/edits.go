@@ -17,6 +17,7 @@
 package edits
 
 import (
+	"errors"
 	"fmt"
 
 	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
@@ -30,58 +31,128 @@ import (
 
 type edits struct {
 	cdi.ContainerEdits
-	logger logger.Interface
+	logger   logger.Interface
+	verbose  bool
+	simplify bool
 }
 
 // NewSpecEdits creates a SpecModifier that defines the required OCI spec edits (as CDI ContainerEdits) from the specified
 // discoverer.
-func NewSpecEdits(logger logger.Interface, d discover.Discover) (oci.SpecModifier, error) {
-	c, err := FromDiscoverer(d)
+func NewSpecEdits(logger logger.Interface, d discover.Discover, opts ...Option) (oci.SpecModifier, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	c, err := FromDiscoverer(d, append([]Option{WithLogger(logger)}, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("error constructing container edits: %v", err)
 	}
 	e := edits{
 		ContainerEdits: *c,
 		logger:         logger,
+		verbose:        o.verboseLogging,
+		simplify:       o.simplify,
 	}
 
 	return &e, nil
 }
 
 // FromDiscoverer creates CDI container edits for the specified discoverer.
-func FromDiscoverer(d discover.Discover) (*cdi.ContainerEdits, error) {
+// By default an error is returned if any discovery step fails. If errors are
+// tolerated, the failures are logged and the edits are constructed from the
+// successful steps. Duplicate device nodes, mounts, and hooks are removed.
+// An error is returned if the path of a hook is not absolute. Hooks are ordered by
+// lifecycle stage with hooks for the same stage retaining the order in which
+// they were discovered. If simplification is enabled, the edits are simplified
+// before they are returned.
+func FromDiscoverer(d discover.Discover, opts ...Option) (*cdi.ContainerEdits, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = &logger.NullLogger{}
+	}
+
+	var discoveryErrors []error
+	checkErr := func(source string, err error) error {
+		if err == nil {
+			return nil
+		}
+		err = fmt.Errorf("failed to discover %v: %w", source, err)
+		if !o.tolerateErrors {
+			return err
+		}
+		o.logger.Warningf("Ignoring error: %v", err)
+		discoveryErrors = append(discoveryErrors, err)
+		return nil
+	}
+
 	devices, err := d.Devices()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover devices: %v", err)
+	if err := checkErr("devices", err); err != nil {
+		return nil, err
+	}
+
+	envVars, err := d.EnvVars()
+	if err := checkErr("environment variables", err); err != nil {
+		return nil, err
 	}
 
 	mounts, err := d.Mounts()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover mounts: %v", err)
+	if err := checkErr("mounts", err); err != nil {
+		return nil, err
 	}
 
 	hooks, err := d.Hooks()
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover hooks: %v", err)
+	if err := checkErr("hooks", err); err != nil {
+		return nil, err
+	}
+
+	if len(discoveryErrors) > 0 {
+		o.logger.Warningf("Container edits are incomplete: %v", errors.Join(discoveryErrors...))
 	}
 
 	c := NewContainerEdits()
-	for _, d := range devices {
-		edits, err := device(d).toEdits()
+	for _, d := range uniqueDevices(o.logger, devices) {
+		edits, err := device(d).toEdits(o)
 		if err != nil {
 			return nil, fmt.Errorf("failed to created container edits for device: %v", err)
 		}
 		c.Append(edits)
 	}
 
-	for _, m := range mounts {
+	for _, e := range envVars {
+		c.Append(envvar(e).toEdits())
+	}
+
+	if o.mountOptionsTransform != nil {
+		var transformed []discover.Mount
+		for _, m := range mounts {
+			m.Options = o.mountOptionsTransform(m)
+			transformed = append(transformed, m)
+		}
+		mounts = transformed
+	}
+	for _, m := range uniqueMounts(o.logger, mounts) {
 		c.Append(mount(m).toEdits())
 	}
 
-	for _, h := range hooks {
+	for _, h := range orderedUniqueHooks(o.logger, hooks) {
+		if hook(h).isExcluded(o.excludedHooks) {
+			o.logger.Infof("Excluding %v hook %v %v", h.Lifecycle, h.Path, h.Args)
+			continue
+		}
+		if err := hook(h).validate(o.verifyHookPaths); err != nil {
+			return nil, fmt.Errorf("invalid hook %v: %w", h.Args, err)
+		}
 		c.Append(hook(h).toEdits())
 	}
 
+	if o.simplify {
+		(&edits{ContainerEdits: *c}).Simplify()
+	}
+
 	return c, nil
 }
 
@@ -99,18 +170,33 @@ func (e *edits) Modify(spec *ociSpecs.Spec) error {
 		return nil
 	}
 
-	e.logger.Info("Mounts:")
+	if e.simplify {
+		e.Simplify()
+	}
+
+	e.logger.Infof("Injecting %d mounts, %d devices, %d hooks", len(e.Mounts), len(e.DeviceNodes), len(e.Hooks))
+
+	logf := e.logger.Debugf
+	if e.verbose {
+		logf = e.logger.Infof
+	}
 	for _, mount := range e.Mounts {
-		e.logger.Infof("Mounting %v at %v", mount.HostPath, mount.ContainerPath)
+		logf("Mounting host_path=%v container_path=%v options=%v", mount.HostPath, mount.ContainerPath, mount.Options)
 	}
-	e.logger.Infof("Devices:")
 	for _, device := range e.DeviceNodes {
-		e.logger.Infof("Injecting %v", device.Path)
+		logf("Injecting device path=%v host_path=%v", device.Path, device.HostPath)
 	}
-	e.logger.Infof("Hooks:")
 	for _, hook := range e.Hooks {
-		e.logger.Infof("Injecting %v %v", hook.Path, hook.Args)
+		logf("Injecting hook name=%v path=%v args=%v", hook.HookName, hook.Path, hook.Args)
+	}
+	env := AppendToExistingEnv(e.Env, spec)
+	for _, v := range env {
+		logf("Injecting environment variable %v", v)
 	}
 
-	return e.Apply(spec)
+	// The environment is updated on a copy of the edits so that the values
+	// from this spec are not carried over if the edits are applied again.
+	c := *e.ContainerEdits.ContainerEdits
+	c.Env = env
+	return (&cdi.ContainerEdits{ContainerEdits: &c}).Apply(spec)
 }
@@ -17,11 +17,20 @@
 package edits
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/nvcaps"
 )
 
 func TestFromDiscovererAllowsMountsToIterate(t *testing.T) {
@@ -30,3 +39,623 @@ func TestFromDiscovererAllowsMountsToIterate(t *testing.T) {
 
 	require.Empty(t, edits.Mounts)
 }
+
+func TestFromDiscovererPersistencedSocket(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	socket := filepath.Join(driverRoot, "run/nvidia-persistenced/socket")
+	require.NoError(t, os.MkdirAll(filepath.Dir(socket), 0755))
+	require.NoError(t, os.WriteFile(socket, nil, 0600))
+
+	edits, err := FromDiscoverer(discover.NewPersistencedSocketDiscoverer(logger, driverRoot))
+	require.NoError(t, err)
+
+	require.EqualValues(t,
+		[]*specs.Mount{
+			{
+				HostPath:      socket,
+				ContainerPath: "/run/nvidia-persistenced/socket",
+				Options:       []string{"ro", "nosuid", "nodev", "bind", "noexec"},
+			},
+		},
+		edits.Mounts,
+	)
+}
+
+func TestFromDiscovererMIGCaps(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	giCap := nvcaps.NewGPUInstanceCap(0, 1)
+	procPath := filepath.Join(root, giCap.ProcPath())
+	require.NoError(t, os.MkdirAll(filepath.Dir(procPath), 0755))
+	require.NoError(t, os.WriteFile(procPath, nil, 0444))
+
+	edits, err := FromDiscoverer(discover.NewMIGCapsDiscoverer(logger, root, giCap))
+	require.NoError(t, err)
+
+	require.EqualValues(t,
+		[]*specs.Mount{
+			{
+				HostPath:      procPath,
+				ContainerPath: "/proc/driver/nvidia/capabilities/gpu0/mig/gi1/access",
+				Options:       []string{"ro", "nosuid", "nodev", "bind"},
+			},
+		},
+		edits.Mounts,
+	)
+}
+
+func TestFromDiscovererEnvVars(t *testing.T) {
+	d := &discover.DiscoverMock{
+		EnvVarsFunc: func() ([]discover.EnvVar, error) {
+			return []discover.EnvVar{
+				{Name: "LD_LIBRARY_PATH", Value: "/usr/lib/a:/usr/lib/b"},
+			}, nil
+		},
+	}
+
+	edits, err := FromDiscoverer(d)
+	require.NoError(t, err)
+
+	require.EqualValues(t, []string{"LD_LIBRARY_PATH=/usr/lib/a:/usr/lib/b"}, edits.Env)
+	require.Empty(t, edits.Hooks)
+}
+
+func TestModifyAppendsToExistingEnv(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description string
+		existing    []string
+		expectedEnv []string
+	}{
+		{
+			description: "value is set if not defined",
+			existing:    []string{"PATH=/usr/bin"},
+			expectedEnv: []string{"PATH=/usr/bin", "LD_LIBRARY_PATH=/usr/lib/a:/usr/lib/b", "OTHER=value"},
+		},
+		{
+			description: "value is appended to existing value",
+			existing:    []string{"LD_LIBRARY_PATH=/opt/app/lib", "OTHER=existing"},
+			expectedEnv: []string{
+				"LD_LIBRARY_PATH=/opt/app/lib",
+				"OTHER=existing",
+				"LD_LIBRARY_PATH=/opt/app/lib:/usr/lib/a:/usr/lib/b",
+				"OTHER=value",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &discover.DiscoverMock{
+				EnvVarsFunc: func() ([]discover.EnvVar, error) {
+					return []discover.EnvVar{
+						{Name: "LD_LIBRARY_PATH", Value: "/usr/lib/a:/usr/lib/b"},
+						{Name: "OTHER", Value: "value"},
+					}, nil
+				},
+			}
+
+			m, err := NewSpecEdits(logger, d)
+			require.NoError(t, err)
+
+			spec := &ociSpecs.Spec{
+				Process: &ociSpecs.Process{
+					Env: tc.existing,
+				},
+			}
+			require.NoError(t, m.Modify(spec))
+			require.EqualValues(t, tc.expectedEnv, spec.Process.Env)
+		})
+	}
+}
+
+func TestModifyDoesNotCarryOverEnv(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	d := &discover.DiscoverMock{
+		EnvVarsFunc: func() ([]discover.EnvVar, error) {
+			return []discover.EnvVar{{Name: "LD_LIBRARY_PATH", Value: "/usr/lib/a"}}, nil
+		},
+	}
+
+	m, err := NewSpecEdits(logger, d)
+	require.NoError(t, err)
+
+	first := &ociSpecs.Spec{Process: &ociSpecs.Process{Env: []string{"LD_LIBRARY_PATH=/opt/app/lib"}}}
+	require.NoError(t, m.Modify(first))
+	require.EqualValues(t, []string{"LD_LIBRARY_PATH=/opt/app/lib", "LD_LIBRARY_PATH=/opt/app/lib:/usr/lib/a"}, first.Process.Env)
+
+	second := &ociSpecs.Spec{Process: &ociSpecs.Process{}}
+	require.NoError(t, m.Modify(second))
+	require.EqualValues(t, []string{"LD_LIBRARY_PATH=/usr/lib/a"}, second.Process.Env)
+}
+
+func TestFromDiscovererRemovesDuplicates(t *testing.T) {
+	testCases := []struct {
+		description     string
+		devices         []discover.Device
+		mounts          []discover.Mount
+		expectedDevices []*specs.DeviceNode
+		expectedMounts  []*specs.Mount
+	}{
+		{
+			description: "identical mounts are deduplicated",
+			mounts: []discover.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/lib/libcuda.so.1"},
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/lib/libcuda.so.1"},
+			},
+			expectedMounts: []*specs.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1"},
+			},
+		},
+		{
+			description: "mounts with reordered options are deduplicated",
+			mounts: []discover.Mount{
+				{HostPath: "/host/socket", Path: "/socket", Options: []string{"ro", "bind"}},
+				{HostPath: "/host/socket", Path: "/socket", Options: []string{"bind", "ro"}},
+			},
+			expectedMounts: []*specs.Mount{
+				{HostPath: "/host/socket", ContainerPath: "/socket", Options: []string{"ro", "bind"}},
+			},
+		},
+		{
+			description: "mounts to different container paths are kept",
+			mounts: []discover.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/lib/libcuda.so.1"},
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/lib64/libcuda.so.1"},
+			},
+			expectedMounts: []*specs.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/lib/libcuda.so.1"},
+				{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/lib64/libcuda.so.1"},
+			},
+		},
+		{
+			description: "devices with the same path are deduplicated",
+			devices: []discover.Device{
+				{HostPath: "/dev/nvidia0", Path: "/dev/nvidia0"},
+				{HostPath: "/dev/nvidia0", Path: "/dev/nvidia0"},
+				{HostPath: "/dev/nvidiactl", Path: "/dev/nvidiactl"},
+			},
+			expectedDevices: []*specs.DeviceNode{
+				{Path: "/dev/nvidia0"},
+				{Path: "/dev/nvidiactl"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &discover.DiscoverMock{
+				DevicesFunc: func() ([]discover.Device, error) {
+					return tc.devices, nil
+				},
+				MountsFunc: func() ([]discover.Mount, error) {
+					return tc.mounts, nil
+				},
+			}
+
+			edits, err := FromDiscoverer(d)
+			require.NoError(t, err)
+
+			require.EqualValues(t, tc.expectedDevices, edits.DeviceNodes)
+			require.EqualValues(t, tc.expectedMounts, edits.Mounts)
+		})
+	}
+}
+
+func TestFromDiscovererHooks(t *testing.T) {
+	ldcacheHook := discover.Hook{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"}}
+	symlinksHook := discover.Hook{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"}}
+	prestartHook := discover.Hook{Lifecycle: "prestart", Path: "/usr/bin/nvidia-container-runtime-hook", Args: []string{"nvidia-container-runtime-hook", "prestart"}}
+
+	testCases := []struct {
+		description   string
+		hooks         []discover.Hook
+		expectedHooks []*specs.Hook
+	}{
+		{
+			description: "identical hooks are deduplicated",
+			hooks:       []discover.Hook{symlinksHook, ldcacheHook, symlinksHook, ldcacheHook},
+			expectedHooks: []*specs.Hook{
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+			},
+		},
+		{
+			description: "hooks with different args are kept",
+			hooks: []discover.Hook{
+				ldcacheHook,
+				{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib"}},
+			},
+			expectedHooks: []*specs.Hook{
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib"}},
+			},
+		},
+		{
+			description: "hooks with different lifecycles are kept",
+			hooks: []discover.Hook{
+				ldcacheHook,
+				{Lifecycle: "startContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+			},
+			expectedHooks: []*specs.Hook{
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "startContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+			},
+		},
+		{
+			description: "hooks are ordered by lifecycle",
+			hooks: []discover.Hook{
+				{Lifecycle: "poststop", Path: "/bin/cleanup"},
+				symlinksHook,
+				prestartHook,
+				ldcacheHook,
+			},
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "poststop", Path: "/bin/cleanup"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &discover.DiscoverMock{
+				HooksFunc: func() ([]discover.Hook, error) {
+					return tc.hooks, nil
+				},
+			}
+
+			edits, err := FromDiscoverer(d)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedHooks, edits.Hooks)
+
+			// Constructing the edits again must produce the same ordering.
+			again, err := FromDiscoverer(d)
+			require.NoError(t, err)
+			require.EqualValues(t, edits.Hooks, again.Hooks)
+		})
+	}
+}
+
+func TestFromDiscovererTolerateErrors(t *testing.T) {
+	d := &discover.DiscoverMock{
+		DevicesFunc: func() ([]discover.Device, error) {
+			return []discover.Device{{Path: "/dev/nvidia0"}}, nil
+		},
+		EnvVarsFunc: func() ([]discover.EnvVar, error) {
+			return []discover.EnvVar{{Name: "FOO", Value: "bar"}}, nil
+		},
+		MountsFunc: func() ([]discover.Mount, error) {
+			return nil, errors.New("mount discovery failed")
+		},
+		HooksFunc: func() ([]discover.Hook, error) {
+			return []discover.Hook{{Lifecycle: "createContainer", Path: "/bin/hook"}}, nil
+		},
+	}
+
+	testCases := []struct {
+		description      string
+		options          []Option
+		expectedError    bool
+		expectedWarnings int
+	}{
+		{
+			description:   "errors are fatal by default",
+			expectedError: true,
+		},
+		{
+			description:      "errors are logged if tolerated",
+			options:          []Option{WithTolerateErrors(true)},
+			expectedWarnings: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger, hook := testlog.NewNullLogger()
+
+			edits, err := FromDiscoverer(d, append(tc.options, WithLogger(logger))...)
+			if tc.expectedError {
+				require.ErrorContains(t, err, "mount discovery failed")
+				require.Nil(t, edits)
+				return
+			}
+			require.NoError(t, err)
+
+			require.EqualValues(t, []*specs.DeviceNode{{Path: "/dev/nvidia0"}}, edits.DeviceNodes)
+			require.EqualValues(t, []string{"FOO=bar"}, edits.Env)
+			require.Empty(t, edits.Mounts)
+			require.EqualValues(t, []*specs.Hook{{HookName: "createContainer", Path: "/bin/hook"}}, edits.Hooks)
+
+			var warnings int
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					require.Contains(t, entry.Message, "mount discovery failed")
+					warnings++
+				}
+			}
+			require.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestFromDiscovererMountOptionsTransform(t *testing.T) {
+	d := &discover.DiscoverMock{
+		MountsFunc: func() ([]discover.Mount, error) {
+			return []discover.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/usr/lib/libcuda.so.1", Options: []string{"rw", "bind"}},
+				{HostPath: "/host/lib/libnvidia-ml.so", Path: "/usr/lib/libnvidia-ml.so"},
+				{HostPath: "/dev/nvidia-caps", Path: "/dev/nvidia-caps", Options: []string{"rw", "bind"}},
+			}, nil
+		},
+	}
+
+	testCases := []struct {
+		description    string
+		options        []Option
+		expectedMounts []*specs.Mount
+	}{
+		{
+			description: "mount options are unchanged by default",
+			expectedMounts: []*specs.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/usr/lib/libcuda.so.1", Options: []string{"rw", "bind"}},
+				{HostPath: "/host/lib/libnvidia-ml.so", ContainerPath: "/usr/lib/libnvidia-ml.so"},
+				{HostPath: "/dev/nvidia-caps", ContainerPath: "/dev/nvidia-caps", Options: []string{"rw", "bind"}},
+			},
+		},
+		{
+			description: "library mounts are made read-only",
+			options:     []Option{WithMountOptionsTransform(ReadOnlyLibraries)},
+			expectedMounts: []*specs.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/usr/lib/libcuda.so.1", Options: []string{"ro", "nosuid", "nodev", "bind"}},
+				{HostPath: "/host/lib/libnvidia-ml.so", ContainerPath: "/usr/lib/libnvidia-ml.so", Options: []string{"ro", "nosuid", "nodev"}},
+				{HostPath: "/dev/nvidia-caps", ContainerPath: "/dev/nvidia-caps", Options: []string{"rw", "bind"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			edits, err := FromDiscoverer(d, tc.options...)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedMounts, edits.Mounts)
+		})
+	}
+}
+
+func TestFromDiscovererHookPaths(t *testing.T) {
+	hookDir := t.TempDir()
+	executable := filepath.Join(hookDir, "nvidia-cdi-hook")
+	require.NoError(t, os.WriteFile(executable, nil, 0755))
+	notExecutable := filepath.Join(hookDir, "not-executable")
+	require.NoError(t, os.WriteFile(notExecutable, nil, 0644))
+
+	testCases := []struct {
+		description   string
+		hookPath      string
+		options       []Option
+		expectedError bool
+	}{
+		{
+			description:   "relative path is invalid",
+			hookPath:      "nvidia-cdi-hook",
+			expectedError: true,
+		},
+		{
+			description: "missing path is valid by default",
+			hookPath:    filepath.Join(hookDir, "missing"),
+		},
+		{
+			description:   "missing path is invalid if verified",
+			hookPath:      filepath.Join(hookDir, "missing"),
+			options:       []Option{WithVerifyHookPaths(true)},
+			expectedError: true,
+		},
+		{
+			description:   "directory is invalid if verified",
+			hookPath:      hookDir,
+			options:       []Option{WithVerifyHookPaths(true)},
+			expectedError: true,
+		},
+		{
+			description:   "non-executable path is invalid if verified",
+			hookPath:      notExecutable,
+			options:       []Option{WithVerifyHookPaths(true)},
+			expectedError: true,
+		},
+		{
+			description: "executable path is valid if verified",
+			hookPath:    executable,
+			options:     []Option{WithVerifyHookPaths(true)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := &discover.DiscoverMock{
+				HooksFunc: func() ([]discover.Hook, error) {
+					return []discover.Hook{{Lifecycle: "createContainer", Path: tc.hookPath}}, nil
+				},
+			}
+
+			edits, err := FromDiscoverer(d, tc.options...)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, edits.Hooks, 1)
+		})
+	}
+}
+
+func TestFromDiscovererExcludedHooks(t *testing.T) {
+	logger, logHook := testlog.NewNullLogger()
+
+	ldcacheHook := discover.Hook{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"}}
+	symlinksHook := discover.Hook{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"}}
+	prestartHook := discover.Hook{Lifecycle: "prestart", Path: "/usr/bin/nvidia-container-runtime-hook", Args: []string{"nvidia-container-runtime-hook", "prestart"}}
+	ctkChmodHook := discover.Hook{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-ctk", Args: []string{"nvidia-ctk", "hook", "chmod", "--mode", "755", "--path", "/dev/dri"}}
+
+	testCases := []struct {
+		description      string
+		excluded         []string
+		expectedHooks    []*specs.Hook
+		expectedExcluded int
+	}{
+		{
+			description: "no hooks are excluded by default",
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ctkChmodHook.Path, Args: ctkChmodHook.Args},
+			},
+		},
+		{
+			description: "update-ldcache hook is excluded",
+			excluded:    []string{"update-ldcache"},
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ctkChmodHook.Path, Args: ctkChmodHook.Args},
+			},
+			expectedExcluded: 1,
+		},
+		{
+			description: "hooks are excluded by executable name",
+			excluded:    []string{"nvidia-container-runtime-hook", "create-symlinks"},
+			expectedHooks: []*specs.Hook{
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: ctkChmodHook.Path, Args: ctkChmodHook.Args},
+			},
+			expectedExcluded: 2,
+		},
+		{
+			description: "nvidia-ctk hook is excluded by subcommand",
+			excluded:    []string{"chmod"},
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+			},
+			expectedExcluded: 1,
+		},
+		{
+			description: "nvidia-ctk hooks are not excluded by executable or hook command",
+			excluded:    []string{"nvidia-ctk", "hook"},
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ctkChmodHook.Path, Args: ctkChmodHook.Args},
+			},
+		},
+		{
+			description: "unknown names are ignored",
+			excluded:    []string{"disable-device-node-modification"},
+			expectedHooks: []*specs.Hook{
+				{HookName: "prestart", Path: prestartHook.Path, Args: prestartHook.Args},
+				{HookName: "createContainer", Path: ldcacheHook.Path, Args: ldcacheHook.Args},
+				{HookName: "createContainer", Path: symlinksHook.Path, Args: symlinksHook.Args},
+				{HookName: "createContainer", Path: ctkChmodHook.Path, Args: ctkChmodHook.Args},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logHook.Reset()
+			d := &discover.DiscoverMock{
+				HooksFunc: func() ([]discover.Hook, error) {
+					return []discover.Hook{ldcacheHook, symlinksHook, prestartHook, ctkChmodHook}, nil
+				},
+			}
+
+			edits, err := FromDiscoverer(d, WithLogger(logger), WithExcludedHooks(tc.excluded...))
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedHooks, edits.Hooks)
+
+			var excludedMessages int
+			for _, entry := range logHook.AllEntries() {
+				if strings.HasPrefix(entry.Message, "Excluding") {
+					excludedMessages++
+				}
+			}
+			require.Equal(t, tc.expectedExcluded, excludedMessages)
+		})
+	}
+}
+
+func TestModifyLogging(t *testing.T) {
+	d := &discover.DiscoverMock{
+		DevicesFunc: func() ([]discover.Device, error) {
+			return nil, nil
+		},
+		MountsFunc: func() ([]discover.Mount, error) {
+			return []discover.Mount{
+				{HostPath: "/host/lib/libcuda.so.1", Path: "/lib/libcuda.so.1"},
+				{HostPath: "/host/lib/libnvidia-ml.so.1", Path: "/lib/libnvidia-ml.so.1"},
+			}, nil
+		},
+		HooksFunc: func() ([]discover.Hook, error) {
+			return []discover.Hook{
+				{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}},
+			}, nil
+		},
+	}
+
+	testCases := []struct {
+		description     string
+		options         []Option
+		expectedInfo    int
+		expectedDebug   int
+		expectedSummary string
+	}{
+		{
+			description:     "individual edits are logged at debug level by default",
+			expectedInfo:    1,
+			expectedDebug:   3,
+			expectedSummary: "Injecting 2 mounts, 0 devices, 1 hooks",
+		},
+		{
+			description:     "verbose logging logs individual edits at info level",
+			options:         []Option{WithVerboseLogging(true)},
+			expectedInfo:    4,
+			expectedSummary: "Injecting 2 mounts, 0 devices, 1 hooks",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger, hook := testlog.NewNullLogger()
+			logger.SetLevel(logrus.DebugLevel)
+
+			e, err := NewSpecEdits(logger, d, tc.options...)
+			require.NoError(t, err)
+
+			err = e.Modify(&ociSpecs.Spec{})
+			require.NoError(t, err)
+
+			var info, debug int
+			for _, entry := range hook.AllEntries() {
+				switch entry.Level {
+				case logrus.InfoLevel:
+					info++
+				case logrus.DebugLevel:
+					debug++
+				}
+			}
+			require.Equal(t, tc.expectedInfo, info)
+			require.Equal(t, tc.expectedDebug, debug)
+			require.Equal(t, tc.expectedSummary, hook.AllEntries()[0].Message)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"fmt"
+	"strings"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+)
+
+type envvar discover.EnvVar
+
+// toEdits converts a discovered environment variable to CDI Container Edits.
+func (d envvar) toEdits() *cdi.ContainerEdits {
+	e := cdi.ContainerEdits{
+		ContainerEdits: &specs.ContainerEdits{
+			Env: []string{d.String()},
+		},
+	}
+	return &e
+}
+
+// String returns the environment variable in the NAME=VALUE form.
+func (d envvar) String() string {
+	return fmt.Sprintf("%v=%v", d.Name, d.Value)
+}
+
+// pathListEnvvars are the environment variables that hold path lists. When
+// these are injected, the value is appended to any existing value.
+var pathListEnvvars = map[string]bool{
+	"LD_LIBRARY_PATH": true,
+}
+
+// AppendToExistingEnv returns the specified environment variables with the
+// values of path list variables such as LD_LIBRARY_PATH appended to the value
+// already defined in the OCI spec. This ensures that the value defined by the
+// container image is not replaced when the edits are applied.
+func AppendToExistingEnv(env []string, spec *ociSpecs.Spec) []string {
+	if spec == nil || spec.Process == nil || len(env) == 0 {
+		return env
+	}
+
+	existing := make(map[string]string)
+	for _, e := range spec.Process.Env {
+		name, value, _ := strings.Cut(e, "=")
+		existing[name] = value
+	}
+
+	var updated []string
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		if current := existing[name]; pathListEnvvars[name] && current != "" && value != "" {
+			e = name + "=" + current + ":" + value
+		}
+		updated = append(updated, e)
+	}
+	return updated
+}
@@ -17,6 +17,10 @@
 package edits
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 	"tags.cncf.io/container-device-interface/specs-go"
 
@@ -46,3 +50,40 @@ func (d hook) toSpec() *specs.Hook {
 
 	return &s
 }
+
+// validate checks that the path of the hook is absolute. If verifyPath is
+// specified the path is also required to refer to an executable file.
+func (d hook) validate(verifyPath bool) error {
+	if !filepath.IsAbs(d.Path) {
+		return fmt.Errorf("hook path %q is not absolute", d.Path)
+	}
+	if !verifyPath {
+		return nil
+	}
+	info, err := os.Stat(d.Path)
+	if err != nil {
+		return fmt.Errorf("invalid hook path: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("hook path %q is a directory", d.Path)
+	}
+	if info.Mode()&0111 == 0 {
+		return fmt.Errorf("hook path %q is not executable", d.Path)
+	}
+	return nil
+}
+
+// isExcluded checks whether the hook matches any of the specified names. A
+// hook matches a name if either its subcommand or the name of its executable
+// are equal to the name. Since nvidia-ctk is used to invoke all hook
+// subcommands, hooks invoked through nvidia-ctk only match their subcommand.
+func (d hook) isExcluded(excluded map[string]bool) bool {
+	if len(excluded) == 0 {
+		return false
+	}
+	if subcommand := discover.Hook(d).Subcommand(); subcommand != "" && excluded[subcommand] {
+		return true
+	}
+	executable := filepath.Base(d.Path)
+	return executable != "nvidia-ctk" && excluded[executable]
+}
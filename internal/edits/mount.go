@@ -17,6 +17,9 @@
 package edits
 
 import (
+	"path/filepath"
+	"strings"
+
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 	"tags.cncf.io/container-device-interface/specs-go"
 
@@ -25,6 +28,34 @@ import (
 
 type mount discover.Mount
 
+// A MountOptionsTransform returns the options to use for the specified mount.
+type MountOptionsTransform func(discover.Mount) []string
+
+// ReadOnlyLibraries is a MountOptionsTransform that ensures that library mounts
+// are read-only by replacing an "rw" option with "ro,nosuid,nodev". The options
+// of other mounts are not modified.
+func ReadOnlyLibraries(m discover.Mount) []string {
+	if !isLibraryPath(m.Path) {
+		return m.Options
+	}
+
+	options := []string{"ro", "nosuid", "nodev"}
+	for _, option := range m.Options {
+		switch option {
+		case "ro", "rw", "nosuid", "nodev":
+			continue
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+// isLibraryPath checks whether the specified path refers to a shared library.
+func isLibraryPath(path string) bool {
+	base := filepath.Base(path)
+	return strings.HasSuffix(base, ".so") || strings.Contains(base, ".so.")
+}
+
 // toEdits converts a discovered mount to CDI Container Edits.
 func (d mount) toEdits() *cdi.ContainerEdits {
 	e := cdi.ContainerEdits{
@@ -0,0 +1,116 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import "github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+
+type options struct {
+	logger                 logger.Interface
+	populateDeviceNodeInfo bool
+	verboseLogging         bool
+	tolerateErrors         bool
+	simplify               bool
+	mountOptionsTransform  MountOptionsTransform
+	verifyHookPaths        bool
+	excludedHooks          map[string]bool
+}
+
+// Option defines a function for passing options to the FromDiscoverer and
+// NewSpecEdits calls.
+type Option func(*options)
+
+// WithLogger sets the logger used when constructing the container edits.
+func WithLogger(logger logger.Interface) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithPopulateDeviceNodeInfo sets whether the type, major, and minor numbers of
+// device nodes are determined when the edits are constructed. By default these
+// are left empty and are filled in when the edits are applied. This is useful
+// for specs that are generated on a host where the device nodes are available,
+// but are applied where they are not.
+func WithPopulateDeviceNodeInfo(populate bool) Option {
+	return func(o *options) {
+		o.populateDeviceNodeInfo = populate
+	}
+}
+
+// WithVerboseLogging sets whether the individual mounts, devices, and hooks are
+// logged at info level when the edits are applied. By default only a summary
+// is logged at info level with the individual edits logged at debug level.
+func WithVerboseLogging(verbose bool) Option {
+	return func(o *options) {
+		o.verboseLogging = verbose
+	}
+}
+
+// WithMountOptionsTransform sets a transform that is applied to the options of
+// each discovered mount when constructing the container edits. This allows a
+// policy such as ReadOnlyLibraries to be enforced regardless of the options set
+// by the discoverer. By default the discovered options are used as is.
+func WithMountOptionsTransform(transform MountOptionsTransform) Option {
+	return func(o *options) {
+		o.mountOptionsTransform = transform
+	}
+}
+
+// WithSimplify sets whether redundant container edits are removed and the
+// remaining edits sorted when they are constructed or applied. See Simplify for
+// details.
+func WithSimplify(simplify bool) Option {
+	return func(o *options) {
+		o.simplify = simplify
+	}
+}
+
+// WithTolerateErrors sets whether errors discovering devices, environment
+// variables, mounts, or hooks are tolerated when constructing container edits.
+// If set, failures are logged as warnings and the edits are constructed from
+// the discovery steps that succeeded. By default an error is returned.
+func WithTolerateErrors(tolerateErrors bool) Option {
+	return func(o *options) {
+		o.tolerateErrors = tolerateErrors
+	}
+}
+
+// WithVerifyHookPaths sets whether the paths of discovered hooks are required to
+// exist and be executable on the host. Hook paths are always required to be
+// absolute, but since specs may be generated on a different host to where they
+// are used, the existence check is disabled by default.
+func WithVerifyHookPaths(verify bool) Option {
+	return func(o *options) {
+		o.verifyHookPaths = verify
+	}
+}
+
+// WithExcludedHooks sets the names of discovered hooks that are not included in
+// the container edits. A name matches either the subcommand of a hook (e.g.
+// update-ldcache for the nvidia-cdi-hook update-ldcache hook) or the name of
+// the hook executable. Hooks invoked through nvidia-ctk only match their
+// subcommand. Each excluded hook is logged.
+func WithExcludedHooks(names ...string) Option {
+	return func(o *options) {
+		if o.excludedHooks == nil {
+			o.excludedHooks = make(map[string]bool)
+		}
+		for _, name := range names {
+			o.excludedHooks[name] = true
+		}
+	}
+}
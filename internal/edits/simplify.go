@@ -0,0 +1,118 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+// Simplify removes redundant entries from the container edits and sorts the
+// remaining entries deterministically:
+//   - Device nodes with the same container path are removed, keeping the first.
+//     Device nodes are sorted by container path.
+//   - Environment variables with the same name are removed, keeping the last
+//     since this is the value that would take effect. Environment variables are
+//     sorted by name.
+//   - Mounts that are shadowed by a later mount to the same container path are
+//     removed. Mounts are sorted by container path which ensures that parent
+//     directories are mounted before their children.
+//   - Identical hooks are removed, keeping the first. Hooks are ordered by
+//     lifecycle stage, retaining their relative order within a stage.
+func (e *edits) Simplify() {
+	if e == nil || e.ContainerEdits.ContainerEdits == nil {
+		return
+	}
+	e.DeviceNodes = simplifyDeviceNodes(e.DeviceNodes)
+	e.Env = simplifyEnv(e.Env)
+	e.Mounts = simplifyMounts(e.Mounts)
+	e.Hooks = simplifyHooks(e.Hooks)
+}
+
+func simplifyDeviceNodes(deviceNodes []*specs.DeviceNode) []*specs.DeviceNode {
+	seen := make(map[string]bool)
+	var simplified []*specs.DeviceNode
+	for _, d := range deviceNodes {
+		if seen[d.Path] {
+			continue
+		}
+		seen[d.Path] = true
+		simplified = append(simplified, d)
+	}
+	sort.SliceStable(simplified, func(i, j int) bool {
+		return simplified[i].Path < simplified[j].Path
+	})
+	return simplified
+}
+
+func simplifyEnv(env []string) []string {
+	values := make(map[string]string)
+	var names []string
+	for _, e := range env {
+		name := strings.SplitN(e, "=", 2)[0]
+		if _, exists := values[name]; !exists {
+			names = append(names, name)
+		}
+		values[name] = e
+	}
+	sort.Strings(names)
+
+	var simplified []string
+	for _, name := range names {
+		simplified = append(simplified, values[name])
+	}
+	return simplified
+}
+
+func simplifyMounts(mounts []*specs.Mount) []*specs.Mount {
+	last := make(map[string]int)
+	for i, m := range mounts {
+		last[filepath.Clean(m.ContainerPath)] = i
+	}
+
+	var simplified []*specs.Mount
+	for i, m := range mounts {
+		if last[filepath.Clean(m.ContainerPath)] != i {
+			continue
+		}
+		simplified = append(simplified, m)
+	}
+	sort.SliceStable(simplified, func(i, j int) bool {
+		return filepath.Clean(simplified[i].ContainerPath) < filepath.Clean(simplified[j].ContainerPath)
+	})
+	return simplified
+}
+
+func simplifyHooks(hooks []*specs.Hook) []*specs.Hook {
+	seen := make(map[string]bool)
+	var simplified []*specs.Hook
+	for _, h := range hooks {
+		key := strings.Join(append([]string{h.HookName, h.Path}, h.Args...), "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		simplified = append(simplified, h)
+	}
+	sort.SliceStable(simplified, func(i, j int) bool {
+		return hookLifecycleRank(simplified[i].HookName) < hookLifecycleRank(simplified[j].HookName)
+	})
+	return simplified
+}
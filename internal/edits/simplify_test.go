@@ -0,0 +1,129 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package edits
+
+import (
+	"testing"
+
+	ociSpecs "github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestSimplify(t *testing.T) {
+	testCases := []struct {
+		description string
+		edits       *specs.ContainerEdits
+		expected    *specs.ContainerEdits
+	}{
+		{
+			description: "empty edits are unchanged",
+			edits:       &specs.ContainerEdits{},
+			expected:    &specs.ContainerEdits{},
+		},
+		{
+			description: "messy edits are simplified",
+			edits: &specs.ContainerEdits{
+				Env: []string{
+					"NVIDIA_VISIBLE_DEVICES=void",
+					"LD_LIBRARY_PATH=/usr/lib",
+					"NVIDIA_VISIBLE_DEVICES=all",
+				},
+				DeviceNodes: []*specs.DeviceNode{
+					{Path: "/dev/nvidiactl"},
+					{Path: "/dev/nvidia0"},
+					{Path: "/dev/nvidiactl", HostPath: "/host/dev/nvidiactl"},
+				},
+				Mounts: []*specs.Mount{
+					{HostPath: "/host/lib/libcuda.so.1", ContainerPath: "/usr/lib/libcuda.so.1"},
+					{HostPath: "/host/lib", ContainerPath: "/usr/lib/"},
+					{HostPath: "/host/lib/libcuda.so.2", ContainerPath: "/usr/lib/libcuda.so.1"},
+					{HostPath: "/host/bin", ContainerPath: "/usr/bin"},
+				},
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "create-symlinks"}},
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache"}},
+					{HookName: "prestart", Path: "/bin/prestart"},
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "create-symlinks"}},
+				},
+			},
+			expected: &specs.ContainerEdits{
+				Env: []string{
+					"LD_LIBRARY_PATH=/usr/lib",
+					"NVIDIA_VISIBLE_DEVICES=all",
+				},
+				DeviceNodes: []*specs.DeviceNode{
+					{Path: "/dev/nvidia0"},
+					{Path: "/dev/nvidiactl"},
+				},
+				Mounts: []*specs.Mount{
+					{HostPath: "/host/bin", ContainerPath: "/usr/bin"},
+					{HostPath: "/host/lib", ContainerPath: "/usr/lib/"},
+					{HostPath: "/host/lib/libcuda.so.2", ContainerPath: "/usr/lib/libcuda.so.1"},
+				},
+				Hooks: []*specs.Hook{
+					{HookName: "prestart", Path: "/bin/prestart"},
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "create-symlinks"}},
+					{HookName: "createContainer", Path: "/bin/hook", Args: []string{"hook", "update-ldcache"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			e := &edits{
+				ContainerEdits: cdi.ContainerEdits{ContainerEdits: tc.edits},
+			}
+			e.Simplify()
+			require.EqualValues(t, tc.expected, e.ContainerEdits.ContainerEdits)
+
+			// Simplifying the edits again must not change them.
+			e.Simplify()
+			require.EqualValues(t, tc.expected, e.ContainerEdits.ContainerEdits)
+		})
+	}
+}
+
+func TestModifySimplify(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	newEdits := func(simplify bool) *edits {
+		return &edits{
+			ContainerEdits: cdi.ContainerEdits{
+				ContainerEdits: &specs.ContainerEdits{
+					Hooks: []*specs.Hook{
+						{HookName: "createContainer", Path: "/bin/hook"},
+						{HookName: "createContainer", Path: "/bin/hook"},
+					},
+				},
+			},
+			logger:   logger,
+			simplify: simplify,
+		}
+	}
+
+	spec := &ociSpecs.Spec{}
+	require.NoError(t, newEdits(false).Modify(spec))
+	require.Len(t, spec.Hooks.CreateContainer, 2)
+
+	spec = &ociSpecs.Spec{}
+	require.NoError(t, newEdits(true).Modify(spec))
+	require.Len(t, spec.Hooks.CreateContainer, 1)
+}
@@ -0,0 +1,67 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package logger
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatText selects the default logrus text formatter.
+	FormatText = "text"
+	// FormatJSON selects the logrus JSON formatter.
+	FormatJSON = "json"
+)
+
+// SetFormat sets the formatter of the specified logger. An empty format is
+// equivalent to FormatText. For FormatJSON, the specified fields (e.g. the
+// component and operation) are added as structured keys to each entry.
+func SetFormat(l *logrus.Logger, format string, fields logrus.Fields) error {
+	switch format {
+	case "", FormatText:
+		return nil
+	case FormatJSON:
+		l.SetFormatter(&logrus.JSONFormatter{})
+		if len(fields) > 0 {
+			l.AddHook(fieldsHook(fields))
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported log format %q; expected one of %v, %v", format, FormatText, FormatJSON)
+}
+
+// fieldsHook adds a fixed set of fields to each log entry. Fields that are
+// already set on an entry are not overridden.
+type fieldsHook logrus.Fields
+
+// Levels returns all log levels.
+func (h fieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds the fields to the specified entry.
+func (h fieldsHook) Fire(entry *logrus.Entry) error {
+	for k, v := range h {
+		if _, ok := entry.Data[k]; ok {
+			continue
+		}
+		entry.Data[k] = v
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFormat(t *testing.T) {
+	testCases := []struct {
+		description   string
+		format        string
+		expectedJSON  bool
+		expectedError bool
+	}{
+		{
+			description: "empty format is text",
+		},
+		{
+			description: "text format",
+			format:      FormatText,
+		},
+		{
+			description:  "json format",
+			format:       FormatJSON,
+			expectedJSON: true,
+		},
+		{
+			description:   "unsupported format",
+			format:        "yaml",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			l := logrus.New()
+			l.SetOutput(output)
+
+			err := SetFormat(l, tc.format, logrus.Fields{"component": "toolkit", "operation": "install"})
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			l.Infof("Installing %v", "toolkit")
+			l.WithField("operation", "override").Warningf("Warning")
+
+			lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+			require.Len(t, lines, 2)
+
+			if !tc.expectedJSON {
+				require.Contains(t, lines[0], `msg="Installing toolkit"`)
+				require.NotContains(t, lines[0], "component")
+				return
+			}
+
+			var entries []map[string]interface{}
+			for _, line := range lines {
+				entry := make(map[string]interface{})
+				require.NoError(t, json.Unmarshal([]byte(line), &entry))
+				entries = append(entries, entry)
+			}
+
+			require.Equal(t, "Installing toolkit", entries[0]["msg"])
+			require.Equal(t, "info", entries[0]["level"])
+			require.Equal(t, "toolkit", entries[0]["component"])
+			require.Equal(t, "install", entries[0]["operation"])
+
+			require.Equal(t, "warning", entries[1]["level"])
+			require.Equal(t, "toolkit", entries[1]["component"])
+			require.Equal(t, "override", entries[1]["operation"])
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import "strings"
+
+// expandBraces expands the brace expressions in the specified pattern into a
+// list of patterns. For example, lib{cuda,nvidia-ml}.so.* is expanded to
+// libcuda.so.* and libnvidia-ml.so.*. Brace expressions may be nested.
+// Braces that are unmatched or that do not contain a top-level comma are
+// treated as literals as is the case for shell brace expansion.
+func expandBraces(pattern string) []string {
+	start, end, alternatives := findBraceExpression(pattern)
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	prefix := pattern[:start]
+	var expanded []string
+	for _, suffix := range expandBraces(pattern[end+1:]) {
+		for _, alternative := range alternatives {
+			for _, a := range expandBraces(alternative) {
+				expanded = append(expanded, prefix+a+suffix)
+			}
+		}
+	}
+	return expanded
+}
+
+// findBraceExpression finds the first brace expression in the specified
+// pattern that contains at least one top-level comma. The start and end
+// indices of the braces as well as the comma-separated alternatives are
+// returned. If no such expression exists, start is -1.
+func findBraceExpression(pattern string) (int, int, []string) {
+	for start := 0; start < len(pattern); start++ {
+		if pattern[start] != '{' {
+			continue
+		}
+		depth := 0
+		commas := []int{}
+		for i := start; i < len(pattern); i++ {
+			switch pattern[i] {
+			case '{':
+				depth++
+			case ',':
+				if depth == 1 {
+					commas = append(commas, i)
+				}
+			case '}':
+				depth--
+			}
+			if depth != 0 {
+				continue
+			}
+			if len(commas) == 0 {
+				// A brace expression without alternatives is a literal.
+				break
+			}
+			var alternatives []string
+			last := start + 1
+			for _, comma := range commas {
+				alternatives = append(alternatives, pattern[last:comma])
+				last = comma + 1
+			}
+			alternatives = append(alternatives, pattern[last:i])
+			return start, i, alternatives
+		}
+	}
+	return -1, -1, nil
+}
+
+// expandPattern returns the patterns to search for the specified pattern. If
+// brace expansion is enabled, the brace expressions in the pattern are
+// expanded.
+func (o builder) expandPattern(pattern string) []string {
+	if !o.braceExpansion || !strings.Contains(pattern, "{") {
+		return []string{pattern}
+	}
+	return expandBraces(pattern)
+}
@@ -0,0 +1,123 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandBraces(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{
+			pattern:  "libcuda.so.*",
+			expected: []string{"libcuda.so.*"},
+		},
+		{
+			pattern:  "lib{cuda,nvidia-ml}.so.*",
+			expected: []string{"libcuda.so.*", "libnvidia-ml.so.*"},
+		},
+		{
+			pattern:  "lib{a,b}.so.{1,2}",
+			expected: []string{"liba.so.1", "libb.so.1", "liba.so.2", "libb.so.2"},
+		},
+		{
+			pattern:  "lib{nvidia-{ml,cfg},cuda}.so",
+			expected: []string{"libnvidia-ml.so", "libnvidia-cfg.so", "libcuda.so"},
+		},
+		{
+			pattern:  "lib{,32}.so",
+			expected: []string{"lib.so", "lib32.so"},
+		},
+		{
+			pattern:  "lib{cuda}.so",
+			expected: []string{"lib{cuda}.so"},
+		},
+		{
+			pattern:  "lib{cuda,nvidia-ml.so",
+			expected: []string{"lib{cuda,nvidia-ml.so"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			require.EqualValues(t, tc.expected, expandBraces(tc.pattern))
+		})
+	}
+}
+
+func TestLocatorBraceExpansion(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	libDir := filepath.Join(root, "usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	for _, lib := range []string{"libcuda.so.550.54.15", "libnvidia-ml.so.550.54.15", "libnvidia-cfg.so.550.54.15"} {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, lib), nil, 0644))
+	}
+
+	expected := []string{
+		filepath.Join(libDir, "libcuda.so.550.54.15"),
+		filepath.Join(libDir, "libnvidia-ml.so.550.54.15"),
+	}
+
+	testCases := []struct {
+		description string
+		newLocator  func(...Option) Locator
+	}{
+		{
+			description: "file locator",
+			newLocator:  NewFileLocator,
+		},
+		{
+			description: "library locator",
+			newLocator: func(opts ...Option) Locator {
+				return NewLibraryLocator(append(opts, WithSearchPaths(libDir))...)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := tc.newLocator(
+				WithLogger(logger),
+				WithRoot(root),
+				WithSearchPaths("/usr/lib64"),
+				WithBraceExpansion(true),
+			)
+			located, err := l.Locate("lib{cuda,nvidia-ml}.so.*")
+			require.NoError(t, err)
+			require.ElementsMatch(t, expected, located)
+
+			// Brace expansion is disabled by default.
+			l = tc.newLocator(
+				WithLogger(logger),
+				WithRoot(root),
+				WithSearchPaths("/usr/lib64"),
+			)
+			_, err = l.Locate("lib{cuda,nvidia-ml}.so.*")
+			require.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
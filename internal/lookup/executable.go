@@ -68,6 +68,21 @@ func (p executable) Locate(pattern string) ([]string, error) {
 	return p.file.Locate(pattern)
 }
 
+// LocateWithOrigin finds executable files with the specified pattern in the
+// path and returns these along with the search path in which they were found.
+// If a relative or absolute path is specified, the search path is empty.
+func (p executable) LocateWithOrigin(pattern string) ([]Located, error) {
+	if strings.Contains(pattern, "/") {
+		paths, err := p.Locate(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return []Located{{Path: paths[0]}}, nil
+	}
+
+	return p.file.LocateWithOrigin(pattern)
+}
+
 // assertExecutable checks whether the specified path is an execuable file.
 func assertExecutable(filename string) error {
 	err := assertFile(filename)
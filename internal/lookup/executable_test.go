@@ -18,6 +18,8 @@ package lookup
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	testlog "github.com/sirupsen/logrus/hooks/test"
@@ -75,3 +77,33 @@ func TestExecutableLocator(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutableLocatorWithOrigin(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	for _, dir := range []string{"/usr/local/bin", "/usr/bin"} {
+		binDir := filepath.Join(root, dir)
+		require.NoError(t, os.MkdirAll(binDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "nvidia-smi"), nil, 0755))
+	}
+
+	e := newExecutableLocator(logger, root, "/usr/local/bin", "/usr/bin")
+
+	located, err := e.LocateWithOrigin("nvidia-smi")
+	require.NoError(t, err)
+	require.EqualValues(t,
+		[]Located{
+			{
+				Path:       filepath.Join(root, "/usr/local/bin/nvidia-smi"),
+				SearchPath: filepath.Join(root, "/usr/local/bin"),
+			},
+		},
+		located,
+	)
+
+	absolute := filepath.Join(root, "/usr/bin/nvidia-smi")
+	located, err = e.LocateWithOrigin(absolute)
+	require.NoError(t, err)
+	require.EqualValues(t, []Located{{Path: absolute}}, located)
+}
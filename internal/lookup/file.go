@@ -20,10 +20,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
+// defaultMaxSymlinkDepth is the default maximum number of symlinks that are
+// followed when resolving a located path. This matches the limit used by the
+// Linux kernel.
+const defaultMaxSymlinkDepth = 40
+
 // file can be used to locate file (or file-like elements) at a specified set of
 // prefixes. The validity of a file is determined by a filter function.
 type file struct {
@@ -39,6 +46,21 @@ type builder struct {
 	filter      func(string) error
 	count       int
 	isOptional  bool
+	// preferredArch specifies the architecture (as a GOARCH value) for which
+	// search paths are preferred when a count is specified.
+	preferredArch string
+	// sonameFallback specifies whether a library locator falls back to
+	// matching libraries by their ELF SONAME.
+	sonameFallback bool
+	// maxSymlinkDepth specifies the maximum number of symlinks that are
+	// followed when resolving a located path.
+	maxSymlinkDepth int
+	// braceExpansion specifies whether brace expressions in patterns are
+	// expanded.
+	braceExpansion bool
+	// layers specifies the layer directories of a layered (e.g. overlay)
+	// root ordered from the uppermost to the lowest layer.
+	layers []string
 }
 
 // Option defines a function for passing builder to the NewFileLocator() call
@@ -88,6 +110,56 @@ func WithOptional(optional bool) Option {
 	}
 }
 
+// WithPreferredArch sets the architecture (as a GOARCH value such as amd64 or
+// arm64) for which search paths are preferred if a count is specified. Search
+// paths that are specific to other architectures are searched last so that a
+// library for the wrong architecture is not returned in favor of a matching
+// one. If this is not specified, the host architecture is used.
+func WithPreferredArch(arch string) Option {
+	return func(f *builder) {
+		f.preferredArch = arch
+	}
+}
+
+// WithSonameFallback sets whether a library locator falls back to matching
+// libraries by the SONAME in their ELF headers if no library with a matching
+// filename is found.
+func WithSonameFallback(fallback bool) Option {
+	return func(f *builder) {
+		f.sonameFallback = fallback
+	}
+}
+
+// WithMaxSymlinkDepth sets the maximum number of symlinks that are followed
+// when resolving a located path. If this is exceeded, for example due to a
+// symlink loop in the driver root, resolution fails with ErrTooManySymlinks.
+// If this is not specified, defaultMaxSymlinkDepth is used.
+func WithMaxSymlinkDepth(depth int) Option {
+	return func(f *builder) {
+		f.maxSymlinkDepth = depth
+	}
+}
+
+// WithBraceExpansion sets whether brace expressions in located patterns are
+// expanded. If enabled, a pattern such as lib{cuda,nvidia-ml}.so.* is
+// searched as the patterns libcuda.so.* and libnvidia-ml.so.*.
+func WithBraceExpansion(braceExpansion bool) Option {
+	return func(f *builder) {
+		f.braceExpansion = braceExpansion
+	}
+}
+
+// WithLayers sets the layer directories of a layered root such as an overlay
+// mount. The layers are ordered from the uppermost to the lowest layer as is
+// the case for the lowerdir option of an overlay mount. If layers are
+// specified, a library locator searches the layers individually instead of the
+// merged root and reports libraries that are shadowed by upper layers.
+func WithLayers(layers ...string) Option {
+	return func(f *builder) {
+		f.layers = layers
+	}
+}
+
 func newBuilder(opts ...Option) *builder {
 	o := &builder{}
 	for _, opt := range opts {
@@ -99,15 +171,25 @@ func newBuilder(opts ...Option) *builder {
 	if o.filter == nil {
 		o.filter = assertFile
 	}
+	if o.preferredArch == "" {
+		o.preferredArch = runtime.GOARCH
+	}
+	if o.maxSymlinkDepth <= 0 {
+		o.maxSymlinkDepth = defaultMaxSymlinkDepth
+	}
 	return o
 }
 
 func (o builder) build() *file {
+	searchPaths := o.searchPaths
+	if o.count > 0 {
+		searchPaths = preferArch(o.preferredArch, searchPaths)
+	}
 	f := file{
 		builder: o,
 		// Since the `Locate` implementations rely on the root already being specified we update
 		// the prefixes to include the root.
-		prefixes: getSearchPrefixes(o.root, o.searchPaths...),
+		prefixes: getSearchPrefixes(o.root, searchPaths...),
 	}
 	return &f
 }
@@ -153,41 +235,104 @@ func getSearchPrefixes(root string, prefixes ...string) []string {
 	return uniquePrefixes
 }
 
+// archDirNames maps GOARCH values to the names used for architecture-specific
+// library directories.
+var archDirNames = map[string]string{
+	"amd64": "x86_64",
+	"arm64": "aarch64",
+}
+
+// preferArch reorders the specified search paths so that paths that are
+// specific to an architecture other than the preferred one are moved to the
+// end. The relative order of the paths is otherwise maintained.
+func preferArch(arch string, searchPaths []string) []string {
+	var preferred []string
+	var others []string
+	for _, path := range searchPaths {
+		if isOtherArchPath(arch, path) {
+			others = append(others, path)
+			continue
+		}
+		preferred = append(preferred, path)
+	}
+	return append(preferred, others...)
+}
+
+// isOtherArchPath checks whether the specified path refers to an
+// architecture-specific directory for an architecture other than arch.
+func isOtherArchPath(arch string, path string) bool {
+	for goarch, dirName := range archDirNames {
+		if goarch == arch {
+			continue
+		}
+		if strings.Contains(path, dirName) {
+			return true
+		}
+	}
+	return false
+}
+
 var _ Locator = (*file)(nil)
+var _ OriginLocator = (*file)(nil)
 
 // Locate attempts to find files with names matching the specified pattern.
 // All prefixes are searched and any matching candidates are returned. If no matches are found, an error is returned.
 func (p file) Locate(pattern string) ([]string, error) {
+	located, err := p.LocateWithOrigin(pattern)
+	if err != nil {
+		return nil, err
+	}
 	var filenames []string
+	for _, l := range located {
+		filenames = append(filenames, l.Path)
+	}
+	return filenames, nil
+}
+
+// LocateWithOrigin attempts to find files with names matching the specified
+// pattern and returns these along with the prefix in which they were found.
+func (p file) LocateWithOrigin(pattern string) ([]Located, error) {
+	var located []Located
+
+	patterns := p.expandPattern(pattern)
+	seen := make(map[string]bool)
 
 	p.logger.Debugf("Locating %q in %v", pattern, p.prefixes)
 visit:
 	for _, prefix := range p.prefixes {
-		pathPattern := filepath.Join(prefix, pattern)
-		candidates, err := filepath.Glob(pathPattern)
-		if err != nil {
-			p.logger.Debugf("Checking pattern '%v' failed: %v", pathPattern, err)
+		var candidates []string
+		for _, pattern := range patterns {
+			pathPattern := filepath.Join(prefix, pattern)
+			matches, err := filepath.Glob(pathPattern)
+			if err != nil {
+				p.logger.Debugf("Checking pattern '%v' failed: %v", pathPattern, err)
+			}
+			candidates = append(candidates, matches...)
 		}
 
 		for _, candidate := range candidates {
+			if seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
 			p.logger.Debugf("Checking candidate '%v'", candidate)
 			err := p.filter(candidate)
 			if err != nil {
 				p.logger.Debugf("Candidate '%v' does not meet requirements: %v", candidate, err)
 				continue
 			}
-			filenames = append(filenames, candidate)
-			if p.count > 0 && len(filenames) == p.count {
-				p.logger.Debugf("Found %d candidates; ignoring further candidates", len(filenames))
+			located = append(located, Located{Path: candidate, SearchPath: prefix})
+			if p.count > 0 && len(located) == p.count {
+				p.logger.Debugf("Found %d candidates; ignoring further candidates", len(located))
 				break visit
 			}
 		}
 	}
 
-	if !p.isOptional && len(filenames) == 0 {
+	if !p.isOptional && len(located) == 0 {
 		return nil, fmt.Errorf("pattern %v %w", pattern, ErrNotFound)
 	}
-	return filenames, nil
+	return located, nil
 }
 
 // assertFile checks whether the specified path is a regular file
@@ -18,8 +18,11 @@ package lookup
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	testlog "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,3 +83,58 @@ func TestGetSearchPrefixes(t *testing.T) {
 		})
 	}
 }
+
+func TestFileLocatorPreferredArch(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	for _, dir := range []string{"/usr/lib/x86_64-linux-gnu", "/usr/lib/aarch64-linux-gnu"} {
+		libDir := filepath.Join(root, dir)
+		require.NoError(t, os.MkdirAll(libDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, "libfoo.so.1"), nil, 0644))
+	}
+
+	testCases := []struct {
+		description string
+		arch        string
+		count       int
+		expected    []string
+	}{
+		{
+			description: "amd64 prefers x86_64",
+			arch:        "amd64",
+			count:       1,
+			expected:    []string{filepath.Join(root, "/usr/lib/x86_64-linux-gnu/libfoo.so.1")},
+		},
+		{
+			description: "arm64 prefers aarch64",
+			arch:        "arm64",
+			count:       1,
+			expected:    []string{filepath.Join(root, "/usr/lib/aarch64-linux-gnu/libfoo.so.1")},
+		},
+		{
+			description: "search path order is used without a count",
+			arch:        "arm64",
+			expected: []string{
+				filepath.Join(root, "/usr/lib/x86_64-linux-gnu/libfoo.so.1"),
+				filepath.Join(root, "/usr/lib/aarch64-linux-gnu/libfoo.so.1"),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := NewFileLocator(
+				WithLogger(logger),
+				WithRoot(root),
+				WithSearchPaths("/usr/lib/x86_64-linux-gnu", "/usr/lib/aarch64-linux-gnu"),
+				WithCount(tc.count),
+				WithPreferredArch(tc.arch),
+			)
+
+			located, err := l.Locate("libfoo.so.1")
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, located)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// layeredLocator locates libraries in the individual layers of a layered root
+// such as an overlay-mounted driver image. Since the layers are searched
+// individually, libraries in a lower layer that are shadowed by the same path
+// in an upper layer can be detected.
+type layeredLocator struct {
+	builder
+	// locators holds the library locator for each layer.
+	locators []Locator
+}
+
+var _ Locator = (*layeredLocator)(nil)
+
+// newLayeredLocator creates a locator for the layers configured in the
+// specified builder. The configured search paths (or the default library
+// search paths) are searched relative to each layer.
+func newLayeredLocator(b *builder) Locator {
+	searchPaths := b.searchPaths
+	if len(searchPaths) == 0 {
+		searchPaths = defaultLibrarySearchPaths
+	}
+
+	l := &layeredLocator{
+		builder: *b,
+	}
+	for _, layer := range b.layers {
+		l.locators = append(l.locators, NewSymlinkLocator(
+			WithLogger(b.logger),
+			WithRoot(layer),
+			WithSearchPaths(searchPaths...),
+			WithBraceExpansion(b.braceExpansion),
+			WithMaxSymlinkDepth(b.maxSymlinkDepth),
+		))
+	}
+	return l
+}
+
+// Locate finds the specified library in the configured layers. Libraries that
+// are shadowed by the same path in an upper layer are reported and skipped,
+// matching the merged view of the layers. If a count is specified, at most
+// this many libraries are returned.
+func (l layeredLocator) Locate(pattern string) ([]string, error) {
+	var located []string
+	for i, layer := range l.layers {
+		candidates, err := l.locators[i].Locate(pattern)
+		if err != nil {
+			l.logger.Debugf("Could not locate %v in layer %v: %v", pattern, layer, err)
+			continue
+		}
+		for _, candidate := range candidates {
+			if upper := l.shadowingLayer(i, candidate); upper != "" {
+				l.logger.Warningf("%v in layer %v is shadowed by layer %v", candidate, layer, upper)
+				continue
+			}
+			located = append(located, candidate)
+			if l.count > 0 && len(located) == l.count {
+				return located, nil
+			}
+		}
+	}
+
+	if len(located) == 0 {
+		return nil, fmt.Errorf("pattern %v not found in layers %v: %w", pattern, l.layers, ErrNotFound)
+	}
+	return located, nil
+}
+
+// shadowingLayer returns the uppermost layer above the layer with the
+// specified index that contains the specified path. If the path is not
+// shadowed, an empty string is returned.
+func (l layeredLocator) shadowingLayer(index int, path string) string {
+	relative, err := filepath.Rel(l.layers[index], path)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, "../") {
+		return ""
+	}
+	for _, upper := range l.layers[:index] {
+		if _, err := os.Lstat(filepath.Join(upper, relative)); err == nil {
+			return upper
+		}
+	}
+	return ""
+}
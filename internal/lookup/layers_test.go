@@ -0,0 +1,112 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayeredLibraryLocator(t *testing.T) {
+	testDir := t.TempDir()
+	upper := filepath.Join(testDir, "upper")
+	lower := filepath.Join(testDir, "lower")
+
+	for _, lib := range []string{
+		filepath.Join(upper, "/usr/lib64/libcuda.so.1"),
+		filepath.Join(lower, "/usr/lib64/libcuda.so.1"),
+		filepath.Join(lower, "/usr/lib64/libnvidia-ml.so.1"),
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(lib), 0755))
+		require.NoError(t, os.WriteFile(lib, nil, 0644))
+	}
+
+	testCases := []struct {
+		description      string
+		libname          string
+		count            int
+		expected         []string
+		expectedError    bool
+		expectedShadowed bool
+	}{
+		{
+			description:      "shadowed library is located in upper layer",
+			libname:          "libcuda.so.1",
+			expected:         []string{filepath.Join(upper, "/usr/lib64/libcuda.so.1")},
+			expectedShadowed: true,
+		},
+		{
+			description: "library is located in lower layer",
+			libname:     "libnvidia-ml.so.1",
+			expected:    []string{filepath.Join(lower, "/usr/lib64/libnvidia-ml.so.1")},
+		},
+		{
+			description: "libraries are located in all layers",
+			libname:     "lib*.so.1",
+			expected: []string{
+				filepath.Join(upper, "/usr/lib64/libcuda.so.1"),
+				filepath.Join(lower, "/usr/lib64/libnvidia-ml.so.1"),
+			},
+			expectedShadowed: true,
+		},
+		{
+			description: "count limits located libraries",
+			libname:     "lib*.so.1",
+			count:       1,
+			expected:    []string{filepath.Join(upper, "/usr/lib64/libcuda.so.1")},
+		},
+		{
+			description:   "missing library returns error",
+			libname:       "libnvidia-missing.so.1",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger, hook := testlog.NewNullLogger()
+
+			l := NewLibraryLocator(
+				WithLogger(logger),
+				WithLayers(upper, lower),
+				WithCount(tc.count),
+			)
+
+			located, err := l.Locate(tc.libname)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, located)
+
+			var shadowed bool
+			for _, entry := range hook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					require.Contains(t, entry.Message, "is shadowed by layer "+upper)
+					shadowed = true
+				}
+			}
+			require.Equal(t, tc.expectedShadowed, shadowed)
+		})
+	}
+}
@@ -20,52 +20,68 @@ import (
 	"fmt"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/ldcache"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 )
 
 type ldcacheLocator struct {
-	logger logger.Interface
-	cache  ldcache.LDCache
+	builder
+	cache ldcache.LDCache
 }
 
 var _ Locator = (*ldcacheLocator)(nil)
 
+// defaultLibrarySearchPaths are the paths relative to the root that are
+// searched for libraries if no search paths are specified.
+var defaultLibrarySearchPaths = []string{
+	"/",
+	"/usr/lib64",
+	"/usr/lib/x86_64-linux-gnu",
+	"/usr/lib/aarch64-linux-gnu",
+	"/usr/lib/x86_64-linux-gnu/nvidia/current",
+	"/usr/lib/aarch64-linux-gnu/nvidia/current",
+	"/lib64",
+	"/lib/x86_64-linux-gnu",
+	"/lib/aarch64-linux-gnu",
+	"/lib/x86_64-linux-gnu/nvidia/current",
+	"/lib/aarch64-linux-gnu/nvidia/current",
+}
+
 // NewLibraryLocator creates a library locator using the specified options.
 func NewLibraryLocator(opts ...Option) Locator {
 	b := newBuilder(opts...)
 
+	if len(b.layers) > 0 {
+		return newLayeredLocator(b)
+	}
+
 	// If search paths are already specified, we return a locator for the specified search paths.
 	if len(b.searchPaths) > 0 {
-		return NewSymlinkLocator(
+		searchPathOpts := []Option{
 			WithLogger(b.logger),
 			WithSearchPaths(b.searchPaths...),
 			WithRoot("/"),
+			WithBraceExpansion(b.braceExpansion),
+		}
+		if !b.sonameFallback {
+			return NewSymlinkLocator(searchPathOpts...)
+		}
+		return First(
+			NewSymlinkLocator(searchPathOpts...),
+			NewSonameLocator(searchPathOpts...),
 		)
 	}
 
-	opts = append(opts,
-		WithSearchPaths([]string{
-			"/",
-			"/usr/lib64",
-			"/usr/lib/x86_64-linux-gnu",
-			"/usr/lib/aarch64-linux-gnu",
-			"/usr/lib/x86_64-linux-gnu/nvidia/current",
-			"/usr/lib/aarch64-linux-gnu/nvidia/current",
-			"/lib64",
-			"/lib/x86_64-linux-gnu",
-			"/lib/aarch64-linux-gnu",
-			"/lib/x86_64-linux-gnu/nvidia/current",
-			"/lib/aarch64-linux-gnu/nvidia/current",
-		}...),
-	)
+	opts = append(opts, WithSearchPaths(defaultLibrarySearchPaths...))
 	// We construct a symlink locator for expected library locations.
 	symlinkLocator := NewSymlinkLocator(opts...)
 
-	l := First(
+	locators := []Locator{
 		symlinkLocator,
 		newLdcacheLocator(opts...),
-	)
-	return l
+	}
+	if b.sonameFallback {
+		locators = append(locators, NewSonameLocator(opts...))
+	}
+	return First(locators...)
 }
 
 func newLdcacheLocator(opts ...Option) Locator {
@@ -79,8 +95,8 @@ func newLdcacheLocator(opts ...Option) Locator {
 	}
 
 	return &ldcacheLocator{
-		logger: b.logger,
-		cache:  cache,
+		builder: *b,
+		cache:   cache,
 	}
 }
 
@@ -88,7 +104,7 @@ func newLdcacheLocator(opts ...Option) Locator {
 // If the input is a library name, the ldcache is searched otherwise the
 // provided path is resolved as a symlink.
 func (l ldcacheLocator) Locate(libname string) ([]string, error) {
-	paths32, paths64 := l.cache.Lookup(libname)
+	paths32, paths64 := l.cache.Lookup(l.expandPattern(libname)...)
 	if len(paths32) > 0 {
 		l.logger.Warningf("Ignoring 32-bit libraries for %v: %v", libname, paths32)
 	}
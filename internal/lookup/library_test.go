@@ -210,3 +210,45 @@ func TestLibraryLocator(t *testing.T) {
 		})
 	}
 }
+
+func TestLibraryLocatorWithOrigin(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testDir := t.TempDir()
+	for _, dir := range []string{"/usr/lib64", "/lib64"} {
+		libDir := filepath.Join(testDir, dir)
+		require.NoError(t, os.MkdirAll(libDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, "libfoo.so.1"), []byte(dir), 0644))
+	}
+
+	lut := NewLibraryLocator(
+		WithLogger(logger),
+		WithRoot(testDir),
+	)
+
+	located, err := LocateWithOrigin(lut, "libfoo.so.1")
+	require.NoError(t, err)
+	require.EqualValues(t,
+		[]Located{
+			{
+				Path:       filepath.Join(testDir, "/usr/lib64/libfoo.so.1"),
+				SearchPath: filepath.Join(testDir, "/usr/lib64"),
+			},
+			{
+				Path:       filepath.Join(testDir, "/lib64/libfoo.so.1"),
+				SearchPath: filepath.Join(testDir, "/lib64"),
+			},
+		},
+		located,
+	)
+
+	paths, err := lut.Locate("libfoo.so.1")
+	require.NoError(t, err)
+	require.EqualValues(t,
+		[]string{
+			filepath.Join(testDir, "/usr/lib64/libfoo.so.1"),
+			filepath.Join(testDir, "/lib64/libfoo.so.1"),
+		},
+		paths,
+	)
+}
@@ -25,5 +25,43 @@ type Locator interface {
 	Locate(string) ([]string, error)
 }
 
+// Located represents a path returned by a locator along with the search path
+// in which it was found.
+type Located struct {
+	// Path is the located (and possibly resolved) path.
+	Path string
+	// SearchPath is the search path (including the root) in which the path
+	// was found. This is empty if the path was specified explicitly.
+	SearchPath string
+}
+
+// OriginLocator defines the interface for locators that can report the search
+// path from which each located path originated.
+type OriginLocator interface {
+	LocateWithOrigin(string) ([]Located, error)
+}
+
+// LocateWithOrigin locates the specified pattern using the specified locator
+// and returns the located paths along with the search paths in which they were
+// found. If the locator does not report origins, the search paths are empty.
+func LocateWithOrigin(l Locator, pattern string) ([]Located, error) {
+	if ol, ok := l.(OriginLocator); ok {
+		return ol.LocateWithOrigin(pattern)
+	}
+	paths, err := l.Locate(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var located []Located
+	for _, path := range paths {
+		located = append(located, Located{Path: path})
+	}
+	return located, nil
+}
+
 // ErrNotFound indicates that a specified pattern or file could not be found.
 var ErrNotFound = errors.New("not found")
+
+// ErrTooManySymlinks indicates that the maximum number of symlinks was exceeded
+// while resolving a path.
+var ErrTooManySymlinks = errors.New("too many levels of symbolic links")
@@ -51,3 +51,25 @@ func (f first) Locate(pattern string) ([]string, error) {
 
 	return nil, errors.Join(allErrors...)
 }
+
+// LocateWithOrigin returns the results for the first locator that returns a
+// non-empty non-error result along with the search paths in which these were
+// found.
+func (f first) LocateWithOrigin(pattern string) ([]Located, error) {
+	var allErrors []error
+	for _, l := range f {
+		if l == nil {
+			continue
+		}
+		candidates, err := LocateWithOrigin(l, pattern)
+		if err != nil {
+			allErrors = append(allErrors, err)
+			continue
+		}
+		if len(candidates) > 0 {
+			return candidates, nil
+		}
+	}
+
+	return nil, errors.Join(allErrors...)
+}
@@ -0,0 +1,80 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package root
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// kernelModuleVersionPath is the path (relative to the driver root) of the
+// file containing the version of the loaded NVIDIA kernel module.
+const kernelModuleVersionPath = "/proc/driver/nvidia/version"
+
+// ErrVersionMismatch indicates that the versions of the userspace driver
+// components and the loaded kernel module do not match.
+var ErrVersionMismatch = errors.New("driver version mismatch")
+
+var driverVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?$`)
+
+// KernelModuleVersion returns the version of the loaded NVIDIA kernel module as
+// reported in /proc/driver/nvidia/version relative to the driver root.
+func (r *Driver) KernelModuleVersion() (string, error) {
+	path := filepath.Join(r.Root, kernelModuleVersionPath)
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open kernel module version file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "NVRM version:") {
+			continue
+		}
+		// The line has the form:
+		//   NVRM version: NVIDIA UNIX x86_64 Kernel Module  550.54.15  Tue Mar  5 22:23:56 UTC 2024
+		// or, for the open kernel modules:
+		//   NVRM version: NVIDIA UNIX Open Kernel Module for x86_64  550.54.15  Release Build  (...)
+		for _, field := range strings.Fields(line) {
+			if driverVersionPattern.MatchString(field) {
+				return field, nil
+			}
+		}
+		return "", fmt.Errorf("failed to extract version from %q", line)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read %v: %w", path, err)
+	}
+	return "", fmt.Errorf("no NVRM version found in %v", path)
+}
+
+// CompareVersions checks whether the specified userspace driver version
+// matches the specified kernel module version. An error wrapping
+// ErrVersionMismatch is returned if the versions differ.
+func CompareVersions(userspaceVersion string, kernelModuleVersion string) error {
+	if strings.TrimSpace(userspaceVersion) != strings.TrimSpace(kernelModuleVersion) {
+		return fmt.Errorf("%w: userspace driver version %v does not match kernel module version %v", ErrVersionMismatch, userspaceVersion, kernelModuleVersion)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKernelModuleVersion(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description             string
+		libcudaVersion          string
+		procContents            string
+		expectedVersion         string
+		expectedError           bool
+		expectedVersionMismatch bool
+	}{
+		{
+			description:    "proprietary kernel module matches",
+			libcudaVersion: "550.54.15",
+			procContents: `NVRM version: NVIDIA UNIX x86_64 Kernel Module  550.54.15  Tue Mar  5 22:23:56 UTC 2024
+GCC version:  gcc version 12.3.0 (Ubuntu 12.3.0-1ubuntu1~22.04)
+`,
+			expectedVersion: "550.54.15",
+		},
+		{
+			description:    "open kernel module matches",
+			libcudaVersion: "550.54.15",
+			procContents: `NVRM version: NVIDIA UNIX Open Kernel Module for x86_64  550.54.15  Release Build  (dvs-builder@U16-I3-B03-4-3)  Tue Mar  5 22:24:52 UTC 2024
+GCC version:  gcc version 12.3.0 (Ubuntu 12.3.0-1ubuntu1~22.04)
+`,
+			expectedVersion: "550.54.15",
+		},
+		{
+			description:    "kernel module mismatch",
+			libcudaVersion: "550.54.15",
+			procContents: `NVRM version: NVIDIA UNIX x86_64 Kernel Module  535.161.08  Sat Feb 17 22:55:48 UTC 2024
+`,
+			expectedVersion:         "535.161.08",
+			expectedVersionMismatch: true,
+		},
+		{
+			description:    "missing NVRM line is an error",
+			libcudaVersion: "550.54.15",
+			procContents: `GCC version:  gcc version 12.3.0 (Ubuntu 12.3.0-1ubuntu1~22.04)
+`,
+			expectedError: true,
+		},
+		{
+			description:    "missing version file is an error",
+			libcudaVersion: "550.54.15",
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			libcudaPath := filepath.Join(driverRoot, "usr/lib64/libcuda.so."+tc.libcudaVersion)
+			require.NoError(t, os.MkdirAll(filepath.Dir(libcudaPath), 0755))
+			require.NoError(t, os.WriteFile(libcudaPath, nil, 0644))
+			if tc.procContents != "" {
+				procPath := filepath.Join(driverRoot, kernelModuleVersionPath)
+				require.NoError(t, os.MkdirAll(filepath.Dir(procPath), 0755))
+				require.NoError(t, os.WriteFile(procPath, []byte(tc.procContents), 0644))
+			}
+
+			d := New(
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+			)
+
+			kernelModuleVersion, err := d.KernelModuleVersion()
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, kernelModuleVersion)
+
+			userspaceVersion, err := d.Version()
+			require.NoError(t, err)
+
+			err = CompareVersions(userspaceVersion, kernelModuleVersion)
+			if tc.expectedVersionMismatch {
+				require.ErrorIs(t, err, ErrVersionMismatch)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package root
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const ldsoconfDir = "/etc/ld.so.conf.d"
+
+// LdsoconfDirectories returns the library directories declared in the
+// /etc/ld.so.conf.d/*.conf files of the driver root. Include directives are
+// followed and comments are ignored. The returned directories are relative to
+// the driver root and only directories that exist in the driver root are
+// included.
+func (r *Driver) LdsoconfDirectories() ([]string, error) {
+	root := r.Root
+	if root == "" {
+		root = "/"
+	}
+	p := &ldsoconfParser{
+		root:    root,
+		visited: make(map[string]bool),
+		seen:    make(map[string]bool),
+	}
+	if err := p.include(filepath.Join(ldsoconfDir, "*.conf"), "/"); err != nil {
+		return nil, err
+	}
+
+	var directories []string
+	for _, dir := range p.directories {
+		info, err := os.Stat(filepath.Join(root, dir))
+		if err != nil || !info.IsDir() {
+			r.logger.Debugf("Ignoring ld.so.conf.d directory %v: not a directory in %v", dir, root)
+			continue
+		}
+		directories = append(directories, dir)
+	}
+	return directories, nil
+}
+
+// ldsoconfParser parses ld.so.conf-style files relative to a root.
+type ldsoconfParser struct {
+	root        string
+	visited     map[string]bool
+	seen        map[string]bool
+	directories []string
+}
+
+// include parses the files matching the specified pattern. Relative patterns
+// are interpreted relative to the specified directory.
+func (p *ldsoconfParser) include(pattern string, relativeTo string) error {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(relativeTo, pattern)
+	}
+	matches, err := filepath.Glob(filepath.Join(p.root, pattern))
+	if err != nil {
+		return fmt.Errorf("invalid include pattern %v: %w", pattern, err)
+	}
+	for _, match := range matches {
+		if err := p.parseFile(match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseFile parses the specified file, recording the directories it declares
+// and following its include directives. Files that have already been parsed
+// are skipped to prevent include loops.
+func (p *ldsoconfParser) parseFile(filename string) error {
+	if p.visited[filename] {
+		return nil
+	}
+	p.visited[filename] = true
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open %v: %w", filename, err)
+	}
+	defer f.Close()
+
+	relativePath, err := filepath.Rel(p.root, filename)
+	if err != nil {
+		return fmt.Errorf("failed to get path of %v relative to %v: %w", filename, p.root, err)
+	}
+	relativeTo := filepath.Dir(filepath.Join("/", relativePath))
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if fields := strings.Fields(line); fields[0] == "include" {
+			for _, pattern := range fields[1:] {
+				if err := p.include(pattern, relativeTo); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		for _, dir := range strings.FieldsFunc(line, isLdsoconfSeparator) {
+			dir = filepath.Clean(dir)
+			if !filepath.IsAbs(dir) || p.seen[dir] {
+				continue
+			}
+			p.seen[dir] = true
+			p.directories = append(p.directories, dir)
+		}
+	}
+	return scanner.Err()
+}
+
+// isLdsoconfSeparator checks whether the specified rune separates directories
+// in an ld.so.conf file.
+func isLdsoconfSeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', ':', ',':
+		return true
+	}
+	return false
+}
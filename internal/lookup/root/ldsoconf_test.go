@@ -0,0 +1,106 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLdsoconfDirectories(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description string
+		files       map[string]string
+		dirs        []string
+		expected    []string
+	}{
+		{
+			description: "no ld.so.conf.d returns no directories",
+		},
+		{
+			description: "comments and missing directories are ignored",
+			files: map[string]string{
+				"/etc/ld.so.conf.d/nvidia.conf": "# A comment\n/usr/lib/nvidia # trailing comment\n/usr/lib/missing\n",
+			},
+			dirs:     []string{"/usr/lib/nvidia"},
+			expected: []string{"/usr/lib/nvidia"},
+		},
+		{
+			description: "multiple directories on a line are split",
+			files: map[string]string{
+				"/etc/ld.so.conf.d/nvidia.conf": "/usr/lib/a:/usr/lib/b, /usr/lib/c\n",
+			},
+			dirs:     []string{"/usr/lib/a", "/usr/lib/b", "/usr/lib/c"},
+			expected: []string{"/usr/lib/a", "/usr/lib/b", "/usr/lib/c"},
+		},
+		{
+			description: "non-conf files are ignored",
+			files: map[string]string{
+				"/etc/ld.so.conf.d/nvidia.conf.bak": "/usr/lib/nvidia\n",
+			},
+			dirs: []string{"/usr/lib/nvidia"},
+		},
+		{
+			description: "includes are followed",
+			files: map[string]string{
+				"/etc/ld.so.conf.d/a.conf":        "include nvidia/*.conf\ninclude /etc/extra.conf\n",
+				"/etc/ld.so.conf.d/nvidia/n.conf": "/usr/lib/relative\n",
+				"/etc/extra.conf":                 "/usr/lib/absolute\n",
+			},
+			dirs:     []string{"/usr/lib/relative", "/usr/lib/absolute"},
+			expected: []string{"/usr/lib/relative", "/usr/lib/absolute"},
+		},
+		{
+			description: "include loops are handled",
+			files: map[string]string{
+				"/etc/ld.so.conf.d/a.conf": "include /etc/ld.so.conf.d/b.conf\n/usr/lib/a\n",
+				"/etc/ld.so.conf.d/b.conf": "include /etc/ld.so.conf.d/a.conf\n/usr/lib/b\n/usr/lib/a\n",
+			},
+			dirs:     []string{"/usr/lib/a", "/usr/lib/b"},
+			expected: []string{"/usr/lib/b", "/usr/lib/a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			for filename, contents := range tc.files {
+				path := filepath.Join(driverRoot, filename)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+			}
+			for _, dir := range tc.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, dir), 0755))
+			}
+
+			d := New(
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+			)
+
+			directories, err := d.LdsoconfDirectories()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, directories)
+		})
+	}
+}
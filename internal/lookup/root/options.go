@@ -18,6 +18,8 @@ package root
 
 import "github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 
+const defaultVersionLibraryPattern = "libcuda.so.*.*"
+
 type Option func(*Driver)
 
 func WithLogger(logger logger.Interface) Option {
@@ -43,3 +45,32 @@ func WithConfigSearchPaths(paths ...string) Option {
 		d.configSearchPaths = paths
 	}
 }
+
+// WithVersionLibraryPattern sets the pattern used to locate the library from
+// which the driver version is extracted. This allows, for example, the
+// libcuda.so from a CUDA forward compatibility package to be used. The default
+// is libcuda.so.*.*.
+func WithVersionLibraryPattern(pattern string) Option {
+	return func(d *Driver) {
+		d.versionLibraryPattern = pattern
+	}
+}
+
+// WithStrictVersionDetection sets whether determining the driver version
+// fails if more than one version library is found. By default, the first
+// library found is used and a warning is logged.
+func WithStrictVersionDetection(strict bool) Option {
+	return func(d *Driver) {
+		d.strictVersionDetection = strict
+	}
+}
+
+// WithSonameFallback sets whether locating driver libraries falls back to
+// matching libraries by the SONAME in their ELF headers if no library with a
+// matching filename is found. This allows libraries that were renamed, for
+// example by a distribution, to be located.
+func WithSonameFallback(fallback bool) Option {
+	return func(d *Driver) {
+		d.sonameFallback = fallback
+	}
+}
@@ -17,6 +17,7 @@
 package root
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -34,6 +35,15 @@ type Driver struct {
 	librarySearchPaths []string
 	// configSearchPaths specified explicit search paths for discovering driver config files.
 	configSearchPaths []string
+	// versionLibraryPattern specifies the pattern used to locate the library
+	// from which the driver version is determined.
+	versionLibraryPattern string
+	// strictVersionDetection specifies whether finding multiple version
+	// libraries is treated as an error.
+	strictVersionDetection bool
+	// sonameFallback specifies whether driver libraries are matched by their
+	// ELF SONAME if no library with a matching filename is found.
+	sonameFallback bool
 }
 
 // New creates a new Driver root using the specified options.
@@ -45,6 +55,9 @@ func New(opts ...Option) *Driver {
 	if d.logger == nil {
 		d.logger = logger.New()
 	}
+	if d.versionLibraryPattern == "" {
+		d.versionLibraryPattern = defaultVersionLibraryPattern
+	}
 	return d
 }
 
@@ -76,9 +89,58 @@ func (r *Driver) Libraries() lookup.Locator {
 		lookup.WithLogger(r.logger),
 		lookup.WithRoot(r.Root),
 		lookup.WithSearchPaths(normalizeSearchPaths(r.librarySearchPaths...)...),
+		lookup.WithSonameFallback(r.sonameFallback),
 	)
 }
 
+// VersionLibraryPath returns the path to the library used to determine the
+// driver version. By default this is the libcuda.so.*.* library. If more than
+// one library is found, the first is returned unless strict version detection
+// is enabled, in which case an error listing the candidates is returned.
+func (r *Driver) VersionLibraryPath() (string, error) {
+	paths, err := r.Libraries().Locate(r.versionLibraryPattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %v: %w", r.versionLibraryPattern, err)
+	}
+	if len(paths) > 1 {
+		if r.strictVersionDetection {
+			return "", fmt.Errorf("found multiple candidates for %v: %v", r.versionLibraryPattern, paths)
+		}
+		r.logger.Warningf("Found multiple candidates for %v: %v; using %v", r.versionLibraryPattern, paths, paths[0])
+	}
+	return paths[0], nil
+}
+
+// VersionLibraryPathForVersion returns the path to the library used to
+// determine the driver version that has the specified version. The library is
+// located using the configured version library pattern.
+func (r *Driver) VersionLibraryPathForVersion(version string) (string, error) {
+	paths, err := r.Libraries().Locate(r.versionLibraryPattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to locate %v: %w", r.versionLibraryPattern, err)
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(filepath.Base(path), ".so."+version) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no %v library found for version %v in %v", r.versionLibraryPattern, version, paths)
+}
+
+// Version returns the driver version as extracted from the suffix of the
+// version library. For libcuda.so.RM_VERSION this is RM_VERSION.
+func (r *Driver) Version() (string, error) {
+	libraryPath, err := r.VersionLibraryPath()
+	if err != nil {
+		return "", err
+	}
+	_, version, _ := strings.Cut(filepath.Base(libraryPath), ".so.")
+	if version == "" {
+		return "", fmt.Errorf("failed to extract version from %v", libraryPath)
+	}
+	return version, nil
+}
+
 // Configs returns a locator for driver configs.
 // If configSearchPaths is specified, these paths are used as absolute paths,
 // otherwise, /etc and /usr/share are searched.
@@ -94,15 +156,23 @@ func (r *Driver) configSearchOptions() []lookup.Option {
 			lookup.WithSearchPaths(normalizeSearchPaths(r.configSearchPaths...)...),
 		}
 	}
-	searchPaths := []string{"/etc"}
-	searchPaths = append(searchPaths, xdgDataDirs()...)
 	return []lookup.Option{
 		lookup.WithLogger(r.logger),
 		lookup.WithRoot(r.Root),
-		lookup.WithSearchPaths(searchPaths...),
+		lookup.WithSearchPaths(defaultConfigSearchPaths()...),
 	}
 }
 
+// defaultConfigSearchPaths returns the paths that are searched for config
+// files if no explicit search paths are specified. These are /etc followed by
+// the XDG config and data directories.
+func defaultConfigSearchPaths() []string {
+	searchPaths := []string{"/etc"}
+	searchPaths = append(searchPaths, xdgConfigDirs()...)
+	searchPaths = append(searchPaths, xdgDataDirs()...)
+	return searchPaths
+}
+
 // normalizeSearchPaths takes a list of paths and normalized these.
 // Each of the elements in the list is expanded if it is a path list and the
 // resultant list is returned.
@@ -116,6 +186,16 @@ func normalizeSearchPaths(paths ...string) []string {
 	return normalized
 }
 
+// xdgConfigDirs finds the paths as specified in the environment variable XDG_CONFIG_DIRS.
+// See https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html.
+func xdgConfigDirs() []string {
+	if dirs, exists := os.LookupEnv("XDG_CONFIG_DIRS"); exists && dirs != "" {
+		return normalizeSearchPaths(dirs)
+	}
+
+	return []string{"/etc/xdg"}
+}
+
 // xdgDataDirs finds the paths as specified in the environment variable XDG_DATA_DIRS.
 // See https://specifications.freedesktop.org/basedir-spec/basedir-spec-latest.html.
 func xdgDataDirs() []string {
@@ -0,0 +1,244 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultConfigSearchPaths(t *testing.T) {
+	testCases := []struct {
+		description   string
+		xdgConfigDirs string
+		xdgDataDirs   string
+		expected      []string
+	}{
+		{
+			description: "defaults are used if unset",
+			expected:    []string{"/etc", "/etc/xdg", "/usr/local/share", "/usr/share"},
+		},
+		{
+			description:   "environment variables are used",
+			xdgConfigDirs: "/config/a:/config/b",
+			xdgDataDirs:   "/data/a:/data/b",
+			expected:      []string{"/etc", "/config/a", "/config/b", "/data/a", "/data/b"},
+		},
+		{
+			description:   "config dirs are used with default data dirs",
+			xdgConfigDirs: "/config/a",
+			expected:      []string{"/etc", "/config/a", "/usr/local/share", "/usr/share"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_DIRS", tc.xdgConfigDirs)
+			t.Setenv("XDG_DATA_DIRS", tc.xdgDataDirs)
+
+			require.EqualValues(t, tc.expected, defaultConfigSearchPaths())
+		})
+	}
+}
+
+func TestDriverVersion(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	for _, library := range []string{
+		"usr/lib64/libcuda.so.535.161.08",
+		"usr/local/cuda/compat/libcuda.so.550.54.15",
+	} {
+		path := filepath.Join(driverRoot, library)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	testCases := []struct {
+		description     string
+		options         []Option
+		expectedPath    string
+		expectedVersion string
+	}{
+		{
+			description:     "default pattern uses libcuda.so",
+			expectedPath:    filepath.Join(driverRoot, "usr/lib64/libcuda.so.535.161.08"),
+			expectedVersion: "535.161.08",
+		},
+		{
+			description: "pattern selects compat library",
+			options: []Option{
+				WithVersionLibraryPattern("usr/local/cuda/compat/libcuda.so.*.*"),
+			},
+			expectedPath:    filepath.Join(driverRoot, "usr/local/cuda/compat/libcuda.so.550.54.15"),
+			expectedVersion: "550.54.15",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := New(
+				append(tc.options,
+					WithLogger(logger),
+					WithDriverRoot(driverRoot),
+				)...,
+			)
+
+			path, err := d.VersionLibraryPath()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPath, path)
+
+			version, err := d.Version()
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+
+			path, err = d.VersionLibraryPathForVersion(version)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPath, path)
+
+			_, err = d.VersionLibraryPathForVersion("999.88.77")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDriverVersionStrictVersionDetection(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		libraries       []string
+		strict          bool
+		expectedVersion string
+		expectedError   bool
+	}{
+		{
+			description:     "single library",
+			libraries:       []string{"usr/lib64/libcuda.so.550.54.15"},
+			strict:          true,
+			expectedVersion: "550.54.15",
+		},
+		{
+			description: "multiple libraries are allowed by default",
+			libraries: []string{
+				"usr/lib64/libcuda.so.550.54.15",
+				"lib64/libcuda.so.535.161.08",
+			},
+			expectedVersion: "550.54.15",
+		},
+		{
+			description: "multiple libraries are an error in strict mode",
+			libraries: []string{
+				"usr/lib64/libcuda.so.550.54.15",
+				"lib64/libcuda.so.535.161.08",
+			},
+			strict:        true,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			for _, library := range tc.libraries {
+				path := filepath.Join(driverRoot, library)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, os.WriteFile(path, nil, 0644))
+			}
+
+			d := New(
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+				WithStrictVersionDetection(tc.strict),
+			)
+
+			version, err := d.Version()
+			if tc.expectedError {
+				require.ErrorContains(t, err, "libcuda.so.550.54.15")
+				require.ErrorContains(t, err, "libcuda.so.535.161.08")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestLibrariesSonameFallback(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	// A system library is used since the fallback requires an ELF file with a
+	// SONAME.
+	candidates, _ := filepath.Glob("/usr/lib*/*/libc.so.6")
+	candidates = append(candidates, "/usr/lib64/libc.so.6")
+	var source string
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			source = candidate
+			break
+		}
+	}
+	if source == "" {
+		t.Skip("no system libc.so.6 found")
+	}
+	contents, err := os.ReadFile(source)
+	require.NoError(t, err)
+
+	driverRoot := t.TempDir()
+	renamed := filepath.Join(driverRoot, "usr/lib64/libc-renamed.so")
+	require.NoError(t, os.MkdirAll(filepath.Dir(renamed), 0755))
+	require.NoError(t, os.WriteFile(renamed, contents, 0644))
+
+	testCases := []struct {
+		description    string
+		sonameFallback bool
+		expected       []string
+		expectedError  bool
+	}{
+		{
+			description:   "renamed library is not found by default",
+			expectedError: true,
+		},
+		{
+			description:    "renamed library is found by SONAME",
+			sonameFallback: true,
+			expected:       []string{renamed},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := New(
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+				WithSonameFallback(tc.sonameFallback),
+			)
+
+			located, err := d.Libraries().Locate("libc.so.6")
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, located)
+		})
+	}
+}
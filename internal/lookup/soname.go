@@ -0,0 +1,112 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// soname can be used to locate libraries by the SONAME recorded in the
+// dynamic section of their ELF headers instead of by their filenames.
+type soname struct {
+	file
+	getSoname func(string) (string, error)
+}
+
+var _ Locator = (*soname)(nil)
+
+// NewSonameLocator creates a locator that finds shared libraries in the
+// configured search paths whose SONAME matches a specified pattern. This is
+// useful when the on-disk filename of a library differs from its SONAME.
+func NewSonameLocator(opts ...Option) Locator {
+	return newSonameLocator(opts...)
+}
+
+func newSonameLocator(opts ...Option) *soname {
+	f := newFileLocator(opts...)
+	return &soname{
+		file:      *f,
+		getSoname: getElfSoname,
+	}
+}
+
+// Locate finds the libraries with a SONAME matching the specified pattern.
+// Symlinks are resolved and the unique targets are returned.
+func (p soname) Locate(pattern string) ([]string, error) {
+	f := p.file
+	f.filter = func(candidate string) error {
+		if err := p.file.filter(candidate); err != nil {
+			return err
+		}
+		name, err := p.getSoname(candidate)
+		if err != nil {
+			return fmt.Errorf("failed to get SONAME: %v", err)
+		}
+		for _, pattern := range p.expandPattern(pattern) {
+			if match, _ := filepath.Match(pattern, name); match {
+				return nil
+			}
+		}
+		return fmt.Errorf("SONAME %v does not match %v", name, pattern)
+	}
+
+	candidates, err := f.Locate("*.so*")
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("SONAME %v %w", pattern, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var targets []string
+	for _, candidate := range candidates {
+		target, err := p.resolveLink(candidate)
+		if err != nil {
+			p.logger.Debugf("Failed to resolve %v: %v", candidate, err)
+			continue
+		}
+		if seen[target] {
+			continue
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// getElfSoname returns the SONAME from the dynamic section of the specified
+// ELF file.
+func getElfSoname(path string) (string, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	sonames, err := f.DynString(elf.DT_SONAME)
+	if err != nil {
+		return "", err
+	}
+	if len(sonames) == 0 {
+		return "", fmt.Errorf("no SONAME in %v", path)
+	}
+	return sonames[0], nil
+}
@@ -0,0 +1,113 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSonameLocator(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testDir := t.TempDir()
+	libDir := filepath.Join(testDir, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+
+	// The filenames intentionally differ from the SONAMEs.
+	sonames := map[string]string{
+		"libfoo-distro.so.1.2.3": "libfoo.so.1",
+		"libbar.so.4.5.6":        "libbar.so.4",
+		"libnotelf.so":           "",
+	}
+	for name := range sonames {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, name), nil, 0644))
+	}
+	require.NoError(t, os.Symlink("libfoo-distro.so.1.2.3", filepath.Join(libDir, "libfoo-link.so")))
+
+	testCases := []struct {
+		description   string
+		pattern       string
+		expected      []string
+		expectedError error
+	}{
+		{
+			description: "library is found by SONAME",
+			pattern:     "libfoo.so.1",
+			expected:    []string{filepath.Join(libDir, "libfoo-distro.so.1.2.3")},
+		},
+		{
+			description: "SONAME pattern is matched",
+			pattern:     "libbar.so.*",
+			expected:    []string{filepath.Join(libDir, "libbar.so.4.5.6")},
+		},
+		{
+			description:   "filename is not matched",
+			pattern:       "libfoo-distro.so.1.2.3",
+			expectedError: ErrNotFound,
+		},
+		{
+			description:   "missing SONAME returns error",
+			pattern:       "libmissing.so.1",
+			expectedError: ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := newSonameLocator(
+				WithLogger(logger),
+				WithRoot(testDir),
+				WithSearchPaths("/usr/lib64"),
+			)
+			l.getSoname = func(path string) (string, error) {
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					return "", err
+				}
+				soname := sonames[filepath.Base(target)]
+				if soname == "" {
+					return "", fmt.Errorf("not an ELF file")
+				}
+				return soname, nil
+			}
+
+			located, err := l.Locate(tc.pattern)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.EqualValues(t, tc.expected, located)
+		})
+	}
+}
+
+func TestGetElfSoname(t *testing.T) {
+	notElf := filepath.Join(t.TempDir(), "libnotelf.so")
+	require.NoError(t, os.WriteFile(notElf, []byte("not an ELF file"), 0644))
+
+	_, err := getElfSoname(notElf)
+	require.Error(t, err)
+
+	// The executable of the test is an ELF file without a SONAME.
+	executable, err := os.Executable()
+	require.NoError(t, err)
+	_, err = getElfSoname(executable)
+	require.Error(t, err)
+}
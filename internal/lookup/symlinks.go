@@ -18,6 +18,7 @@ package lookup
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/symlinks"
@@ -63,6 +64,7 @@ func (p symlinkChain) Locate(pattern string) ([]string, error) {
 	}
 
 	found := make(map[string]bool)
+	depths := make(map[string]int)
 	for len(candidates) > 0 {
 		candidate := candidates[0]
 		candidates = candidates[:len(candidates)-1]
@@ -84,9 +86,15 @@ func (p symlinkChain) Locate(pattern string) ([]string, error) {
 		}
 
 		p.logger.Debugf("Resolved link: '%v' => '%v'", candidate, target)
-		if !found[target] {
-			candidates = append(candidates, target)
+		if found[target] || target == candidate {
+			continue
+		}
+		depth := depths[candidate] + 1
+		if depth > p.maxSymlinkDepth {
+			return nil, fmt.Errorf("%w: more than %d links followed from %v", ErrTooManySymlinks, p.maxSymlinkDepth, candidate)
 		}
+		depths[target] = depth
+		candidates = append(candidates, target)
 	}
 
 	var filenames []string
@@ -99,15 +107,31 @@ func (p symlinkChain) Locate(pattern string) ([]string, error) {
 // Locate finds the specified pattern at the specified root.
 // If the file is a symlink, the link is resolved and the target returned.
 func (p symlink) Locate(pattern string) ([]string, error) {
-	candidates, err := p.file.Locate(pattern)
+	located, err := p.LocateWithOrigin(pattern)
 	if err != nil {
 		return nil, err
 	}
 
 	var targets []string
+	for _, l := range located {
+		targets = append(targets, l.Path)
+	}
+	return targets, nil
+}
+
+// LocateWithOrigin finds the specified pattern at the specified root and
+// returns the resolved targets along with the search path in which the
+// (unresolved) candidate was found.
+func (p symlink) LocateWithOrigin(pattern string) ([]Located, error) {
+	candidates, err := p.file.LocateWithOrigin(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Located
 	seen := make(map[string]bool)
 	for _, candidate := range candidates {
-		target, err := filepath.EvalSymlinks(candidate)
+		target, err := p.resolveLink(candidate.Path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve link: %w", err)
 		}
@@ -115,7 +139,35 @@ func (p symlink) Locate(pattern string) ([]string, error) {
 			continue
 		}
 		seen[target] = true
-		targets = append(targets, target)
+		targets = append(targets, Located{Path: target, SearchPath: candidate.SearchPath})
+	}
+	return targets, nil
+}
+
+// resolveLink returns the path to which the specified path resolves. At most
+// maxSymlinkDepth links are followed for the final path component to ensure
+// that symlink loops are detected instead of being followed indefinitely.
+func (p builder) resolveLink(path string) (string, error) {
+	current := path
+	for depth := 0; ; depth++ {
+		info, err := os.Lstat(current)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			// The remaining path components may still contain symlinks.
+			return filepath.EvalSymlinks(current)
+		}
+		if depth >= p.maxSymlinkDepth {
+			return "", fmt.Errorf("%w: more than %d links followed from %v", ErrTooManySymlinks, p.maxSymlinkDepth, path)
+		}
+		target, err := os.Readlink(current)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(current), target)
+		}
+		current = target
 	}
-	return targets, err
 }
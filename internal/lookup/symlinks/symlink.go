@@ -17,8 +17,12 @@
 package symlinks
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // Resolve returns the link target of the specified filename or the filename if it is not a link.
@@ -33,3 +37,38 @@ func Resolve(filename string) (string, error) {
 
 	return os.Readlink(filename)
 }
+
+// ResolveInRoot returns the path in the specified root that the specified
+// path refers to. Symlinks are resolved and an error is returned if the
+// resolved path is outside the root. A path that does not exist is returned
+// without resolving symlinks.
+func ResolveInRoot(root string, path string) (string, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve root %v: %w", root, err)
+	}
+	joined := filepath.Join(root, path)
+	if !isInRoot(root, joined) {
+		return "", fmt.Errorf("path %v is outside the container root", path)
+	}
+
+	resolved, err := filepath.EvalSymlinks(joined)
+	if errors.Is(err, fs.ErrNotExist) {
+		return joined, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %v: %w", joined, err)
+	}
+	if !isInRoot(root, resolved) {
+		return "", fmt.Errorf("path %v resolves to %v which is outside the container root", path, resolved)
+	}
+	return resolved, nil
+}
+
+func isInRoot(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
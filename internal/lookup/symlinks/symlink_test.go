@@ -0,0 +1,82 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package symlinks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInRoot(t *testing.T) {
+	containerRoot := t.TempDir()
+	outside := t.TempDir()
+
+	devDir := filepath.Join(containerRoot, "dev")
+	require.NoError(t, os.MkdirAll(devDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(devDir, "nvidia0"), nil, 0644))
+	require.NoError(t, os.Symlink("nvidia0", filepath.Join(devDir, "relative-link")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "target"), filepath.Join(devDir, "escaping-link")))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "target"), nil, 0644))
+
+	testCases := []struct {
+		description   string
+		path          string
+		expected      string
+		expectedError bool
+	}{
+		{
+			description: "path in root",
+			path:        "/dev/nvidia0",
+			expected:    filepath.Join(devDir, "nvidia0"),
+		},
+		{
+			description: "relative link in root is resolved",
+			path:        "/dev/relative-link",
+			expected:    filepath.Join(devDir, "nvidia0"),
+		},
+		{
+			description: "missing path is returned unresolved",
+			path:        "/dev/nvidia1",
+			expected:    filepath.Join(devDir, "nvidia1"),
+		},
+		{
+			description:   "path escaping the root is rejected",
+			path:          "../../../etc/shadow",
+			expectedError: true,
+		},
+		{
+			description:   "link resolving outside the root is rejected",
+			path:          "/dev/escaping-link",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			resolved, err := ResolveInRoot(containerRoot, tc.path)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, resolved)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lookup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymlinkLocatorMaxSymlinkDepth(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	libDir := filepath.Join(root, "lib")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+
+	// A chain of links: libfoo.so -> libfoo.so.1 -> libfoo.so.1.2 -> libfoo.so.1.2.3
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libfoo.so.1.2.3"), nil, 0644))
+	require.NoError(t, os.Symlink("libfoo.so.1.2.3", filepath.Join(libDir, "libfoo.so.1.2")))
+	require.NoError(t, os.Symlink("libfoo.so.1.2", filepath.Join(libDir, "libfoo.so.1")))
+	require.NoError(t, os.Symlink(filepath.Join(libDir, "libfoo.so.1"), filepath.Join(libDir, "libfoo.so")))
+
+	// A symlink loop: libloop.so -> libloop.so.1 -> libloop.so
+	require.NoError(t, os.Symlink("libloop.so.1", filepath.Join(libDir, "libloop.so")))
+	require.NoError(t, os.Symlink("libloop.so", filepath.Join(libDir, "libloop.so.1")))
+
+	acceptAll := func(string) error { return nil }
+
+	testCases := []struct {
+		description   string
+		pattern       string
+		depth         int
+		expected      []string
+		expectedError error
+	}{
+		{
+			description: "default depth resolves chain",
+			pattern:     "libfoo.so",
+			expected:    []string{filepath.Join(libDir, "libfoo.so.1.2.3")},
+		},
+		{
+			description: "depth matching chain length resolves chain",
+			pattern:     "libfoo.so",
+			depth:       3,
+			expected:    []string{filepath.Join(libDir, "libfoo.so.1.2.3")},
+		},
+		{
+			description:   "depth shorter than chain returns error",
+			pattern:       "libfoo.so",
+			depth:         2,
+			expectedError: ErrTooManySymlinks,
+		},
+		{
+			description:   "symlink loop returns error",
+			pattern:       "libloop.so",
+			expectedError: ErrTooManySymlinks,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := NewSymlinkLocator(
+				WithLogger(logger),
+				WithRoot(root),
+				WithSearchPaths("/lib"),
+				WithFilter(acceptAll),
+				WithMaxSymlinkDepth(tc.depth),
+			)
+			located, err := l.Locate(tc.pattern)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.EqualValues(t, tc.expected, located)
+		})
+	}
+}
+
+func TestSymlinkChainLocatorMaxSymlinkDepth(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	root := t.TempDir()
+	libDir := filepath.Join(root, "lib")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libfoo.so.1.2.3"), nil, 0644))
+	require.NoError(t, os.Symlink("libfoo.so.1.2.3", filepath.Join(libDir, "libfoo.so.1.2")))
+	require.NoError(t, os.Symlink("libfoo.so.1.2", filepath.Join(libDir, "libfoo.so.1")))
+
+	require.NoError(t, os.Symlink("libloop.so.1", filepath.Join(libDir, "libloop.so")))
+	require.NoError(t, os.Symlink("libloop.so", filepath.Join(libDir, "libloop.so.1")))
+
+	acceptAll := func(string) error { return nil }
+
+	l := NewSymlinkChainLocator(
+		WithLogger(logger),
+		WithRoot(root),
+		WithSearchPaths("/lib"),
+		WithFilter(acceptAll),
+		WithMaxSymlinkDepth(1),
+	)
+	_, err := l.Locate("libfoo.so.1")
+	require.ErrorIs(t, err, ErrTooManySymlinks)
+
+	l = NewSymlinkChainLocator(
+		WithLogger(logger),
+		WithRoot(root),
+		WithSearchPaths("/lib"),
+		WithFilter(acceptAll),
+	)
+	located, err := l.Locate("libfoo.so.1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(libDir, "libfoo.so.1"),
+		filepath.Join(libDir, "libfoo.so.1.2"),
+		filepath.Join(libDir, "libfoo.so.1.2.3"),
+	}, located)
+
+	// A loop terminates since each link is only visited once.
+	located, err = l.Locate("libloop.so")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(libDir, "libloop.so"),
+		filepath.Join(libDir, "libloop.so.1"),
+	}, located)
+}
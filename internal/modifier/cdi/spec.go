@@ -22,6 +22,7 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
 )
 
@@ -33,9 +34,12 @@ type fromCDISpec struct {
 var _ oci.SpecModifier = (*fromCDISpec)(nil)
 
 // Modify applies the mofiications defined by the raw CDI spec to the incomming OCI spec.
+// Path list environment variables such as LD_LIBRARY_PATH are appended to the
+// values defined in the incoming OCI spec.
 func (m fromCDISpec) Modify(spec *specs.Spec) error {
 	for _, device := range m.cdiSpec.Devices {
 		device := device
+		device.ContainerEdits.Env = edits.AppendToExistingEnv(device.ContainerEdits.Env, spec)
 		cdiDevice := cdi.Device{
 			Device: &device,
 		}
@@ -44,5 +48,10 @@ func (m fromCDISpec) Modify(spec *specs.Spec) error {
 		}
 	}
 
-	return m.cdiSpec.ApplyEdits(spec)
+	commonEdits := m.cdiSpec.ContainerEdits
+	commonEdits.Env = edits.AppendToExistingEnv(commonEdits.Env, spec)
+	e := cdi.ContainerEdits{
+		ContainerEdits: &commonEdits,
+	}
+	return e.Apply(spec)
 }
@@ -65,6 +65,8 @@ func NewCSVModifier(logger logger.Interface, cfg *config.Config, image image.CUD
 		nvcdi.WithNVIDIACDIHookPath(cfg.NVIDIACTKConfig.Path),
 		nvcdi.WithMode(nvcdi.ModeCSV),
 		nvcdi.WithCSVFiles(csvFiles),
+		nvcdi.WithLDCacheUpdateMode(cfg.NVIDIAContainerRuntimeConfig.Modes.CSV.LDCacheUpdateMode),
+		nvcdi.WithLDCacheUpdateHookLifecycle(cfg.NVIDIAContainerRuntimeConfig.Modes.CSV.LDCacheUpdateHookLifecycle),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct CDI library: %v", err)
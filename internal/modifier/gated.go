@@ -33,6 +33,12 @@ import (
 //	NVIDIA_MOFED=enabled
 //	NVIDIA_NVSWITCH=enabled
 //	NVIDIA_GDRCOPY=enabled
+//	NVIDIA_MPS=enabled
+//	NVIDIA_SELINUX_RELABEL=enabled
+//	NVIDIA_DEV_CHAR_SYMLINKS=enabled
+//
+// If nvidia-container-runtime.device-node-mode is set, the permissions of the
+// injected device nodes are also updated.
 //
 // If not devices are selected, no changes are made.
 func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image image.CUDA) (oci.SpecModifier, error) {
@@ -46,7 +52,12 @@ func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image
 	driverRoot := cfg.NVIDIAContainerCLIConfig.Root
 	devRoot := cfg.NVIDIAContainerCLIConfig.Root
 
-	if cfg.Features.IsEnabled(config.FeatureGDS, image) {
+	env := config.NewGetenvRecorder(image)
+	defer func() {
+		logger.Debugf("Checked feature envvars: %v", env.Consulted())
+	}()
+
+	if cfg.Features.IsEnabled(config.FeatureGDS, env) {
 		d, err := discover.NewGDSDiscoverer(logger, driverRoot, devRoot)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct discoverer for GDS devices: %w", err)
@@ -54,7 +65,7 @@ func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image
 		discoverers = append(discoverers, d)
 	}
 
-	if cfg.Features.IsEnabled(config.FeatureMOFED, image) {
+	if cfg.Features.IsEnabled(config.FeatureMOFED, env) {
 		d, err := discover.NewMOFEDDiscoverer(logger, devRoot)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct discoverer for MOFED devices: %w", err)
@@ -62,7 +73,7 @@ func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image
 		discoverers = append(discoverers, d)
 	}
 
-	if cfg.Features.IsEnabled(config.FeatureNVSWITCH, image) {
+	if cfg.Features.IsEnabled(config.FeatureNVSWITCH, env) {
 		d, err := discover.NewNvSwitchDiscoverer(logger, devRoot)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct discoverer for NVSWITCH devices: %w", err)
@@ -70,7 +81,7 @@ func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image
 		discoverers = append(discoverers, d)
 	}
 
-	if cfg.Features.IsEnabled(config.FeatureGDRCopy, image) {
+	if cfg.Features.IsEnabled(config.FeatureGDRCopy, env) {
 		d, err := discover.NewGDRCopyDiscoverer(logger, devRoot)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct discoverer for GDRCopy devices: %w", err)
@@ -78,5 +89,36 @@ func NewFeatureGatedModifier(logger logger.Interface, cfg *config.Config, image
 		discoverers = append(discoverers, d)
 	}
 
+	if cfg.Features.IsEnabled(config.FeatureMPS, env) {
+		d := discover.NewMPSDiscoverer(
+			logger,
+			driverRoot,
+			cfg.NVIDIAContainerRuntimeConfig.MPS.PipeDirectory,
+			cfg.NVIDIAContainerRuntimeConfig.MPS.LogDirectory,
+		)
+		discoverers = append(discoverers, d)
+	}
+
+	if cfg.Features.IsEnabled(config.FeatureSELinuxRelabel, env) {
+		devices := discover.NewCharDeviceDiscoverer(logger, devRoot, []string{"/dev/nvidia*"})
+		d := discover.NewSELinuxRelabelHook(logger, devices, cfg.NVIDIACTKConfig.Path, "")
+		discoverers = append(discoverers, d)
+	}
+
+	if cfg.Features.IsEnabled(config.FeatureDevCharSymlinks, env) {
+		devices := discover.NewCharDeviceDiscoverer(logger, devRoot, []string{"/dev/nvidia*", "/dev/nvidia-caps/nvidia-cap*"})
+		d := discover.NewDevCharSymlinksHook(logger, devices, cfg.NVIDIACTKConfig.Path)
+		discoverers = append(discoverers, d)
+	}
+
+	if mode := cfg.NVIDIAContainerRuntimeConfig.DeviceNodeMode; mode != "" {
+		devices := discover.NewCharDeviceDiscoverer(logger, devRoot, []string{"/dev/nvidia*"})
+		d, err := discover.NewChmodDevicesHook(logger, devices, cfg.NVIDIACTKConfig.Path, mode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct discoverer for device node permissions: %w", err)
+		}
+		discoverers = append(discoverers, d)
+	}
+
 	return NewModifierFromDiscoverer(logger, discover.Merge(discoverers...))
 }
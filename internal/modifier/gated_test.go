@@ -0,0 +1,121 @@
+/**
+# Copyright (c) 2022, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package modifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+)
+
+func TestFeatureGatedModifierMPS(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description          string
+		env                  map[string]string
+		runtimeConfig        config.RuntimeConfig
+		directories          []string
+		expectedMountsInRoot []string
+	}{
+		{
+			description: "feature disabled",
+			env: map[string]string{
+				"NVIDIA_VISIBLE_DEVICES": "all",
+			},
+			directories: []string{"/tmp/nvidia-mps"},
+		},
+		{
+			description: "feature enabled with missing pipe directory",
+			env: map[string]string{
+				"NVIDIA_VISIBLE_DEVICES": "all",
+				"NVIDIA_MPS":             "enabled",
+			},
+		},
+		{
+			description: "feature enabled mounts log directory",
+			env: map[string]string{
+				"NVIDIA_VISIBLE_DEVICES": "all",
+				"NVIDIA_MPS":             "enabled",
+			},
+			directories:          []string{"/tmp/nvidia-mps", "/var/log/nvidia-mps"},
+			expectedMountsInRoot: []string{"/var/log/nvidia-mps"},
+		},
+		{
+			description: "feature enabled mounts configured pipe directory",
+			env: map[string]string{
+				"NVIDIA_VISIBLE_DEVICES": "all",
+				"NVIDIA_MPS":             "enabled",
+			},
+			runtimeConfig: config.RuntimeConfig{
+				MPS: config.MPSConfig{
+					PipeDirectory: "/run/nvidia/mps",
+				},
+			},
+			directories:          []string{"/tmp/nvidia-mps", "/run/nvidia/mps"},
+			expectedMountsInRoot: []string{"/run/nvidia/mps"},
+		},
+		{
+			description: "image envvars do not select directories",
+			env: map[string]string{
+				"NVIDIA_VISIBLE_DEVICES":  "all",
+				"NVIDIA_MPS":              "enabled",
+				"CUDA_MPS_PIPE_DIRECTORY": "/etc",
+				"CUDA_MPS_LOG_DIRECTORY":  "/root",
+			},
+			directories: []string{"/etc", "/root"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			for _, dir := range tc.directories {
+				require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, dir), 0755))
+			}
+
+			cfg := &config.Config{
+				NVIDIAContainerCLIConfig: config.ContainerCLIConfig{
+					Root: driverRoot,
+				},
+				NVIDIAContainerRuntimeConfig: tc.runtimeConfig,
+			}
+			cudaImage, err := image.New(image.WithEnvMap(tc.env))
+			require.NoError(t, err)
+
+			m, err := NewFeatureGatedModifier(logger, cfg, cudaImage)
+			require.NoError(t, err)
+
+			spec := &specs.Spec{}
+			require.NoError(t, m.Modify(spec))
+
+			var mounts []string
+			for _, mount := range spec.Mounts {
+				require.Equal(t, filepath.Join(driverRoot, mount.Destination), mount.Source)
+				mounts = append(mounts, mount.Destination)
+			}
+			require.EqualValues(t, tc.expectedMountsInRoot, mounts)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package oci
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -26,10 +27,15 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// ErrEmptyContainerState is returned if the input from which the container state
+// is read is empty.
+var ErrEmptyContainerState = errors.New("container state is empty")
+
 // State stores an OCI container state. This includes the spec path and the environment
 type State specs.State
 
-// LoadContainerState loads the container state from the specified filename. If the filename is empty or '-' the state is loaded from STDIN
+// LoadContainerState loads the container state from the specified filename. If the filename is empty or '-' the state is loaded from STDIN.
+// The file may also be a non-seekable input such as a named pipe.
 func LoadContainerState(filename string) (*State, error) {
 	if filename == "" || filename == "-" {
 		return ReadContainerState(os.Stdin)
@@ -44,13 +50,17 @@ func LoadContainerState(filename string) (*State, error) {
 	return ReadContainerState(inputFile)
 }
 
-// ReadContainerState reads the container state from the specified reader
+// ReadContainerState reads the container state from the specified reader.
+// If the input is empty or only contains whitespace, ErrEmptyContainerState is
+// returned.
 func ReadContainerState(reader io.Reader) (*State, error) {
 	var s State
-
-	d := json.NewDecoder(reader)
-	if err := d.Decode(&s); err != nil {
-		return nil, fmt.Errorf("failed to decode container state: %v", err)
+	err := json.NewDecoder(reader).Decode(&s)
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyContainerState
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode container state: %w", err)
 	}
 
 	return &s, nil
@@ -0,0 +1,124 @@
+/**
+# Copyright (c) 2025, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadContainerState(t *testing.T) {
+	testCases := []struct {
+		description    string
+		input          string
+		expectedError  error
+		invalid        bool
+		expectedBundle string
+	}{
+		{
+			description:    "valid state",
+			input:          `{"ociVersion": "1.0.0", "id": "ctr", "bundle": "/bundle"}`,
+			expectedBundle: "/bundle",
+		},
+		{
+			description:   "empty input",
+			input:         "",
+			expectedError: ErrEmptyContainerState,
+		},
+		{
+			description:   "whitespace only input",
+			input:         " \n\t",
+			expectedError: ErrEmptyContainerState,
+		},
+		{
+			description: "truncated input",
+			input:       `{"ociVersion": "1.0.0", "id": `,
+			invalid:     true,
+		},
+		{
+			description: "invalid input",
+			input:       "not json",
+			invalid:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			s, err := ReadContainerState(strings.NewReader(tc.input))
+			if tc.invalid {
+				require.Error(t, err)
+				require.NotErrorIs(t, err, ErrEmptyContainerState)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedError)
+			if tc.expectedError != nil {
+				return
+			}
+			require.Equal(t, tc.expectedBundle, s.Bundle)
+		})
+	}
+}
+
+func TestLoadContainerStateFromPipe(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, syscall.Mkfifo(pipe, 0600))
+
+	errs := make(chan error, 1)
+	go func() {
+		w, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer w.Close()
+		// Write the state in multiple chunks to ensure that partial reads are
+		// handled.
+		for _, chunk := range []string{`{"ociVersion": "1.0.0", `, `"id": "ctr", `, `"bundle": "/bundle"}`} {
+			if _, err := w.WriteString(chunk); err != nil {
+				errs <- err
+				return
+			}
+		}
+		errs <- nil
+	}()
+
+	s, err := LoadContainerState(pipe)
+	require.NoError(t, err)
+	require.NoError(t, <-errs)
+	require.Equal(t, "ctr", s.ID)
+	require.Equal(t, "/bundle", s.Bundle)
+}
+
+func TestLoadContainerStateFromEmptyPipe(t *testing.T) {
+	pipe := filepath.Join(t.TempDir(), "state")
+	require.NoError(t, syscall.Mkfifo(pipe, 0600))
+
+	go func() {
+		w, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+		if err == nil {
+			w.Close()
+		}
+	}()
+
+	_, err := LoadContainerState(pipe)
+	require.ErrorIs(t, err, ErrEmptyContainerState)
+}
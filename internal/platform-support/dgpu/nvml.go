@@ -18,6 +18,7 @@ package dgpu
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
@@ -75,6 +76,7 @@ func (o *options) newNvmlDGPUDiscoverer(d requiredInfo) (discover.Discover, erro
 type requiredMigInfo interface {
 	getPlacementInfo() (int, int, int, error)
 	getDevNodePath() (string, error)
+	getUUID() (string, error)
 }
 
 func (o *options) newNvmlMigDiscoverer(d requiredMigInfo) (discover.Discover, error) {
@@ -115,7 +117,38 @@ func (o *options) newNvmlMigDiscoverer(d requiredMigInfo) (discover.Discover, er
 		},
 	)
 
-	return deviceNodes, nil
+	discoverers := []discover.Discover{deviceNodes}
+	if o.migCaps {
+		discoverers = append(discoverers, discover.NewMIGCapsDiscoverer(o.logger, "/", giCap, ciCap))
+	}
+
+	if o.validateMigDevice {
+		uuid, err := d.getUUID()
+		if err != nil {
+			return nil, fmt.Errorf("error getting MIG device UUID: %w", err)
+		}
+		discoverers = append(discoverers, o.newMigValidationHook(uuid, gpu, gi, ci))
+	}
+
+	if len(discoverers) == 1 {
+		return deviceNodes, nil
+	}
+	return discover.Merge(discoverers...), nil
+}
+
+// newMigValidationHook creates a hook that checks whether the MIG device with
+// the specified placement exists when a container is created. This allows
+// container creation to fail early if the MIG device was destroyed after the
+// CDI specification was generated.
+func (o *options) newMigValidationHook(uuid string, gpu int, gi int, ci int) discover.Hook {
+	return discover.CreateNvidiaCDIHook(
+		o.nvidiaCDIHookPath,
+		"validate-mig-device",
+		"--uuid", uuid,
+		"--gpu", strconv.Itoa(gpu),
+		"--gpu-instance", strconv.Itoa(gi),
+		"--compute-instance", strconv.Itoa(ci),
+	)
 }
 
 type toRequiredInfo struct {
@@ -166,3 +199,11 @@ func (d *toRequiredMigInfo) getPlacementInfo() (int, int, int, error) {
 func (d *toRequiredMigInfo) getDevNodePath() (string, error) {
 	return d.parent.getDevNodePath()
 }
+
+func (d *toRequiredMigInfo) getUUID() (string, error) {
+	uuid, ret := d.GetUUID()
+	if ret != nvml.SUCCESS {
+		return "", ret
+	}
+	return uuid, nil
+}
@@ -85,3 +85,24 @@ func TestNewNvmlDGPUDiscoverer(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMigValidationHook(t *testing.T) {
+	o := &options{
+		nvidiaCDIHookPath: "/usr/bin/nvidia-cdi-hook",
+	}
+
+	hook := o.newMigValidationHook("MIG-1234", 1, 2, 3)
+
+	expected := discover.Hook{
+		Lifecycle: "createContainer",
+		Path:      "/usr/bin/nvidia-cdi-hook",
+		Args: []string{
+			"nvidia-cdi-hook", "validate-mig-device",
+			"--uuid", "MIG-1234",
+			"--gpu", "1",
+			"--gpu-instance", "2",
+			"--compute-instance", "3",
+		},
+	}
+	require.Equal(t, expected, hook)
+}
@@ -24,6 +24,9 @@ type options struct {
 	logger            logger.Interface
 	devRoot           string
 	nvidiaCDIHookPath string
+
+	validateMigDevice bool
+	migCaps           bool
 }
 
 type Option func(*options)
@@ -48,3 +51,19 @@ func WithNVIDIACDIHookPath(path string) Option {
 		l.nvidiaCDIHookPath = path
 	}
 }
+
+// WithValidateMigDevice sets whether a hook is added to the MIG device edits
+// that validates that the MIG device still exists when a container is created.
+func WithValidateMigDevice(validate bool) Option {
+	return func(l *options) {
+		l.validateMigDevice = validate
+	}
+}
+
+// WithMIGCaps sets whether the procfs capability files for the GPU and compute
+// instances of a MIG device are included as mounts in the MIG device edits.
+func WithMIGCaps(migCaps bool) Option {
+	return func(l *options) {
+		l.migCaps = migCaps
+	}
+}
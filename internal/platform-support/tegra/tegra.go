@@ -35,6 +35,8 @@ type tegraOptions struct {
 	librarySearchPaths []string
 	ignorePatterns     ignoreMountSpecPatterns
 
+	ldcacheUpdateHookOptions []discover.LDCacheUpdateHookOption
+
 	// The following can be overridden for testing
 	symlinkLocator      lookup.Locator
 	symlinkChainLocator lookup.Locator
@@ -80,7 +82,7 @@ func New(opts ...Option) (discover.Discover, error) {
 		return nil, fmt.Errorf("failed to create CSV discoverer: %v", err)
 	}
 
-	ldcacheUpdateHook, err := discover.NewLDCacheUpdateHook(o.logger, csvDiscoverer, o.nvidiaCDIHookPath, o.ldconfigPath)
+	ldcacheUpdateHook, err := discover.NewLDCacheUpdateHook(o.logger, csvDiscoverer, o.nvidiaCDIHookPath, o.ldconfigPath, o.ldcacheUpdateHookOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ldcach update hook discoverer: %v", err)
 	}
@@ -147,6 +149,14 @@ func WithLdconfigPath(ldconfigPath string) Option {
 	}
 }
 
+// WithLDCacheUpdateHookOptions sets the options for the ldcache update hook
+// for the discovered libraries.
+func WithLDCacheUpdateHookOptions(opts ...discover.LDCacheUpdateHookOption) Option {
+	return func(o *tegraOptions) {
+		o.ldcacheUpdateHookOptions = opts
+	}
+}
+
 // WithLibrarySearchPaths sets the library search paths for the discoverer.
 func WithLibrarySearchPaths(librarySearchPaths ...string) Option {
 	return func(o *tegraOptions) {
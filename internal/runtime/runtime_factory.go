@@ -21,6 +21,8 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config/image"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
@@ -79,8 +81,8 @@ func newSpecModifier(logger logger.Interface, cfg *config.Config, ociSpec oci.Sp
 	if err != nil {
 		return nil, err
 	}
-	// For CDI mode we make no additional modifications.
-	if mode == "cdi" {
+	// For CDI and none modes we make no additional modifications.
+	if mode == "cdi" || mode == "none" {
 		return modeModifier, nil
 	}
 
@@ -110,6 +112,9 @@ func newModeModifier(logger logger.Interface, mode string, cfg *config.Config, o
 		return modifier.NewCSVModifier(logger, cfg, image)
 	case "cdi":
 		return modifier.NewCDIModifier(logger, cfg, ociSpec)
+	case "none":
+		logger.Infof("Skipping GPU injection in 'none' mode")
+		return edits.NewSpecEdits(logger, discover.None{})
 	}
 
 	return nil, fmt.Errorf("invalid runtime mode: %v", cfg.NVIDIAContainerRuntimeConfig.Mode)
@@ -30,6 +30,7 @@ import (
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/oci"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/test"
 )
 
@@ -134,6 +135,15 @@ func TestFactoryMethod(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "none mode is supported",
+			cfg: &config.Config{
+				NVIDIAContainerRuntimeConfig: config.RuntimeConfig{
+					Runtimes: []string{"runc"},
+					Mode:     "none",
+				},
+			},
+		},
 		{
 			description: "empty mode raises error",
 			cfg: &config.Config{
@@ -165,3 +175,32 @@ func TestFactoryMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSpecModifierNoneMode(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+	driver := root.New(
+		root.WithDriverRoot("/nvidia/driver/root"),
+	)
+
+	cfg, err := config.GetDefault()
+	require.NoError(t, err)
+	cfg.NVIDIAContainerRuntimeConfig.Mode = "none"
+
+	spec := &specs.Spec{
+		Process: &specs.Process{
+			Env: []string{
+				"NVIDIA_VISIBLE_DEVICES=all",
+				"NVIDIA_DRIVER_CAPABILITIES=all",
+			},
+		},
+	}
+
+	specModifier, err := newSpecModifier(logger, cfg, oci.NewMemorySpec(spec), driver)
+	require.NoError(t, err)
+
+	require.NoError(t, specModifier.Modify(spec))
+	require.Nil(t, spec.Hooks)
+	require.Empty(t, spec.Mounts)
+	require.Nil(t, spec.Linux)
+	require.EqualValues(t, []string{"NVIDIA_VISIBLE_DEVICES=all", "NVIDIA_DRIVER_CAPABILITIES=all"}, spec.Process.Env)
+}
@@ -29,6 +29,9 @@ import (
 
 var errInvalidDeviceNode = errors.New("invalid device node")
 
+// controlDeviceNodes are the names of the NVIDIA control device nodes.
+var controlDeviceNodes = []string{"nvidiactl", "nvidia-modeset", "nvidia-uvm", "nvidia-uvm-tools"}
+
 // Interface provides a set of utilities for interacting with NVIDIA devices on the system.
 type Interface struct {
 	devices.Devices
@@ -38,6 +41,9 @@ type Interface struct {
 	dryRun bool
 	// devRoot is the root directory where device nodes are expected to exist.
 	devRoot string
+	// deviceMinors is the set of minor numbers for which device nodes are
+	// created. If this is empty, no filtering is applied.
+	deviceMinors map[int64]bool
 
 	mknoder
 }
@@ -72,9 +78,13 @@ func New(opts ...Option) (*Interface, error) {
 }
 
 // CreateNVIDIAControlDevices creates the NVIDIA control device nodes at the configured devRoot.
+// If device minors are configured, only the control devices with these minors are created.
 func (m *Interface) CreateNVIDIAControlDevices() error {
-	controlNodes := []string{"nvidiactl", "nvidia-modeset", "nvidia-uvm", "nvidia-uvm-tools"}
-	for _, node := range controlNodes {
+	for _, node := range controlDeviceNodes {
+		if !m.isRequestedMinor(node) {
+			m.logger.Infof("Skipping: %s not in requested device minors", node)
+			continue
+		}
 		err := m.CreateNVIDIADevice(node)
 		if err != nil {
 			return fmt.Errorf("failed to create device node %s: %w", node, err)
@@ -83,6 +93,41 @@ func (m *Interface) CreateNVIDIAControlDevices() error {
 	return nil
 }
 
+// MissingNVIDIAControlDevices returns the paths of the NVIDIA control device
+// nodes that do not exist at the configured devRoot. If device minors are
+// configured, only the control devices with these minors are considered.
+func (m *Interface) MissingNVIDIAControlDevices() ([]string, error) {
+	var missing []string
+	for _, node := range controlDeviceNodes {
+		if !m.isRequestedMinor(node) {
+			continue
+		}
+		path := filepath.Join(m.devRoot, "dev", node)
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			missing = append(missing, path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %v", path, err)
+		}
+	}
+	return missing, nil
+}
+
+// isRequestedMinor checks whether a device node should be created for the
+// specified node given the configured device minors.
+func (m *Interface) isRequestedMinor(node string) bool {
+	if len(m.deviceMinors) == 0 {
+		return true
+	}
+	minor, err := m.Minor(node)
+	if err != nil {
+		return false
+	}
+	return m.deviceMinors[minor]
+}
+
 // CreateNVIDIADevice creates the specified NVIDIA device node at the configured devRoot.
 func (m *Interface) CreateNVIDIADevice(node string) error {
 	node = filepath.Base(node)
@@ -18,6 +18,8 @@ package nvdevices
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	testlog "github.com/sirupsen/logrus/hooks/test"
@@ -48,6 +50,7 @@ func TestCreateControlDevices(t *testing.T) {
 		description   string
 		root          string
 		devices       devices.Devices
+		deviceMinors  []int64
 		mknodeError   error
 		expectedError error
 		expectedCalls []struct {
@@ -104,6 +107,24 @@ func TestCreateControlDevices(t *testing.T) {
 				{"/some/root/dev/nvidia-uvm-tools", 243, 1},
 			},
 		},
+		{
+			description:  "device minors restrict created nodes",
+			devices:      nvidiaDevices,
+			deviceMinors: []int64{255, 0},
+			expectedCalls: []struct {
+				S  string
+				N1 int
+				N2 int
+			}{
+				{"/dev/nvidiactl", 195, 255},
+				{"/dev/nvidia-uvm", 243, 0},
+			},
+		},
+		{
+			description:  "unknown device minors create no nodes",
+			devices:      nvidiaDevices,
+			deviceMinors: []int64{42},
+		},
 		{
 			description:   "mknod error returns error",
 			devices:       nvidiaDevices,
@@ -141,6 +162,7 @@ func TestCreateControlDevices(t *testing.T) {
 				WithLogger(logger),
 				WithDevRoot(tc.root),
 				WithDevices(tc.devices),
+				WithDeviceMinors(tc.deviceMinors...),
 			)
 			d.mknoder = mknode
 
@@ -150,3 +172,59 @@ func TestCreateControlDevices(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingControlDevices(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	nvidiaDevices := devices.New(
+		devices.WithDeviceToMajor(map[string]int{
+			"nvidia-frontend": 195,
+			"nvidia-uvm":      243,
+		}),
+	)
+
+	devRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(devRoot, "dev"), 0755))
+	for _, node := range []string{"nvidiactl", "nvidia-uvm"} {
+		require.NoError(t, os.WriteFile(filepath.Join(devRoot, "dev", node), nil, 0644))
+	}
+
+	testCases := []struct {
+		description     string
+		deviceMinors    []int64
+		expectedMissing []string
+	}{
+		{
+			description: "missing nodes are returned",
+			expectedMissing: []string{
+				filepath.Join(devRoot, "dev/nvidia-modeset"),
+				filepath.Join(devRoot, "dev/nvidia-uvm-tools"),
+			},
+		},
+		{
+			description:     "device minors restrict checked nodes",
+			deviceMinors:    []int64{255, 1},
+			expectedMissing: []string{filepath.Join(devRoot, "dev/nvidia-uvm-tools")},
+		},
+		{
+			description:  "no nodes are missing",
+			deviceMinors: []int64{255, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d, err := New(
+				WithLogger(logger),
+				WithDevRoot(devRoot),
+				WithDevices(nvidiaDevices),
+				WithDeviceMinors(tc.deviceMinors...),
+			)
+			require.NoError(t, err)
+
+			missing, err := d.MissingNVIDIAControlDevices()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedMissing, missing)
+		})
+	}
+}
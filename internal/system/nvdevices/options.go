@@ -45,6 +45,22 @@ func WithDevRoot(devRoot string) Option {
 	}
 }
 
+// WithDeviceMinors restricts the device nodes that are created to those with
+// the specified minor numbers. If no minors are specified, all device nodes are
+// created.
+func WithDeviceMinors(minors ...int64) Option {
+	return func(i *Interface) {
+		if len(minors) == 0 {
+			i.deviceMinors = nil
+			return
+		}
+		i.deviceMinors = make(map[int64]bool)
+		for _, minor := range minors {
+			i.deviceMinors[minor] = true
+		}
+	}
+}
+
 // WithDevices sets the devices for the Interface struct.
 func WithDevices(devices devices.Devices) Option {
 	return func(i *Interface) {
@@ -40,6 +40,9 @@ const (
 	// ModeCSV configures the CDI spec generator to generate a spec based on the contents of CSV
 	// mountspec files.
 	ModeCSV = "csv"
+	// ModeVfio configures the CDI spec generator to generate a spec for GPUs
+	// that are bound to the vfio-pci driver for PCI passthrough.
+	ModeVfio = "vfio"
 )
 
 // Interface defines the API for the nvcdi package
@@ -41,7 +41,7 @@ func (l *nvmllib) newCommonNVMLDiscoverer() (discover.Discover, error) {
 		l.logger.Warningf("failed to create discoverer for graphics mounts: %v", err)
 	}
 
-	driverFiles, err := NewDriverDiscoverer(l.logger, l.driver, l.nvidiaCDIHookPath, l.ldconfigPath, l.nvmllib)
+	driverFiles, err := NewDriverDiscoverer(l.logger, l.driver, l.nvidiaCDIHookPath, l.ldconfigPath, l.nvmllib, (*nvcdilib)(l).ldcacheUpdateHookOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discoverer for driver files: %v", err)
 	}
@@ -52,5 +52,5 @@ func (l *nvmllib) newCommonNVMLDiscoverer() (discover.Discover, error) {
 		driverFiles,
 	)
 
-	return d, nil
+	return discover.NewLibrarySizeBudget(l.logger, d, l.librarySizeBudget, l.librarySizeBudgetStrict), nil
 }
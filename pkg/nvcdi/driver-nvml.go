@@ -28,13 +28,13 @@ import (
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/cuda"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 )
 
 // NewDriverDiscoverer creates a discoverer for the libraries and binaries associated with a driver installation.
 // The supplied NVML Library is used to query the expected driver version.
-func NewDriverDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string, ldconfigPath string, nvmllib nvml.Interface) (discover.Discover, error) {
+// The specified options are applied to the ldcache update hook for the driver libraries.
+func NewDriverDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath string, ldconfigPath string, nvmllib nvml.Interface, opts ...discover.LDCacheUpdateHookOption) (discover.Discover, error) {
 	if r := nvmllib.Init(); r != nvml.SUCCESS {
 		return nil, fmt.Errorf("failed to initialize NVML: %v", r)
 	}
@@ -49,11 +49,11 @@ func NewDriverDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDI
 		return nil, fmt.Errorf("failed to determine driver version: %v", r)
 	}
 
-	return newDriverVersionDiscoverer(logger, driver, nvidiaCDIHookPath, ldconfigPath, version)
+	return newDriverVersionDiscoverer(logger, driver, nvidiaCDIHookPath, ldconfigPath, version, opts...)
 }
 
-func newDriverVersionDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath, ldconfigPath, version string) (discover.Discover, error) {
-	libraries, err := NewDriverLibraryDiscoverer(logger, driver, nvidiaCDIHookPath, ldconfigPath, version)
+func newDriverVersionDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath, ldconfigPath, version string, opts ...discover.LDCacheUpdateHookOption) (discover.Discover, error) {
+	libraries, err := NewDriverLibraryDiscoverer(logger, driver, nvidiaCDIHookPath, ldconfigPath, version, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discoverer for driver libraries: %v", err)
 	}
@@ -81,7 +81,8 @@ func newDriverVersionDiscoverer(logger logger.Interface, driver *root.Driver, nv
 }
 
 // NewDriverLibraryDiscoverer creates a discoverer for the libraries associated with the specified driver version.
-func NewDriverLibraryDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath, ldconfigPath, version string) (discover.Discover, error) {
+// The specified options are applied to the ldcache update hook for the libraries.
+func NewDriverLibraryDiscoverer(logger logger.Interface, driver *root.Driver, nvidiaCDIHookPath, ldconfigPath, version string, opts ...discover.LDCacheUpdateHookOption) (discover.Discover, error) {
 	libraryPaths, err := getVersionLibs(logger, driver, version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get libraries for driver version: %v", err)
@@ -97,7 +98,10 @@ func NewDriverLibraryDiscoverer(logger logger.Interface, driver *root.Driver, nv
 		libraryPaths,
 	)
 
-	hooks, _ := discover.NewLDCacheUpdateHook(logger, libraries, nvidiaCDIHookPath, ldconfigPath)
+	hooks, err := discover.NewLDCacheUpdateHook(logger, libraries, nvidiaCDIHookPath, ldconfigPath, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ldcache update hook discoverer: %v", err)
+	}
 
 	d := discover.Merge(
 		libraries,
@@ -107,6 +111,32 @@ func NewDriverLibraryDiscoverer(logger logger.Interface, driver *root.Driver, nv
 	return d, nil
 }
 
+// ldcacheUpdateHookOptions returns the options for the ldcache update hook for
+// the driver libraries.
+func (l *nvcdilib) ldcacheUpdateHookOptions() []discover.LDCacheUpdateHookOption {
+	var opts []discover.LDCacheUpdateHookOption
+	if l.ldcacheUpdateMode != "" {
+		opts = append(opts, discover.WithLDCacheUpdateMode(discover.LDCacheUpdateMode(l.ldcacheUpdateMode)))
+	}
+	if l.ldcacheUpdateHookLifecycle != "" {
+		opts = append(opts, discover.WithHookLifecycle(l.ldcacheUpdateHookLifecycle))
+	}
+	if len(l.ldcacheIncludedLibraries) > 0 {
+		opts = append(opts, discover.WithIncludedLibraries(l.ldcacheIncludedLibraries...))
+	}
+	if len(l.ldcacheExcludedLibraries) > 0 {
+		opts = append(opts, discover.WithExcludedLibraries(l.ldcacheExcludedLibraries...))
+	}
+	if l.ldsoconfFolders {
+		folders, err := l.driver.LdsoconfDirectories()
+		if err != nil {
+			l.logger.Warningf("Failed to get library directories from ld.so.conf.d: %v", err)
+		}
+		opts = append(opts, discover.WithDriverFolders(l.driver.Root, folders...))
+	}
+	return opts
+}
+
 func getUTSRelease() (string, error) {
 	utsname := &unix.Utsname{}
 	if err := unix.Uname(utsname); err != nil {
@@ -190,13 +220,11 @@ func NewDriverBinariesDiscoverer(logger logger.Interface, driverRoot string) dis
 func getVersionLibs(logger logger.Interface, driver *root.Driver, version string) ([]string, error) {
 	logger.Infof("Using driver version %v", version)
 
-	libCudaPaths, err := cuda.New(
-		driver.Libraries(),
-	).Locate("." + version)
+	libCudaPath, err := driver.VersionLibraryPathForVersion(version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to locate libcuda.so.%v: %v", version, err)
 	}
-	libRoot := filepath.Dir(libCudaPaths[0])
+	libRoot := filepath.Dir(libCudaPath)
 
 	libraries := lookup.NewFileLocator(
 		lookup.WithLogger(logger),
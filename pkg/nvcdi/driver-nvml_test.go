@@ -0,0 +1,149 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
+)
+
+func TestGetVersionLibs(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	for _, library := range []string{
+		"usr/lib64/libcuda.so.535.161.08",
+		"usr/lib64/libnvidia-ml.so.535.161.08",
+		"usr/local/cuda/compat/libcuda.so.550.54.15",
+		"usr/local/cuda/compat/libnvidia-ptxjitcompiler.so.550.54.15",
+	} {
+		path := filepath.Join(driverRoot, library)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	testCases := []struct {
+		description   string
+		options       []root.Option
+		version       string
+		expectedLibs  []string
+		expectedError bool
+	}{
+		{
+			description: "default pattern locates libcuda.so",
+			version:     "535.161.08",
+			expectedLibs: []string{
+				"/usr/lib64/libcuda.so.535.161.08",
+				"/usr/lib64/libnvidia-ml.so.535.161.08",
+			},
+		},
+		{
+			description:   "default pattern does not locate compat library",
+			version:       "550.54.15",
+			expectedError: true,
+		},
+		{
+			description: "pattern locates compat library",
+			options: []root.Option{
+				root.WithVersionLibraryPattern("usr/local/cuda/compat/libcuda.so.*.*"),
+			},
+			version: "550.54.15",
+			expectedLibs: []string{
+				"/usr/local/cuda/compat/libcuda.so.550.54.15",
+				"/usr/local/cuda/compat/libnvidia-ptxjitcompiler.so.550.54.15",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driver := root.New(
+				append(tc.options,
+					root.WithLogger(logger),
+					root.WithDriverRoot(driverRoot),
+				)...,
+			)
+
+			libs, err := getVersionLibs(logger, driver, tc.version)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.expectedLibs, libs)
+		})
+	}
+}
+
+func TestLDCacheUpdateHookOptionsLibraries(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	libraries := &discover.DiscoverMock{
+		MountsFunc: func() ([]discover.Mount, error) {
+			mounts := []discover.Mount{
+				{Path: "/usr/lib64/libcuda.so.535.161.08", HostPath: "/usr/lib64/libcuda.so.535.161.08"},
+				{Path: "/usr/lib64/vdpau/libvdpau_nvidia.so.535.161.08", HostPath: "/usr/lib64/vdpau/libvdpau_nvidia.so.535.161.08"},
+			}
+			return mounts, nil
+		},
+	}
+
+	testCases := []struct {
+		description  string
+		options      []Option
+		expectedArgs []string
+	}{
+		{
+			description:  "all library folders are included by default",
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64", "--folder", "/usr/lib64/vdpau"},
+		},
+		{
+			description:  "excluded libraries are ignored",
+			options:      []Option{WithLDCacheExcludedLibraries("libvdpau_*")},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+		},
+		{
+			description:  "only included libraries are considered",
+			options:      []Option{WithLDCacheIncludedLibraries("libvdpau_*")},
+			expectedArgs: []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64/vdpau"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := &nvcdilib{logger: logger}
+			for _, opt := range tc.options {
+				opt(l)
+			}
+
+			d, err := discover.NewLDCacheUpdateHook(logger, libraries, "/usr/bin/nvidia-cdi-hook", "", l.ldcacheUpdateHookOptions()...)
+			require.NoError(t, err)
+
+			hooks, err := d.Hooks()
+			require.NoError(t, err)
+			require.Len(t, hooks, 1)
+			require.EqualValues(t, tc.expectedArgs, hooks[0].Args)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+)
+
+const (
+	// vfioDriverName is the name of the kernel driver that GPUs are bound to
+	// for PCI passthrough.
+	vfioDriverName = "vfio-pci"
+	// vfioDevicePath is the path of the vfio container device node.
+	vfioDevicePath = "/dev/vfio/vfio"
+)
+
+type vfiolib nvcdilib
+
+var _ Interface = (*vfiolib)(nil)
+
+// GetSpec returns the complete CDI spec for the GPUs bound to the vfio-pci
+// driver. The vendor and class of the library are used to construct the kind.
+func (l *vfiolib) GetSpec() (spec.Interface, error) {
+	deviceSpecs, err := l.GetAllDeviceSpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := l.GetCommonEdits()
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.New(
+		spec.WithDeviceSpecs(deviceSpecs),
+		spec.WithEdits(*edits.ContainerEdits),
+		spec.WithVendor(l.vendor),
+		spec.WithClass(l.class),
+		spec.WithMergedDeviceOptions(l.mergedDeviceOptions...),
+	)
+}
+
+// GetAllDeviceSpecs returns a device spec for each GPU that is bound to the
+// vfio-pci driver. GPUs that are not bound to the vfio-pci driver are skipped
+// with a warning. Each device is named by the index of the GPU and includes
+// the device node of its IOMMU group. SR-IOV virtual functions that are bound
+// to the vfio-pci driver are named by the index of their physical function
+// and their index among its virtual functions (e.g. 0-vf1). Virtual functions
+// have their own IOMMU groups. The host paths of the device nodes are located in
+// the configured dev root.
+func (l *vfiolib) GetAllDeviceSpecs() ([]specs.Device, error) {
+	gpus, err := l.nvpcilib.GetGPUs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GPU information: %w", err)
+	}
+
+	vfs, err := l.getVirtualFunctions()
+	if err != nil {
+		return nil, err
+	}
+
+	var deviceSpecs []specs.Device
+	var unbound []string
+	for i, gpu := range gpus {
+		var vfDeviceSpecs []specs.Device
+		for j, vf := range vfs[gpu.Address] {
+			if vf.Driver != vfioDriverName {
+				unbound = append(unbound, describeDriver(vf))
+				continue
+			}
+			vfDeviceSpecs = append(vfDeviceSpecs, l.getDeviceSpec(fmt.Sprintf("%d-vf%d", i, j), vf))
+		}
+
+		switch {
+		case gpu.Driver == vfioDriverName:
+			deviceSpecs = append(deviceSpecs, l.getDeviceSpec(strconv.Itoa(i), gpu))
+		case len(vfDeviceSpecs) == 0:
+			// A physical function that is not bound to vfio-pci is expected if
+			// its virtual functions are used for passthrough.
+			unbound = append(unbound, describeDriver(gpu))
+		}
+		deviceSpecs = append(deviceSpecs, vfDeviceSpecs...)
+	}
+
+	if len(unbound) > 0 {
+		l.logger.Warningf("Skipping GPUs that are not bound to the %v driver: %v", vfioDriverName, strings.Join(unbound, ", "))
+	}
+	if len(deviceSpecs) == 0 && l.requireVfioDevices {
+		return nil, fmt.Errorf("no GPUs bound to the %v driver found", vfioDriverName)
+	}
+	return deviceSpecs, nil
+}
+
+// describeDriver returns a description of the specified device and the driver
+// that it is bound to for use in log messages.
+func describeDriver(d *nvpci.NvidiaPCIDevice) string {
+	driver := d.Driver
+	if driver == "" {
+		driver = "none"
+	}
+	return fmt.Sprintf("%v (driver: %v)", d.Address, driver)
+}
+
+// getVirtualFunctions returns the SR-IOV virtual functions of the GPUs in the
+// system indexed by the PCI address of their physical function. The virtual
+// functions of each GPU are returned in the order that they are enumerated.
+func (l *vfiolib) getVirtualFunctions() (map[string][]*nvpci.NvidiaPCIDevice, error) {
+	devices, err := l.nvpcilib.GetAllDevices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PCI device information: %w", err)
+	}
+
+	vfs := make(map[string][]*nvpci.NvidiaPCIDevice)
+	for _, d := range devices {
+		if !d.IsGPU() || !d.SriovInfo.IsVF() || d.SriovInfo.VirtualFunction.PhysicalFunction == nil {
+			continue
+		}
+		pf := d.SriovInfo.VirtualFunction.PhysicalFunction.Address
+		vfs[pf] = append(vfs[pf], d)
+	}
+	return vfs, nil
+}
+
+// getDeviceSpec returns the device spec with the specified name for a device
+// bound to the vfio-pci driver.
+func (l *vfiolib) getDeviceSpec(name string, d *nvpci.NvidiaPCIDevice) specs.Device {
+	return specs.Device{
+		Name: name,
+		ContainerEdits: specs.ContainerEdits{
+			DeviceNodes: []*specs.DeviceNode{
+				l.getDeviceNode(filepath.Join("/dev/vfio", strconv.Itoa(d.IommuGroup))),
+			},
+		},
+	}
+}
+
+// getDeviceNode returns the device node for the specified path in the
+// container. If a dev root is configured, the host path of the device node is
+// the path in the dev root.
+func (l *vfiolib) getDeviceNode(path string) *specs.DeviceNode {
+	deviceNode := &specs.DeviceNode{
+		Path: path,
+	}
+	if hostPath := filepath.Join(l.devRoot, path); hostPath != path {
+		deviceNode.HostPath = hostPath
+	}
+	return deviceNode
+}
+
+// GetCommonEdits returns the edits required by all vfio devices. This is the
+// vfio container device node. The host path of the device node is
+// located in the configured dev root.
+func (l *vfiolib) GetCommonEdits() (*cdi.ContainerEdits, error) {
+	e := edits.NewContainerEdits()
+	e.DeviceNodes = []*specs.DeviceNode{
+		l.getDeviceNode(vfioDevicePath),
+	}
+	return e, nil
+}
+
+// GetGPUDeviceEdits is unsupported for the vfiolib specs
+func (l *vfiolib) GetGPUDeviceEdits(device.Device) (*cdi.ContainerEdits, error) {
+	return nil, fmt.Errorf("GetGPUDeviceEdits is not supported")
+}
+
+// GetGPUDeviceSpecs is unsupported for the vfiolib specs
+func (l *vfiolib) GetGPUDeviceSpecs(int, device.Device) ([]specs.Device, error) {
+	return nil, fmt.Errorf("GetGPUDeviceSpecs is not supported")
+}
+
+// GetMIGDeviceEdits is unsupported for the vfiolib specs
+func (l *vfiolib) GetMIGDeviceEdits(device.Device, device.MigDevice) (*cdi.ContainerEdits, error) {
+	return nil, fmt.Errorf("GetMIGDeviceEdits is not supported")
+}
+
+// GetMIGDeviceSpecs is unsupported for the vfiolib specs
+func (l *vfiolib) GetMIGDeviceSpecs(int, device.Device, int, device.MigDevice) ([]specs.Device, error) {
+	return nil, fmt.Errorf("GetMIGDeviceSpecs is not supported")
+}
+
+// GetDeviceSpecsByID is unsupported for the vfiolib specs
+func (l *vfiolib) GetDeviceSpecsByID(...string) ([]specs.Device, error) {
+	return nil, fmt.Errorf("GetDeviceSpecsByID is not supported")
+}
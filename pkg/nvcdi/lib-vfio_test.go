@@ -0,0 +1,356 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"testing"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
+	"github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+// fakePCILib is an nvpci.Interface that returns a fixed set of devices.
+type fakePCILib struct {
+	nvpci.Interface
+	devices []*nvpci.NvidiaPCIDevice
+}
+
+func (f *fakePCILib) GetAllDevices() ([]*nvpci.NvidiaPCIDevice, error) {
+	return f.devices, nil
+}
+
+// GetGPUs returns the GPUs that are not SR-IOV virtual functions.
+func (f *fakePCILib) GetGPUs() ([]*nvpci.NvidiaPCIDevice, error) {
+	var gpus []*nvpci.NvidiaPCIDevice
+	for _, d := range f.devices {
+		if d.IsGPU() && !d.SriovInfo.IsVF() {
+			gpus = append(gpus, d)
+		}
+	}
+	return gpus, nil
+}
+
+func TestVfioGetSpec(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	nvpcilib := &fakePCILib{
+		devices: []*nvpci.NvidiaPCIDevice{
+			{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "vfio-pci", IommuGroup: 45},
+			{Address: "0000:86:00.0", Class: nvpci.PCI3dControllerClass, Driver: "vfio-pci", IommuGroup: 87},
+		},
+	}
+
+	testCases := []struct {
+		description  string
+		options      []Option
+		expectedKind string
+	}{
+		{
+			description:  "default vendor and class",
+			expectedKind: "nvidia.com/pgpu",
+		},
+		{
+			description:  "custom vendor and class",
+			options:      []Option{WithVendor("example.com"), WithClass("passthrough")},
+			expectedKind: "example.com/passthrough",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithMode(ModeVfio),
+				WithPCILib(nvpcilib),
+			}, tc.options...)
+
+			lib, err := New(opts...)
+			require.NoError(t, err)
+
+			// The spec is generated by the vfio library itself and through the
+			// wrapper returned by New.
+			for _, l := range []Interface{lib.(*wrapper).Interface, lib} {
+				spec, err := l.GetSpec()
+				require.NoError(t, err)
+
+				raw := spec.Raw()
+				require.Equal(t, tc.expectedKind, raw.Kind)
+				require.EqualValues(t,
+					[]specs.Device{
+						{
+							Name: "0",
+							ContainerEdits: specs.ContainerEdits{
+								DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/45"}},
+							},
+						},
+						{
+							Name: "1",
+							ContainerEdits: specs.ContainerEdits{
+								DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/87"}},
+							},
+						},
+					},
+					raw.Devices,
+				)
+				require.EqualValues(t, []*specs.DeviceNode{{Path: "/dev/vfio/vfio"}}, raw.ContainerEdits.DeviceNodes)
+			}
+		})
+	}
+}
+
+func TestVfioGetAllDeviceSpecsVirtualFunctions(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	pf := &nvpci.NvidiaPCIDevice{
+		Address:    "0000:3b:00.0",
+		Class:      nvpci.PCI3dControllerClass,
+		Driver:     "nvidia",
+		IommuGroup: 45,
+		SriovInfo: nvpci.SriovInfo{
+			PhysicalFunction: &nvpci.SriovPhysicalFunction{TotalVFs: 16, NumVFs: 2},
+		},
+	}
+	newVF := func(address string, iommuGroup int) *nvpci.NvidiaPCIDevice {
+		return &nvpci.NvidiaPCIDevice{
+			Address:    address,
+			Class:      nvpci.PCI3dControllerClass,
+			Driver:     "vfio-pci",
+			IommuGroup: iommuGroup,
+			SriovInfo: nvpci.SriovInfo{
+				VirtualFunction: &nvpci.SriovVirtualFunction{PhysicalFunction: pf},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description     string
+		pfDriver        string
+		expectedDevices []specs.Device
+	}{
+		{
+			description: "virtual functions of a PF bound to nvidia",
+			pfDriver:    "nvidia",
+			expectedDevices: []specs.Device{
+				{
+					Name: "0-vf0",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/201"}},
+					},
+				},
+				{
+					Name: "0-vf1",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/202"}},
+					},
+				},
+			},
+		},
+		{
+			description: "virtual functions of a PF bound to vfio-pci",
+			pfDriver:    "vfio-pci",
+			expectedDevices: []specs.Device{
+				{
+					Name: "0",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/45"}},
+					},
+				},
+				{
+					Name: "0-vf0",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/201"}},
+					},
+				},
+				{
+					Name: "0-vf1",
+					ContainerEdits: specs.ContainerEdits{
+						DeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/202"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			pf.Driver = tc.pfDriver
+			nvpcilib := &fakePCILib{
+				devices: []*nvpci.NvidiaPCIDevice{
+					pf,
+					newVF("0000:3b:00.4", 201),
+					newVF("0000:3b:00.5", 202),
+				},
+			}
+
+			lib, err := New(
+				WithLogger(logger),
+				WithMode(ModeVfio),
+				WithPCILib(nvpcilib),
+			)
+			require.NoError(t, err)
+
+			deviceSpecs, err := lib.GetAllDeviceSpecs()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedDevices, deviceSpecs)
+		})
+	}
+}
+
+func TestVfioGetAllDeviceSpecsUnboundGPUs(t *testing.T) {
+	logger, logHook := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description     string
+		devices         []*nvpci.NvidiaPCIDevice
+		options         []Option
+		expectedNames   []string
+		expectedWarning string
+		expectedError   bool
+	}{
+		{
+			description: "mix of nvidia and vfio-pci bound GPUs",
+			devices: []*nvpci.NvidiaPCIDevice{
+				{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "nvidia", IommuGroup: 45},
+				{Address: "0000:5e:00.0", Class: nvpci.PCI3dControllerClass, Driver: "vfio-pci", IommuGroup: 62},
+				{Address: "0000:86:00.0", Class: nvpci.PCI3dControllerClass, IommuGroup: 87},
+			},
+			expectedNames:   []string{"1"},
+			expectedWarning: "Skipping GPUs that are not bound to the vfio-pci driver: 0000:3b:00.0 (driver: nvidia), 0000:86:00.0 (driver: none)",
+		},
+		{
+			description: "all GPUs bound to vfio-pci",
+			devices: []*nvpci.NvidiaPCIDevice{
+				{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "vfio-pci", IommuGroup: 45},
+			},
+			expectedNames: []string{"0"},
+		},
+		{
+			description: "no vfio-pci bound GPUs",
+			devices: []*nvpci.NvidiaPCIDevice{
+				{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "nvidia", IommuGroup: 45},
+			},
+			expectedWarning: "Skipping GPUs that are not bound to the vfio-pci driver: 0000:3b:00.0 (driver: nvidia)",
+		},
+		{
+			description: "no vfio-pci bound GPUs is an error if required",
+			devices: []*nvpci.NvidiaPCIDevice{
+				{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "nvidia", IommuGroup: 45},
+			},
+			options:         []Option{WithRequireVfioDevices(true)},
+			expectedWarning: "Skipping GPUs that are not bound to the vfio-pci driver: 0000:3b:00.0 (driver: nvidia)",
+			expectedError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithMode(ModeVfio),
+				WithPCILib(&fakePCILib{devices: tc.devices}),
+			}, tc.options...)
+			lib, err := New(opts...)
+			require.NoError(t, err)
+
+			logHook.Reset()
+
+			deviceSpecs, err := lib.GetAllDeviceSpecs()
+
+			var warnings []string
+			for _, entry := range logHook.AllEntries() {
+				if entry.Level == logrus.WarnLevel {
+					warnings = append(warnings, entry.Message)
+				}
+			}
+			if tc.expectedWarning == "" {
+				require.Empty(t, warnings)
+			} else {
+				require.Equal(t, []string{tc.expectedWarning}, warnings)
+			}
+
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, d := range deviceSpecs {
+				names = append(names, d.Name)
+			}
+			require.EqualValues(t, tc.expectedNames, names)
+		})
+	}
+}
+
+func TestVfioGetSpecDevRoot(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	nvpcilib := &fakePCILib{
+		devices: []*nvpci.NvidiaPCIDevice{
+			{Address: "0000:3b:00.0", Class: nvpci.PCI3dControllerClass, Driver: "vfio-pci", IommuGroup: 45},
+		},
+	}
+
+	testCases := []struct {
+		description         string
+		options             []Option
+		expectedDeviceNodes []*specs.DeviceNode
+		expectedCommonNodes []*specs.DeviceNode
+	}{
+		{
+			description:         "default dev root",
+			expectedDeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/45"}},
+			expectedCommonNodes: []*specs.DeviceNode{{Path: "/dev/vfio/vfio"}},
+		},
+		{
+			description:         "custom dev root",
+			options:             []Option{WithDevRoot("/host")},
+			expectedDeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/45", HostPath: "/host/dev/vfio/45"}},
+			expectedCommonNodes: []*specs.DeviceNode{{Path: "/dev/vfio/vfio", HostPath: "/host/dev/vfio/vfio"}},
+		},
+		{
+			description:         "dev root defaults to driver root",
+			options:             []Option{WithDriverRoot("/run/nvidia/driver")},
+			expectedDeviceNodes: []*specs.DeviceNode{{Path: "/dev/vfio/45", HostPath: "/run/nvidia/driver/dev/vfio/45"}},
+			expectedCommonNodes: []*specs.DeviceNode{{Path: "/dev/vfio/vfio", HostPath: "/run/nvidia/driver/dev/vfio/vfio"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithMode(ModeVfio),
+				WithPCILib(nvpcilib),
+			}, tc.options...)
+			lib, err := New(opts...)
+			require.NoError(t, err)
+
+			spec, err := lib.GetSpec()
+			require.NoError(t, err)
+
+			raw := spec.Raw()
+			require.Len(t, raw.Devices, 1)
+			require.EqualValues(t, tc.expectedDeviceNodes, raw.Devices[0].ContainerEdits.DeviceNodes)
+			require.EqualValues(t, tc.expectedCommonNodes, raw.ContainerEdits.DeviceNodes)
+		})
+	}
+}
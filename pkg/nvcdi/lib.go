@@ -21,9 +21,12 @@ import (
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/platform-support/tegra/csv"
@@ -38,6 +41,9 @@ type wrapper struct {
 	class  string
 
 	mergedDeviceOptions []transform.MergedDeviceOption
+	specOptions         []spec.Option
+
+	getAnnotations func() map[string]string
 }
 
 type nvcdilib struct {
@@ -53,16 +59,41 @@ type nvcdilib struct {
 	configSearchPaths  []string
 	librarySearchPaths []string
 
+	versionLibraryPattern string
+	sonameFallback        bool
+	ldsoconfFolders       bool
+	ldcacheUpdateMode     string
+
+	ldcacheUpdateHookLifecycle string
+	ldcacheIncludedLibraries   []string
+	ldcacheExcludedLibraries   []string
+
 	csvFiles          []string
 	csvIgnorePatterns []string
 
 	vendor string
 	class  string
 
-	driver  *root.Driver
-	infolib info.Interface
+	driver   *root.Driver
+	infolib  info.Interface
+	nvpcilib nvpci.Interface
+
+	validateMigDevices bool
+	verifyMounts       bool
+	migCaps            bool
+	generationMetadata bool
+
+	managementEditsOnly bool
+
+	requireVfioDevices bool
+
+	librarySizeBudget       int64
+	librarySizeBudgetStrict bool
+
+	editsOptions []edits.Option
 
 	mergedDeviceOptions []transform.MergedDeviceOption
+	specOptions         []spec.Option
 }
 
 // New creates a new nvcdi library
@@ -94,6 +125,8 @@ func New(opts ...Option) (Interface, error) {
 		root.WithLogger(l.logger),
 		root.WithDriverRoot(l.driverRoot),
 		root.WithLibrarySearchPaths(l.librarySearchPaths...),
+		root.WithVersionLibraryPattern(l.versionLibraryPattern),
+		root.WithSonameFallback(l.sonameFallback),
 	)
 	if l.nvmllib == nil {
 		var nvmlOpts []nvml.LibraryOption
@@ -119,8 +152,10 @@ func New(opts ...Option) (Interface, error) {
 		)
 	}
 
+	mode := l.resolveMode()
+
 	var lib Interface
-	switch l.resolveMode() {
+	switch mode {
 	case ModeCSV:
 		if len(l.csvFiles) == 0 {
 			l.csvFiles = csv.DefaultFileList()
@@ -145,6 +180,14 @@ func New(opts ...Option) (Interface, error) {
 			l.class = "mofed"
 		}
 		lib = (*mofedlib)(l)
+	case ModeVfio:
+		if l.class == "" {
+			l.class = "pgpu"
+		}
+		if l.nvpcilib == nil {
+			l.nvpcilib = nvpci.New()
+		}
+		lib = (*vfiolib)(l)
 	default:
 		return nil, fmt.Errorf("unknown mode %q", l.mode)
 	}
@@ -154,6 +197,12 @@ func New(opts ...Option) (Interface, error) {
 		vendor:              l.vendor,
 		class:               l.class,
 		mergedDeviceOptions: l.mergedDeviceOptions,
+		specOptions:         l.specOptions,
+	}
+	if l.generationMetadata {
+		w.getAnnotations = func() map[string]string {
+			return l.getGenerationAnnotations(mode)
+		}
 	}
 	return &w, nil
 }
@@ -170,13 +219,20 @@ func (l *wrapper) GetSpec() (spec.Interface, error) {
 		return nil, err
 	}
 
-	return spec.New(
+	var annotations map[string]string
+	if l.getAnnotations != nil {
+		annotations = l.getAnnotations()
+	}
+
+	opts := []spec.Option{
 		spec.WithDeviceSpecs(deviceSpecs),
 		spec.WithEdits(*edits.ContainerEdits),
 		spec.WithVendor(l.vendor),
 		spec.WithClass(l.class),
 		spec.WithMergedDeviceOptions(l.mergedDeviceOptions...),
-	)
+		spec.WithAnnotations(annotations),
+	}
+	return spec.New(append(opts, l.specOptions...)...)
 }
 
 // GetCommonEdits returns the wrapped edits and adds additional edits on top.
@@ -236,3 +292,19 @@ func (l *nvcdilib) getCudaVersion() (string, error) {
 	}
 	return version, nil
 }
+
+// editsFromDiscoverer creates the CDI container edits for the specified
+// discoverer applying the edits options configured for the library.
+func (l *nvcdilib) editsFromDiscoverer(d discover.Discover) (*cdi.ContainerEdits, error) {
+	opts := append([]edits.Option{edits.WithLogger(l.logger)}, l.editsOptions...)
+	return edits.FromDiscoverer(d, opts...)
+}
+
+// withVerifyMountsHook adds a hook that verifies the mounts of the specified
+// discoverer in the container if this is enabled for the library.
+func (l *nvcdilib) withVerifyMountsHook(d discover.Discover) discover.Discover {
+	if !l.verifyMounts {
+		return d
+	}
+	return discover.Merge(d, discover.NewVerifyMountsHook(l.logger, d, l.nvidiaCDIHookPath))
+}
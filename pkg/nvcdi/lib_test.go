@@ -0,0 +1,307 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+)
+
+func TestEditsFromDiscoverer(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	testCases := []struct {
+		description   string
+		options       []Option
+		devices       []discover.Device
+		mounts        []discover.Mount
+		mountsError   error
+		hooks         []discover.Hook
+		expectedError bool
+		expected      *specs.ContainerEdits
+	}{
+		{
+			description: "device node info is not populated by default",
+			devices:     []discover.Device{{Path: "/dev/null", HostPath: "/dev/null"}},
+			expected: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/null"}},
+			},
+		},
+		{
+			description: "device node info is populated",
+			options:     []Option{WithPopulateDeviceNodeInfo(true)},
+			devices:     []discover.Device{{Path: "/dev/null", HostPath: "/dev/null"}},
+			expected: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/null", Type: "c", Major: 1, Minor: 3}},
+			},
+		},
+		{
+			description:   "discovery errors are returned by default",
+			devices:       []discover.Device{{Path: "/dev/null", HostPath: "/dev/null"}},
+			mountsError:   fmt.Errorf("failed to locate library"),
+			expectedError: true,
+		},
+		{
+			description: "discovery errors are tolerated",
+			options:     []Option{WithTolerateDiscoveryErrors(true)},
+			devices:     []discover.Device{{Path: "/dev/null", HostPath: "/dev/null"}},
+			mountsError: fmt.Errorf("failed to locate library"),
+			expected: &specs.ContainerEdits{
+				DeviceNodes: []*specs.DeviceNode{{Path: "/dev/null"}},
+			},
+		},
+		{
+			description: "mounts are not simplified by default",
+			mounts: []discover.Mount{
+				{Path: "/usr/lib64/libcuda.so.1", HostPath: "/usr/lib64/libcuda.so.1"},
+				{Path: "/etc/vulkan/icd.d/nvidia_icd.json", HostPath: "/etc/vulkan/icd.d/nvidia_icd.json"},
+				{Path: "/usr/lib64/libcuda.so.1", HostPath: "/opt/compat/libcuda.so.1"},
+			},
+			expected: &specs.ContainerEdits{
+				Mounts: []*specs.Mount{
+					{ContainerPath: "/usr/lib64/libcuda.so.1", HostPath: "/usr/lib64/libcuda.so.1"},
+					{ContainerPath: "/etc/vulkan/icd.d/nvidia_icd.json", HostPath: "/etc/vulkan/icd.d/nvidia_icd.json"},
+					{ContainerPath: "/usr/lib64/libcuda.so.1", HostPath: "/opt/compat/libcuda.so.1"},
+				},
+			},
+		},
+		{
+			description: "shadowed mounts are removed and mounts sorted",
+			options:     []Option{WithSimplifyEdits(true)},
+			mounts: []discover.Mount{
+				{Path: "/usr/lib64/libcuda.so.1", HostPath: "/usr/lib64/libcuda.so.1"},
+				{Path: "/etc/vulkan/icd.d/nvidia_icd.json", HostPath: "/etc/vulkan/icd.d/nvidia_icd.json"},
+				{Path: "/usr/lib64/libcuda.so.1", HostPath: "/opt/compat/libcuda.so.1"},
+			},
+			expected: &specs.ContainerEdits{
+				Mounts: []*specs.Mount{
+					{ContainerPath: "/etc/vulkan/icd.d/nvidia_icd.json", HostPath: "/etc/vulkan/icd.d/nvidia_icd.json"},
+					{ContainerPath: "/usr/lib64/libcuda.so.1", HostPath: "/opt/compat/libcuda.so.1"},
+				},
+			},
+		},
+		{
+			description: "library mounts are made read-only",
+			options:     []Option{WithReadOnlyLibraries(true)},
+			mounts: []discover.Mount{
+				{Path: "/usr/lib64/libcuda.so.1", HostPath: "/usr/lib64/libcuda.so.1", Options: []string{"rw", "bind"}},
+				{Path: "/var/run/nvidia-persistenced/socket", HostPath: "/var/run/nvidia-persistenced/socket", Options: []string{"rw", "bind"}},
+			},
+			expected: &specs.ContainerEdits{
+				Mounts: []*specs.Mount{
+					{ContainerPath: "/usr/lib64/libcuda.so.1", HostPath: "/usr/lib64/libcuda.so.1", Options: []string{"ro", "nosuid", "nodev", "bind"}},
+					{ContainerPath: "/var/run/nvidia-persistenced/socket", HostPath: "/var/run/nvidia-persistenced/socket", Options: []string{"rw", "bind"}},
+				},
+			},
+		},
+		{
+			description: "missing hook paths are allowed by default",
+			hooks: []discover.Hook{
+				{Lifecycle: "createContainer", Path: "/not/a/path/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}},
+			},
+			expected: &specs.ContainerEdits{
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/not/a/path/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}},
+				},
+			},
+		},
+		{
+			description: "missing hook paths are an error if verified",
+			options:     []Option{WithVerifyHookPaths(true)},
+			hooks: []discover.Hook{
+				{Lifecycle: "createContainer", Path: "/not/a/path/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}},
+			},
+			expectedError: true,
+		},
+		{
+			description: "excluded hooks are removed",
+			options:     []Option{WithExcludedHooks("update-ldcache")},
+			hooks: []discover.Hook{
+				{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"}},
+				{Lifecycle: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "update-ldcache"}},
+			},
+			expected: &specs.ContainerEdits{
+				Hooks: []*specs.Hook{
+					{HookName: "createContainer", Path: "/usr/bin/nvidia-cdi-hook", Args: []string{"nvidia-cdi-hook", "create-symlinks", "--link", "libcuda.so.1::/usr/lib64/libcuda.so"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			l := &nvcdilib{logger: logger}
+			for _, opt := range tc.options {
+				opt(l)
+			}
+
+			d := &discover.DiscoverMock{
+				DevicesFunc: func() ([]discover.Device, error) {
+					return tc.devices, nil
+				},
+				EnvVarsFunc: func() ([]discover.EnvVar, error) {
+					return nil, nil
+				},
+				MountsFunc: func() ([]discover.Mount, error) {
+					return tc.mounts, tc.mountsError
+				},
+				HooksFunc: func() ([]discover.Hook, error) {
+					return tc.hooks, nil
+				},
+			}
+
+			e, err := l.editsFromDiscoverer(d)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, e.ContainerEdits)
+		})
+	}
+}
+
+func TestGetSpecOptions(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), nil, 0644))
+
+	testCases := []struct {
+		description         string
+		options             []Option
+		expectedAnnotations []string
+		expectedExtension   string
+	}{
+		{
+			description:       "no annotations by default",
+			expectedExtension: ".yaml",
+		},
+		{
+			description: "generation metadata is included",
+			options:     []Option{WithGenerationMetadata(true)},
+			expectedAnnotations: []string{
+				GeneratorVersionAnnotation,
+				GeneratorTimestampAnnotation,
+				GeneratorModeAnnotation,
+			},
+			expectedExtension: ".yaml",
+		},
+		{
+			description:       "spec options are applied",
+			options:           []Option{WithSpecOptions(spec.WithFormat(spec.FormatJSON))},
+			expectedExtension: ".json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+				WithMode(ModeManagement),
+				WithManagementEditsOnly(true),
+			}, tc.options...)
+
+			lib, err := New(opts...)
+			require.NoError(t, err)
+
+			s, err := lib.GetSpec()
+			require.NoError(t, err)
+
+			var annotations []string
+			for k := range s.Raw().Annotations {
+				annotations = append(annotations, k)
+			}
+			require.ElementsMatch(t, tc.expectedAnnotations, annotations)
+			if len(tc.expectedAnnotations) > 0 {
+				require.Equal(t, ModeManagement, s.Raw().Annotations[GeneratorModeAnnotation])
+			}
+
+			outputDir := t.TempDir()
+			require.NoError(t, s.Save(filepath.Join(outputDir, "spec")))
+			require.FileExists(t, filepath.Join(outputDir, "spec"+tc.expectedExtension))
+		})
+	}
+}
+
+func TestWithVerifyMounts(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), nil, 0644))
+
+	ldcacheHook := &specs.Hook{
+		HookName: "createContainer",
+		Path:     "/usr/bin/nvidia-cdi-hook",
+		Args:     []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+	}
+
+	testCases := []struct {
+		description   string
+		options       []Option
+		expectedHooks []*specs.Hook
+	}{
+		{
+			description:   "mounts are not verified by default",
+			expectedHooks: []*specs.Hook{ldcacheHook},
+		},
+		{
+			description: "verify-mounts hook is added",
+			options:     []Option{WithVerifyMounts(true)},
+			expectedHooks: []*specs.Hook{
+				ldcacheHook,
+				{
+					HookName: "createContainer",
+					Path:     "/usr/bin/nvidia-cdi-hook",
+					Args:     []string{"nvidia-cdi-hook", "verify-mounts", "--path", "/usr/lib64/libcuda.so.999.88.77"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+				WithMode(ModeManagement),
+				WithManagementEditsOnly(true),
+			}, tc.options...)
+
+			lib, err := New(opts...)
+			require.NoError(t, err)
+
+			s, err := lib.GetSpec()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedHooks, s.Raw().ContainerEdits.Hooks)
+		})
+	}
+}
@@ -0,0 +1,274 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform"
+	transformroot "github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform/root"
+)
+
+// ManagementSpecConfig defines the inputs used to generate a CDI specification
+// for management containers.
+type ManagementSpecConfig struct {
+	// DriverRoot is the path at which the driver installation is accessible
+	// to the caller.
+	DriverRoot string
+	// TargetDriverRoot is the path at which the driver installation is
+	// accessible where the spec is consumed. If this is empty, DriverRoot is
+	// used.
+	TargetDriverRoot string
+	// DevRoot is the path at which /dev is accessible to the caller. If this
+	// is empty, DriverRoot is used.
+	DevRoot string
+	// TargetDevRoot is the path at which /dev is accessible where the spec
+	// is consumed. If this is empty, TargetDriverRoot is used.
+	TargetDevRoot string
+	// NVIDIACDIHookPath is the path to the nvidia-cdi-hook executable
+	// referenced by the hooks in the spec.
+	NVIDIACDIHookPath string
+	// Vendor and Class define the CDI kind of the generated spec.
+	Vendor string
+	Class  string
+	// DeviceIndices restricts the GPUs for which specs are generated by
+	// GetManagementDeviceSpecs to those with the specified indices. If this is
+	// empty, specs are generated for all GPUs.
+	DeviceIndices []int
+}
+
+const (
+	// DefaultManagementVendor is the default vendor for management CDI specs.
+	DefaultManagementVendor = "management.nvidia.com"
+	// DefaultManagementClass is the default class for management CDI specs.
+	DefaultManagementClass = "gpu"
+)
+
+// NewManagementLibrary creates a CDI library that generates specs for use in
+// management containers. The mode is always set to ModeManagement and the
+// vendor and class default to DefaultManagementVendor and
+// DefaultManagementClass. These defaults are overridden by the WithVendor and
+// WithClass options.
+//
+// No options are required. Note, however, that WithDriverRoot (and WithDevRoot)
+// must be specified if the driver is not installed at / and that the hooks in
+// the generated spec reference /usr/bin/nvidia-cdi-hook unless
+// WithNVIDIACDIHookPath is specified.
+func NewManagementLibrary(opts ...Option) (Interface, error) {
+	opts = append([]Option{
+		WithVendor(DefaultManagementVendor),
+		WithClass(DefaultManagementClass),
+	}, opts...)
+	opts = append(opts, WithMode(ModeManagement))
+
+	return New(opts...)
+}
+
+// GetManagementSpec generates the CDI specification for management
+// containers. The host paths in the spec are transformed from the driver and
+// device roots of the caller to the corresponding target roots. Additional
+// options such as WithManagementEditsOnly can be used to further configure the
+// generated spec.
+func GetManagementSpec(logger logger.Interface, config ManagementSpecConfig, opts ...Option) (spec.Interface, error) {
+	config = config.withDefaults()
+
+	opts = append([]Option{
+		WithLogger(logger),
+		WithMode(ModeManagement),
+		WithDriverRoot(config.DriverRoot),
+		WithDevRoot(config.DevRoot),
+		WithNVIDIACDIHookPath(config.NVIDIACDIHookPath),
+		WithVendor(config.Vendor),
+		WithClass(config.Class),
+	}, opts...)
+
+	cdilib, err := New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CDI library for management containers: %v", err)
+	}
+
+	spec, err := cdilib.GetSpec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CDI spec for management containers: %v", err)
+	}
+
+	if err := config.newTransformer().Transform(spec.Raw()); err != nil {
+		return nil, fmt.Errorf("failed to transform driver root in CDI spec: %v", err)
+	}
+
+	return spec, nil
+}
+
+// GetManagementDeviceSpecs generates a CDI specification for each of the GPUs
+// on the system for use in management containers. Each spec contains a single
+// device that is named by the UUID of the GPU as well as the common edits
+// required to use it. The specs are returned keyed by GPU UUID. As is the case
+// for GetManagementSpec, the host paths in each spec are transformed to the
+// target roots.
+func GetManagementDeviceSpecs(logger logger.Interface, config ManagementSpecConfig, opts ...Option) (map[string]spec.Interface, error) {
+	config = config.withDefaults()
+
+	uuidNamer, err := NewDeviceNamer(DeviceNameStrategyUUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create device namer: %v", err)
+	}
+
+	opts = append([]Option{
+		WithLogger(logger),
+		WithDriverRoot(config.DriverRoot),
+		WithDevRoot(config.DevRoot),
+		WithNVIDIACDIHookPath(config.NVIDIACDIHookPath),
+		WithVendor(config.Vendor),
+		WithClass(config.Class),
+	}, opts...)
+	opts = append(opts,
+		WithMode(ModeNvml),
+		WithDeviceNamers(uuidNamer),
+	)
+
+	cdilib, err := New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CDI library for management containers: %v", err)
+	}
+	w, ok := cdilib.(*wrapper)
+	if !ok {
+		return nil, fmt.Errorf("unexpected CDI library type %T", cdilib)
+	}
+	l, ok := w.Interface.(*nvmllib)
+	if !ok {
+		return nil, fmt.Errorf("unexpected CDI library type %T for management device specs", w.Interface)
+	}
+	if l.managementEditsOnly {
+		return nil, fmt.Errorf("management edits only are not supported for per-device specs")
+	}
+
+	if r := l.nvmllib.Init(); r != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to initialize NVML: %v", r)
+	}
+	defer func() {
+		if r := l.nvmllib.Shutdown(); r != nvml.SUCCESS {
+			l.logger.Warningf("failed to shutdown NVML: %v", r)
+		}
+	}()
+
+	selected, err := selectDeviceIndices(l, config.DeviceIndices)
+	if err != nil {
+		return nil, err
+	}
+
+	commonEdits, err := w.GetCommonEdits()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get common edits for management containers: %v", err)
+	}
+	// The common edits are transformed once and shared by all the specs.
+	common := &specs.Spec{ContainerEdits: *commonEdits.ContainerEdits}
+	if err := config.newTransformer().Transform(common); err != nil {
+		return nil, fmt.Errorf("failed to transform driver root in common edits: %v", err)
+	}
+
+	deviceSpecs := make(map[string]spec.Interface)
+	err = l.devicelib.VisitDevices(func(i int, d device.Device) error {
+		if selected != nil && !selected[i] {
+			return nil
+		}
+		uuid, ret := d.GetUUID()
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get UUID of device %d: %v", i, ret)
+		}
+		specsForDevice, err := l.GetGPUDeviceSpecs(i, d)
+		if err != nil {
+			return fmt.Errorf("failed to get device specs for %v: %w", uuid, err)
+		}
+		s, err := spec.New(
+			spec.WithDeviceSpecs(specsForDevice),
+			spec.WithVendor(w.vendor),
+			spec.WithClass(w.class),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create CDI spec for %v: %w", uuid, err)
+		}
+		if err := config.newTransformer().Transform(s.Raw()); err != nil {
+			return fmt.Errorf("failed to transform driver root in CDI spec for %v: %w", uuid, err)
+		}
+		// The common edits are appended to empty edits so that the specs
+		// do not share the underlying slices.
+		commonForDevice := edits.NewContainerEdits()
+		commonForDevice.Append(&cdi.ContainerEdits{ContainerEdits: &common.ContainerEdits})
+		s.Raw().ContainerEdits = *commonForDevice.ContainerEdits
+		deviceSpecs[uuid] = s
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CDI specs for management containers: %w", err)
+	}
+
+	return deviceSpecs, nil
+}
+
+// selectDeviceIndices validates the specified device indices against the
+// number of GPUs on the system and returns these as a set. If no indices are
+// specified, nil is returned to indicate that all GPUs are selected.
+func selectDeviceIndices(l *nvmllib, indices []int) (map[int]bool, error) {
+	if len(indices) == 0 {
+		return nil, nil
+	}
+	count, ret := l.nvmllib.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		return nil, fmt.Errorf("failed to get device count: %v", ret)
+	}
+	selected := make(map[int]bool)
+	for _, index := range indices {
+		if index < 0 || index >= count {
+			return nil, fmt.Errorf("invalid device index %d: %d devices available", index, count)
+		}
+		selected[index] = true
+	}
+	return selected, nil
+}
+
+// withDefaults returns a copy of the config with unset roots defaulted.
+func (c ManagementSpecConfig) withDefaults() ManagementSpecConfig {
+	if c.DevRoot == "" {
+		c.DevRoot = c.DriverRoot
+	}
+	if c.TargetDriverRoot == "" {
+		c.TargetDriverRoot = c.DriverRoot
+	}
+	if c.TargetDevRoot == "" {
+		c.TargetDevRoot = c.TargetDriverRoot
+	}
+	return c
+}
+
+// newTransformer creates a transformer that transforms the driver and device
+// roots in a spec to the target roots.
+func (c ManagementSpecConfig) newTransformer() transform.Transformer {
+	return transformroot.NewDriverTransformer(
+		transformroot.WithDriverRoot(c.DriverRoot),
+		transformroot.WithTargetDriverRoot(c.TargetDriverRoot),
+		transformroot.WithDevRoot(c.DevRoot),
+		transformroot.WithTargetDevRoot(c.TargetDevRoot),
+	)
+}
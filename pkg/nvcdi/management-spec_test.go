@@ -0,0 +1,270 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/NVIDIA/go-nvml/pkg/nvml/mock"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestGetManagementSpec(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	for _, lib := range []string{"libcuda.so.999.88.77", "libnvidia-ml.so.999.88.77"} {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, lib), nil, 0644))
+	}
+
+	spec, err := GetManagementSpec(
+		logger,
+		ManagementSpecConfig{
+			DriverRoot:        driverRoot,
+			TargetDriverRoot:  "/run/nvidia/driver",
+			NVIDIACDIHookPath: "/usr/bin/nvidia-cdi-hook",
+			Vendor:            "management.nvidia.com",
+			Class:             "gpu",
+		},
+		WithManagementEditsOnly(true),
+	)
+	require.NoError(t, err)
+
+	raw := spec.Raw()
+	require.Equal(t, "management.nvidia.com/gpu", raw.Kind)
+	require.Empty(t, raw.Devices)
+
+	edits := raw.ContainerEdits
+	require.Empty(t, edits.DeviceNodes)
+	require.EqualValues(t,
+		[]*specs.Mount{
+			{
+				HostPath:      "/run/nvidia/driver/usr/lib64/libcuda.so.999.88.77",
+				ContainerPath: "/usr/lib64/libcuda.so.999.88.77",
+				Options:       []string{"ro", "nosuid", "nodev", "bind"},
+			},
+			{
+				HostPath:      "/run/nvidia/driver/usr/lib64/libnvidia-ml.so.999.88.77",
+				ContainerPath: "/usr/lib64/libnvidia-ml.so.999.88.77",
+				Options:       []string{"ro", "nosuid", "nodev", "bind"},
+			},
+		},
+		edits.Mounts,
+	)
+	require.EqualValues(t,
+		[]*specs.Hook{
+			{
+				HookName: "createContainer",
+				Path:     "/usr/bin/nvidia-cdi-hook",
+				Args:     []string{"nvidia-cdi-hook", "update-ldcache", "--folder", "/usr/lib64"},
+			},
+		},
+		edits.Hooks,
+	)
+}
+
+func TestGetManagementDeviceSpecs(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	for _, lib := range []string{"libcuda.so.999.88.77", "libnvidia-ml.so.999.88.77"} {
+		require.NoError(t, os.WriteFile(filepath.Join(libDir, lib), nil, 0644))
+	}
+
+	uuids := []string{"GPU-11111111-1111-1111-1111-111111111111", "GPU-22222222-2222-2222-2222-222222222222"}
+	var devices []nvml.Device
+	for i, uuid := range uuids {
+		i, uuid := i, uuid
+		devices = append(devices, &mock.Device{
+			GetUUIDFunc: func() (string, nvml.Return) {
+				return uuid, nvml.SUCCESS
+			},
+			GetMinorNumberFunc: func() (int, nvml.Return) {
+				return i, nvml.SUCCESS
+			},
+			GetPciInfoFunc: func() (nvml.PciInfo, nvml.Return) {
+				var busID [32]int8
+				for j, b := range []byte(fmt.Sprintf("00000000:%02x:00.0", i+1)) {
+					busID[j] = int8(b)
+				}
+				return nvml.PciInfo{BusId: busID}, nvml.SUCCESS
+			},
+			GetNameFunc: func() (string, nvml.Return) {
+				return "NVIDIA A100-SXM4-40GB", nvml.SUCCESS
+			},
+			IsMigDeviceHandleFunc: func() (bool, nvml.Return) {
+				return false, nvml.SUCCESS
+			},
+		})
+	}
+	nvmllib := &mock.Interface{
+		InitFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		ShutdownFunc: func() nvml.Return {
+			return nvml.SUCCESS
+		},
+		SystemGetDriverVersionFunc: func() (string, nvml.Return) {
+			return "999.88.77", nvml.SUCCESS
+		},
+		DeviceGetCountFunc: func() (int, nvml.Return) {
+			return len(devices), nvml.SUCCESS
+		},
+		DeviceGetHandleByIndexFunc: func(i int) (nvml.Device, nvml.Return) {
+			return devices[i], nvml.SUCCESS
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		deviceIndices []int
+		options       []Option
+		expectedUUIDs []string
+		expectedError bool
+	}{
+		{
+			description:   "all devices",
+			expectedUUIDs: uuids,
+		},
+		{
+			description:   "selected device",
+			deviceIndices: []int{1},
+			expectedUUIDs: uuids[1:],
+		},
+		{
+			description:   "invalid device index",
+			deviceIndices: []int{0, 2},
+			expectedError: true,
+		},
+		{
+			description:   "edits only is not supported",
+			options:       []Option{WithManagementEditsOnly(true)},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			deviceSpecs, err := GetManagementDeviceSpecs(
+				logger,
+				ManagementSpecConfig{
+					DriverRoot:        driverRoot,
+					TargetDriverRoot:  "/run/nvidia/driver",
+					NVIDIACDIHookPath: "/usr/bin/nvidia-cdi-hook",
+					Vendor:            "management.nvidia.com",
+					Class:             "gpu",
+					DeviceIndices:     tc.deviceIndices,
+				},
+				append(tc.options, WithNvmlLib(nvmllib))...,
+			)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, deviceSpecs, len(tc.expectedUUIDs))
+
+			for _, uuid := range tc.expectedUUIDs {
+				require.Contains(t, deviceSpecs, uuid)
+				raw := deviceSpecs[uuid].Raw()
+				require.Equal(t, "management.nvidia.com/gpu", raw.Kind)
+				require.Len(t, raw.Devices, 1)
+				require.Equal(t, uuid, raw.Devices[0].Name)
+				require.Contains(t, raw.ContainerEdits.Env, "NVIDIA_VISIBLE_DEVICES=void")
+				require.Contains(t, raw.ContainerEdits.Mounts,
+					&specs.Mount{
+						HostPath:      "/run/nvidia/driver/usr/lib64/libcuda.so.999.88.77",
+						ContainerPath: "/usr/lib64/libcuda.so.999.88.77",
+						Options:       []string{"ro", "nosuid", "nodev", "bind"},
+					},
+				)
+			}
+		})
+	}
+}
+
+func TestNewManagementLibrary(t *testing.T) {
+	logger, _ := testlog.NewNullLogger()
+
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), nil, 0644))
+
+	testCases := []struct {
+		description  string
+		options      []Option
+		expectedKind string
+		expectedHook string
+	}{
+		{
+			description:  "defaults are applied",
+			expectedKind: "management.nvidia.com/gpu",
+			expectedHook: "/usr/bin/nvidia-cdi-hook",
+		},
+		{
+			description: "overrides take effect",
+			options: []Option{
+				WithVendor("example.com"),
+				WithClass("mgmt"),
+				WithNVIDIACDIHookPath("/opt/bin/nvidia-cdi-hook"),
+			},
+			expectedKind: "example.com/mgmt",
+			expectedHook: "/opt/bin/nvidia-cdi-hook",
+		},
+		{
+			description:  "mode cannot be overridden",
+			options:      []Option{WithMode(ModeNvml)},
+			expectedKind: "management.nvidia.com/gpu",
+			expectedHook: "/usr/bin/nvidia-cdi-hook",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := append([]Option{
+				WithLogger(logger),
+				WithDriverRoot(driverRoot),
+				WithManagementEditsOnly(true),
+			}, tc.options...)
+
+			lib, err := NewManagementLibrary(opts...)
+			require.NoError(t, err)
+			require.IsType(t, &managementlib{}, lib.(*wrapper).Interface)
+
+			spec, err := lib.GetSpec()
+			require.NoError(t, err)
+
+			raw := spec.Raw()
+			require.Equal(t, tc.expectedKind, raw.Kind)
+			require.Empty(t, raw.Devices)
+			require.NotEmpty(t, raw.ContainerEdits.Mounts)
+			require.NotEmpty(t, raw.ContainerEdits.Hooks)
+			require.Equal(t, tc.expectedHook, raw.ContainerEdits.Hooks[0].Path)
+		})
+	}
+}
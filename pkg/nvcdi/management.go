@@ -26,8 +26,6 @@ import (
 	"tags.cncf.io/container-device-interface/specs-go"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/discover"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
-	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/cuda"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
 )
 
@@ -37,13 +35,18 @@ var _ Interface = (*managementlib)(nil)
 
 // GetAllDeviceSpecs returns all device specs for use in managemnt containers.
 // A single device with the name `all` is returned.
+// If only edits are to be included, no devices are returned.
 func (m *managementlib) GetAllDeviceSpecs() ([]specs.Device, error) {
+	if m.managementEditsOnly {
+		return nil, nil
+	}
+
 	devices, err := m.newManagementDeviceDiscoverer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create device discoverer: %v", err)
 	}
 
-	edits, err := edits.FromDiscoverer(devices)
+	edits, err := (*nvcdilib)(m).editsFromDiscoverer(devices)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create edits from discoverer: %v", err)
 	}
@@ -66,12 +69,16 @@ func (m *managementlib) GetCommonEdits() (*cdi.ContainerEdits, error) {
 		return nil, fmt.Errorf("failed to get CUDA version: %v", err)
 	}
 
-	driver, err := newDriverVersionDiscoverer(m.logger, m.driver, m.nvidiaCDIHookPath, m.ldconfigPath, version)
+	driver, err := newDriverVersionDiscoverer(m.logger, m.driver, m.nvidiaCDIHookPath, m.ldconfigPath, version, (*nvcdilib)(m).ldcacheUpdateHookOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create driver library discoverer: %v", err)
 	}
 
-	edits, err := edits.FromDiscoverer(driver)
+	edits, err := (*nvcdilib)(m).editsFromDiscoverer(
+		(*nvcdilib)(m).withVerifyMountsHook(
+			discover.NewLibrarySizeBudget(m.logger, driver, m.librarySizeBudget, m.librarySizeBudgetStrict),
+		),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create edits from discoverer: %v", err)
 	}
@@ -86,17 +93,11 @@ func (m *managementlib) getCudaVersion() (string, error) {
 		return version, nil
 	}
 
-	libCudaPaths, err := cuda.New(
-		m.driver.Libraries(),
-	).Locate(".*.*")
+	version, err = m.driver.Version()
 	if err != nil {
-		return "", fmt.Errorf("failed to locate libcuda.so: %v", err)
+		return "", fmt.Errorf("failed to determine driver version: %v", err)
 	}
 
-	libCudaPath := libCudaPaths[0]
-
-	version = strings.TrimPrefix(filepath.Base(libCudaPath), "libcuda.so.")
-
 	return version, nil
 }
 
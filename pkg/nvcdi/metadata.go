@@ -0,0 +1,54 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package nvcdi
+
+import (
+	"time"
+
+	toolkitinfo "github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+)
+
+const (
+	// GeneratorVersionAnnotation records the version of the toolkit used to generate a spec.
+	GeneratorVersionAnnotation = "nvidia.com/cdi-generator.version"
+	// GeneratorTimestampAnnotation records the (UTC) time at which a spec was generated.
+	GeneratorTimestampAnnotation = "nvidia.com/cdi-generator.timestamp"
+	// GeneratorModeAnnotation records the mode used to generate a spec.
+	GeneratorModeAnnotation = "nvidia.com/cdi-generator.mode"
+	// GeneratorDriverVersionAnnotation records the driver version detected when generating a spec.
+	GeneratorDriverVersionAnnotation = "nvidia.com/cdi-generator.driver-version"
+)
+
+// getGenerationAnnotations returns the spec-level annotations describing how
+// a spec was generated. The driver version is only included if it can be
+// determined.
+func (l *nvcdilib) getGenerationAnnotations(mode string) map[string]string {
+	annotations := map[string]string{
+		GeneratorVersionAnnotation:   toolkitinfo.GetVersionParts()[0],
+		GeneratorTimestampAnnotation: time.Now().UTC().Format(time.RFC3339),
+		GeneratorModeAnnotation:      mode,
+	}
+
+	driverVersion, err := l.getCudaVersion()
+	if err != nil {
+		l.logger.Debugf("Omitting driver version annotation: %v", err)
+		return annotations
+	}
+	annotations[GeneratorDriverVersionAnnotation] = driverVersion
+
+	return annotations
+}
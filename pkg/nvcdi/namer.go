@@ -47,20 +47,54 @@ const (
 	DeviceNameStrategyUUID = "uuid"
 )
 
+// DefaultDeviceNameSeparator is the separator used between the GPU and MIG
+// indices in the names of MIG devices.
+const DefaultDeviceNameSeparator = ":"
+
 type deviceNameIndex struct {
 	gpuPrefix string
 	migPrefix string
+	separator string
 }
 type deviceNameUUID struct{}
 
+type deviceNamerOptions struct {
+	separator string
+}
+
+// DeviceNamerOption defines a function for passing options to NewDeviceNamer.
+type DeviceNamerOption func(*deviceNamerOptions)
+
+// WithDeviceNameSeparator sets the separator used between the GPU and MIG
+// indices for index-based naming strategies. The separator must be one of the
+// characters allowed in CDI device names: ':', '_', '-', or '.'.
+func WithDeviceNameSeparator(separator string) DeviceNamerOption {
+	return func(o *deviceNamerOptions) {
+		o.separator = separator
+	}
+}
+
 // NewDeviceNamer creates a Device Namer based on the supplied strategy.
 // This namer can be used to construct the names for MIG and GPU devices when generating the CDI spec.
-func NewDeviceNamer(strategy string) (DeviceNamer, error) {
+func NewDeviceNamer(strategy string, opts ...DeviceNamerOption) (DeviceNamer, error) {
+	o := &deviceNamerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.separator == "" {
+		o.separator = DefaultDeviceNameSeparator
+	}
+	switch o.separator {
+	case ":", "_", "-", ".":
+	default:
+		return nil, fmt.Errorf("invalid device name separator: %q", o.separator)
+	}
+
 	switch strategy {
 	case DeviceNameStrategyIndex:
-		return deviceNameIndex{}, nil
+		return deviceNameIndex{separator: o.separator}, nil
 	case DeviceNameStrategyTypeIndex:
-		return deviceNameIndex{gpuPrefix: "gpu", migPrefix: "mig"}, nil
+		return deviceNameIndex{gpuPrefix: "gpu", migPrefix: "mig", separator: o.separator}, nil
 	case DeviceNameStrategyUUID:
 		return deviceNameUUID{}, nil
 	}
@@ -75,7 +109,11 @@ func (s deviceNameIndex) GetDeviceName(i int, _ UUIDer) (string, error) {
 
 // GetMigDeviceName returns the name for the specified device based on the naming strategy
 func (s deviceNameIndex) GetMigDeviceName(i int, _ UUIDer, j int, _ UUIDer) (string, error) {
-	return fmt.Sprintf("%s%d:%d", s.migPrefix, i, j), nil
+	separator := s.separator
+	if separator == "" {
+		separator = DefaultDeviceNameSeparator
+	}
+	return fmt.Sprintf("%s%d%s%d", s.migPrefix, i, separator, j), nil
 }
 
 // GetDeviceName returns the name for the specified device based on the naming strategy
@@ -21,6 +21,7 @@ import (
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/pkg/parser"
 )
 
 func TestConvert(t *testing.T) {
@@ -65,3 +66,53 @@ func TestConvert(t *testing.T) {
 		})
 	}
 }
+
+func TestDeviceNameSeparator(t *testing.T) {
+	testCases := []struct {
+		description   string
+		strategy      string
+		options       []DeviceNamerOption
+		expectedError bool
+		expectedName  string
+	}{
+		{
+			description:  "default separator is a colon",
+			strategy:     DeviceNameStrategyIndex,
+			expectedName: "1:0",
+		},
+		{
+			description:  "underscore separator",
+			strategy:     DeviceNameStrategyIndex,
+			options:      []DeviceNamerOption{WithDeviceNameSeparator("_")},
+			expectedName: "1_0",
+		},
+		{
+			description:  "dash separator with type-index",
+			strategy:     DeviceNameStrategyTypeIndex,
+			options:      []DeviceNamerOption{WithDeviceNameSeparator("-")},
+			expectedName: "mig1-0",
+		},
+		{
+			description:   "invalid separator is an error",
+			strategy:      DeviceNameStrategyIndex,
+			options:       []DeviceNamerOption{WithDeviceNameSeparator("/")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			namer, err := NewDeviceNamer(tc.strategy, tc.options...)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			name, err := namer.GetMigDeviceName(1, nil, 0, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedName, name)
+			require.NoError(t, parser.ValidateDeviceName(name))
+		})
+	}
+}
@@ -19,9 +19,12 @@ package nvcdi
 import (
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/edits"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform"
 )
 
@@ -42,6 +45,22 @@ func WithInfoLib(infolib info.Interface) Option {
 	}
 }
 
+// WithPCILib sets the PCI library used to enumerate devices in vfio mode.
+func WithPCILib(nvpcilib nvpci.Interface) Option {
+	return func(l *nvcdilib) {
+		l.nvpcilib = nvpcilib
+	}
+}
+
+// WithRequireVfioDevices sets whether generating device specs in vfio mode
+// fails if no GPUs are bound to the vfio-pci driver. By default an empty set of
+// device specs is returned.
+func WithRequireVfioDevices(require bool) Option {
+	return func(l *nvcdilib) {
+		l.requireVfioDevices = require
+	}
+}
+
 // WithDeviceNamers sets the device namer for the library
 func WithDeviceNamers(namers ...DeviceNamer) Option {
 	return func(l *nvcdilib) {
@@ -127,6 +146,14 @@ func WithMergedDeviceOptions(opts ...transform.MergedDeviceOption) Option {
 	}
 }
 
+// WithSpecOptions sets additional options, such as the output format, that are
+// applied when constructing a spec in a call to GetSpec.
+func WithSpecOptions(opts ...spec.Option) Option {
+	return func(o *nvcdilib) {
+		o.specOptions = opts
+	}
+}
+
 // WithCSVFiles sets the CSV files for the library
 func WithCSVFiles(csvFiles []string) Option {
 	return func(o *nvcdilib) {
@@ -155,3 +182,187 @@ func WithLibrarySearchPaths(paths []string) Option {
 		o.librarySearchPaths = paths
 	}
 }
+
+// WithVersionLibraryPattern sets the pattern used to locate the library from
+// which the driver version is determined and relative to which the driver
+// libraries are located. This allows, for example, the libcuda.so from a CUDA
+// forward compatibility package to be used. The default is libcuda.so.*.*.
+func WithVersionLibraryPattern(pattern string) Option {
+	return func(o *nvcdilib) {
+		o.versionLibraryPattern = pattern
+	}
+}
+
+// WithSonameFallback sets whether driver libraries that are not found by
+// filename are located by matching the SONAME in their ELF headers instead.
+func WithSonameFallback(fallback bool) Option {
+	return func(o *nvcdilib) {
+		o.sonameFallback = fallback
+	}
+}
+
+// WithLdsoconfFolders sets whether the library folders declared in the
+// /etc/ld.so.conf.d files of the driver root are included in the ldcache
+// update for the driver libraries. Only folders that contain one of the driver
+// libraries are included.
+func WithLdsoconfFolders(include bool) Option {
+	return func(o *nvcdilib) {
+		o.ldsoconfFolders = include
+	}
+}
+
+// WithLDCacheUpdateMode sets how the driver libraries are made available to
+// the dynamic linker in the container. This is one of ldconfig (the default)
+// or env. In env mode, LD_LIBRARY_PATH is set to the library folders instead
+// of running ldconfig in the container.
+func WithLDCacheUpdateMode(mode string) Option {
+	return func(o *nvcdilib) {
+		o.ldcacheUpdateMode = mode
+	}
+}
+
+// WithLDCacheUpdateHookLifecycle sets the lifecycle at which the ldcache update
+// hook for the driver libraries is run. This must be createContainer (the
+// default) or startContainer.
+func WithLDCacheUpdateHookLifecycle(lifecycle string) Option {
+	return func(o *nvcdilib) {
+		o.ldcacheUpdateHookLifecycle = lifecycle
+	}
+}
+
+// WithLDCacheIncludedLibraries sets the patterns of library names that are
+// considered when determining the folders for the ldcache update of the driver
+// libraries. If no patterns are specified, all libraries are considered.
+func WithLDCacheIncludedLibraries(patterns ...string) Option {
+	return func(o *nvcdilib) {
+		o.ldcacheIncludedLibraries = patterns
+	}
+}
+
+// WithLDCacheExcludedLibraries sets the patterns of library names that are not
+// considered when determining the folders for the ldcache update of the driver
+// libraries.
+func WithLDCacheExcludedLibraries(patterns ...string) Option {
+	return func(o *nvcdilib) {
+		o.ldcacheExcludedLibraries = patterns
+	}
+}
+
+// WithValidateMigDevices sets whether the edits for MIG devices include a hook
+// that checks that the MIG device still exists when a container is created.
+// This allows a container that references a MIG device that was destroyed
+// after the CDI specification was generated to fail early.
+func WithValidateMigDevices(validate bool) Option {
+	return func(o *nvcdilib) {
+		o.validateMigDevices = validate
+	}
+}
+
+// WithVerifyMounts sets whether the common edits include a hook that verifies
+// that the mounted driver files exist in the container once the edits have
+// been applied. This allows a partial injection to be detected when the
+// container is created.
+func WithVerifyMounts(verify bool) Option {
+	return func(o *nvcdilib) {
+		o.verifyMounts = verify
+	}
+}
+
+// WithMIGCaps sets whether the edits for MIG devices include mounts for the
+// procfs capability files of the associated GPU and compute instances.
+func WithMIGCaps(migCaps bool) Option {
+	return func(o *nvcdilib) {
+		o.migCaps = migCaps
+	}
+}
+
+// WithGenerationMetadata sets whether the generated spec includes annotations
+// recording the toolkit version, generation time, mode, and driver version.
+// Since spec-level annotations were added in CDI v0.6.0, enabling this raises
+// the minimum required version of the generated spec.
+func WithGenerationMetadata(enabled bool) Option {
+	return func(o *nvcdilib) {
+		o.generationMetadata = enabled
+	}
+}
+
+// WithManagementEditsOnly sets whether a management spec includes only the
+// driver edits (e.g. mounts and hooks) as common edits and no devices. This is
+// useful for containers that manage the driver but do not require access to
+// devices.
+// This option only applies to ModeManagement.
+func WithManagementEditsOnly(editsOnly bool) Option {
+	return func(o *nvcdilib) {
+		o.managementEditsOnly = editsOnly
+	}
+}
+
+// WithLibrarySizeBudget sets a budget (in bytes) for the total size of the
+// driver libraries injected into a container. If the total exceeds the budget
+// a warning is logged, or an error is raised if strict is set. A budget that
+// is not positive disables the check.
+func WithLibrarySizeBudget(budget int64, strict bool) Option {
+	return func(o *nvcdilib) {
+		o.librarySizeBudget = budget
+		o.librarySizeBudgetStrict = strict
+	}
+}
+
+// WithPopulateDeviceNodeInfo sets whether the type, major, and minor numbers
+// of device nodes are included in the generated spec. By default these are
+// left empty and are determined by the runtime when the spec is applied.
+func WithPopulateDeviceNodeInfo(populate bool) Option {
+	return func(o *nvcdilib) {
+		o.editsOptions = append(o.editsOptions, edits.WithPopulateDeviceNodeInfo(populate))
+	}
+}
+
+// WithTolerateDiscoveryErrors sets whether errors discovering the devices,
+// mounts, or hooks for a set of container edits are tolerated. If set, the
+// failures are logged as warnings and the edits are constructed from the
+// discovery steps that succeeded. By default an error is returned.
+func WithTolerateDiscoveryErrors(tolerate bool) Option {
+	return func(o *nvcdilib) {
+		o.editsOptions = append(o.editsOptions, edits.WithTolerateErrors(tolerate))
+	}
+}
+
+// WithSimplifyEdits sets whether redundant entries are removed from the
+// generated container edits and the remaining entries sorted. This includes
+// mounts that are shadowed by a later mount to the same container path.
+func WithSimplifyEdits(simplify bool) Option {
+	return func(o *nvcdilib) {
+		o.editsOptions = append(o.editsOptions, edits.WithSimplify(simplify))
+	}
+}
+
+// WithReadOnlyLibraries sets whether the mounts for shared libraries in the
+// generated container edits are forced to be read-only regardless of the
+// options set when they were discovered.
+func WithReadOnlyLibraries(readOnly bool) Option {
+	return func(o *nvcdilib) {
+		if !readOnly {
+			return
+		}
+		o.editsOptions = append(o.editsOptions, edits.WithMountOptionsTransform(edits.ReadOnlyLibraries))
+	}
+}
+
+// WithVerifyHookPaths sets whether the paths of the hooks in the generated
+// container edits are required to exist and be executable. Since a spec may
+// be generated on a different host to where it is used, this is disabled by
+// default.
+func WithVerifyHookPaths(verify bool) Option {
+	return func(o *nvcdilib) {
+		o.editsOptions = append(o.editsOptions, edits.WithVerifyHookPaths(verify))
+	}
+}
+
+// WithExcludedHooks sets the names of hooks that are not included in the
+// generated container edits. A name matches either the subcommand of a hook
+// (e.g. update-ldcache) or the name of the hook executable.
+func WithExcludedHooks(names ...string) Option {
+	return func(o *nvcdilib) {
+		o.editsOptions = append(o.editsOptions, edits.WithExcludedHooks(names...))
+	}
+}
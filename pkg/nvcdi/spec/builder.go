@@ -35,17 +35,21 @@ type builder struct {
 	deviceSpecs []specs.Device
 	edits       specs.ContainerEdits
 	format      string
+	annotations map[string]string
 
 	mergedDeviceOptions []transform.MergedDeviceOption
 	noSimplify          bool
 	permissions         os.FileMode
+	atomicWrite         bool
 
 	transformOnSave transform.Transformer
 }
 
 // newBuilder creates a new spec builder with the supplied options
 func newBuilder(opts ...Option) *builder {
-	s := &builder{}
+	s := &builder{
+		atomicWrite: true,
+	}
 	for _, opt := range opts {
 		opt(s)
 	}
@@ -93,6 +97,14 @@ func (o *builder) Build() (*spec, error) {
 			ContainerEdits: o.edits,
 		}
 	}
+	if len(o.annotations) > 0 {
+		if raw.Annotations == nil {
+			raw.Annotations = make(map[string]string)
+		}
+		for k, v := range o.annotations {
+			raw.Annotations[k] = v
+		}
+	}
 	if raw.Version == "" {
 		raw.Version = o.version
 	}
@@ -118,6 +130,7 @@ func (o *builder) Build() (*spec, error) {
 		Spec:            raw,
 		format:          o.format,
 		permissions:     o.permissions,
+		atomicWrite:     o.atomicWrite,
 		transformOnSave: o.transformOnSave,
 	}
 	return &s, nil
@@ -195,3 +208,21 @@ func WithMergedDeviceOptions(opts ...transform.MergedDeviceOption) Option {
 		o.mergedDeviceOptions = opts
 	}
 }
+
+// WithAnnotations sets the spec-level annotations for the spec builder.
+// These are added to any annotations already present in a raw spec.
+func WithAnnotations(annotations map[string]string) Option {
+	return func(o *builder) {
+		o.annotations = annotations
+	}
+}
+
+// WithAtomicWrite sets whether the spec file is written atomically. If set
+// (the default), the spec is written to a temporary location in the same
+// directory and renamed into place once its contents and permissions are final
+// so that readers never observe a partially written spec.
+func WithAtomicWrite(atomicWrite bool) Option {
+	return func(o *builder) {
+		o.atomicWrite = atomicWrite
+	}
+}
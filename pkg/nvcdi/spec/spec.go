@@ -32,6 +32,7 @@ type spec struct {
 	*specs.Spec
 	format          string
 	permissions     os.FileMode
+	atomicWrite     bool
 	transformOnSave transform.Transformer
 }
 
@@ -55,6 +56,37 @@ func (s *spec) Save(path string) error {
 		return fmt.Errorf("failed to normalize path: %w", err)
 	}
 
+	if !s.atomicWrite {
+		return s.write(path)
+	}
+
+	// In order to write the spec atomically, we write it to a temporary
+	// directory alongside the target path and then rename it into place. This
+	// ensures that the file is only visible once its permissions have also
+	// been set.
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create spec directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(filepath.Dir(path), ".spec-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpPath := filepath.Join(tmpDir, filepath.Base(path))
+	if err := s.write(tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename spec file: %w", err)
+	}
+
+	return nil
+}
+
+// write writes the spec to the specified path and sets the configured
+// permissions.
+func (s *spec) write(path string) error {
 	specDir := filepath.Dir(path)
 	cache, _ := cdi.NewCache(
 		cdi.WithAutoRefresh(false),
@@ -18,6 +18,8 @@ package spec
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -155,6 +157,22 @@ containerEdits:
   - FOO=bar
 devices: null
 kind: nvidia.com/gpu
+`,
+		},
+		{
+			description: "annotations use 0.6.0 version",
+			options: []Option{
+				WithAnnotations(map[string]string{
+					"nvidia.com/cdi-generator.mode": "nvml",
+				}),
+			},
+			expectedSpec: `---
+annotations:
+  nvidia.com/cdi-generator.mode: nvml
+cdiVersion: 0.6.0
+containerEdits: {}
+devices: null
+kind: nvidia.com/gpu
 `,
 		},
 	}
@@ -179,3 +197,65 @@ kind: nvidia.com/gpu
 		})
 	}
 }
+
+func TestSaveAtomic(t *testing.T) {
+	testCases := []struct {
+		description   string
+		options       []Option
+		expectedError bool
+	}{
+		{
+			description: "spec is renamed into place",
+		},
+		{
+			description: "spec is written directly",
+			options:     []Option{WithAtomicWrite(false)},
+		},
+		{
+			description:   "invalid spec leaves no files",
+			options:       []Option{WithVendor("-invalid")},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			specDir := t.TempDir()
+			path := filepath.Join(specDir, "spec.yaml")
+
+			opts := append([]Option{
+				WithEdits(specs.ContainerEdits{Env: []string{"FOO=bar"}}),
+				WithPermissions(0640),
+			}, tc.options...)
+			s, err := New(opts...)
+			require.NoError(t, err)
+
+			err = s.Save(path)
+			if tc.expectedError {
+				require.Error(t, err)
+				entries, err := os.ReadDir(specDir)
+				require.NoError(t, err)
+				require.Empty(t, entries)
+				return
+			}
+			require.NoError(t, err)
+
+			// Only the spec file itself must be present.
+			entries, err := os.ReadDir(specDir)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			require.Equal(t, "spec.yaml", entries[0].Name())
+
+			original, err := os.Stat(path)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0640), original.Mode().Perm())
+
+			// Saving the spec again must replace the file.
+			require.NoError(t, s.Save(path))
+			updated, err := os.Stat(path)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0640), updated.Mode().Perm())
+			require.False(t, os.SameFile(original, updated))
+		})
+	}
+}
@@ -63,9 +63,28 @@ func (t containerRootTransformer) applyToEdits(edits *specs.ContainerEdits) erro
 		edits.Mounts[i] = t.transformMount(mount)
 	}
 
+	for i, env := range edits.Env {
+		edits.Env[i] = t.transformEnv(env)
+	}
+
 	return nil
 }
 
+// transformEnv transforms the container paths in the LD_LIBRARY_PATH
+// environment variable. Other environment variables are not modified.
+func (t containerRootTransformer) transformEnv(env string) string {
+	name, value, found := strings.Cut(env, "=")
+	if !found || name != "LD_LIBRARY_PATH" {
+		return env
+	}
+
+	var paths []string
+	for _, path := range strings.Split(value, ":") {
+		paths = append(paths, t.transformPath(path))
+	}
+	return name + "=" + strings.Join(paths, ":")
+}
+
 func (t containerRootTransformer) transformDeviceNode(dn *specs.DeviceNode) *specs.DeviceNode {
 	dn.Path = t.transformPath(dn.Path)
 
@@ -68,6 +68,27 @@ func TestContainerRootTransformer(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "LD_LIBRARY_PATH",
+			root:        "/root",
+			targetRoot:  "/target-root",
+			spec: &specs.Spec{
+				ContainerEdits: specs.ContainerEdits{
+					Env: []string{
+						"LD_LIBRARY_PATH=/root/lib:/different-root/lib",
+						"OTHER=/root/lib",
+					},
+				},
+			},
+			expectedSpec: &specs.Spec{
+				ContainerEdits: specs.ContainerEdits{
+					Env: []string{
+						"LD_LIBRARY_PATH=/target-root/lib:/different-root/lib",
+						"OTHER=/root/lib",
+					},
+				},
+			},
+		},
 		{
 			description: "mounts",
 			root:        "/root",
@@ -0,0 +1,141 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pelletier/go-toml"
+	log "github.com/sirupsen/logrus"
+)
+
+// configBackup records the state of a config file before it was overwritten so
+// that the original state can be restored.
+type configBackup struct {
+	path     string
+	existed  bool
+	contents []byte
+	mode     os.FileMode
+}
+
+// writeAdditionalConfigs writes the specified config to each of the specified
+// paths. Each file is written atomically by writing to a temporary
+// file in the target directory and renaming it. If a write fails and errors are
+// not ignored, the files that have already been written are restored to their
+// original state and an error is returned.
+func writeAdditionalConfigs(cfg *toml.Tree, paths []string, ignoreErrors bool) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	var contents bytes.Buffer
+	if _, err := cfg.WriteTo(&contents); err != nil {
+		return fmt.Errorf("error rendering config: %w", err)
+	}
+
+	var written []configBackup
+	for _, path := range paths {
+		backup, err := backupConfig(path)
+		if err == nil {
+			err = writeConfigAtomically(path, contents.Bytes())
+		}
+		if err == nil {
+			log.Infof("Installed NVIDIA container toolkit config to additional path '%v'", path)
+			written = append(written, backup)
+			continue
+		}
+		err = fmt.Errorf("error writing config to %v: %w", path, err)
+		if ignoreErrors {
+			log.Errorf("Ignoring error: %v", err)
+			continue
+		}
+		if rollbackErr := rollbackConfigs(written); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// backupConfig records the current state of the config at the specified path.
+func backupConfig(path string) (configBackup, error) {
+	backup := configBackup{path: path}
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return backup, nil
+	}
+	if err != nil {
+		return backup, err
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return backup, err
+	}
+	backup.existed = true
+	backup.contents = contents
+	backup.mode = info.Mode().Perm()
+	return backup, nil
+}
+
+// rollbackConfigs restores the specified configs to their recorded state.
+// Configs that did not exist are removed.
+func rollbackConfigs(backups []configBackup) error {
+	var errs []error
+	for i := len(backups) - 1; i >= 0; i-- {
+		backup := backups[i]
+		log.Infof("Rolling back NVIDIA container toolkit config at '%v'", backup.path)
+		if !backup.existed {
+			if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("failed to remove %v: %w", backup.path, err))
+			}
+			continue
+		}
+		if err := os.WriteFile(backup.path, backup.contents, backup.mode); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %v: %w", backup.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeConfigAtomically writes the specified contents to a temporary file in
+// the directory of the target path and renames it to the target path.
+func writeConfigAtomically(path string, contents []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set permissions on temporary file: %w", err)
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
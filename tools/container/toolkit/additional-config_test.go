@@ -0,0 +1,118 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteAdditionalConfigs(t *testing.T) {
+	cfg, err := toml.Load(`[nvidia-container-cli]
+root = "/run/nvidia/driver"
+`)
+	require.NoError(t, err)
+	expectedContents, err := cfg.ToTomlString()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		description      string
+		existing         string
+		unwritableSecond bool
+		ignoreErrors     bool
+		expectedError    bool
+		expectedFirst    string
+	}{
+		{
+			description:   "config is written to all paths",
+			expectedFirst: expectedContents,
+		},
+		{
+			description:   "existing config is overwritten",
+			existing:      "original",
+			expectedFirst: expectedContents,
+		},
+		{
+			description:      "failed write removes new configs",
+			unwritableSecond: true,
+			expectedError:    true,
+		},
+		{
+			description:      "failed write restores existing configs",
+			existing:         "original",
+			unwritableSecond: true,
+			expectedError:    true,
+			expectedFirst:    "original",
+		},
+		{
+			description:      "failed write is ignored",
+			unwritableSecond: true,
+			ignoreErrors:     true,
+			expectedFirst:    expectedContents,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			testDir := t.TempDir()
+			first := filepath.Join(testDir, "containerd", "config.toml")
+			second := filepath.Join(testDir, "docker", "config.toml")
+			if tc.existing != "" {
+				require.NoError(t, os.MkdirAll(filepath.Dir(first), 0755))
+				require.NoError(t, os.WriteFile(first, []byte(tc.existing), 0644))
+			}
+			if tc.unwritableSecond {
+				// The parent of the second path is a regular file so that the
+				// write fails even when running as root.
+				require.NoError(t, os.WriteFile(filepath.Dir(second), nil, 0644))
+			}
+
+			err := writeAdditionalConfigs(cfg, []string{first, second}, tc.ignoreErrors)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.expectedFirst == "" {
+				require.NoFileExists(t, first)
+			} else {
+				contents, err := os.ReadFile(first)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedFirst, string(contents))
+			}
+
+			if !tc.unwritableSecond {
+				contents, err := os.ReadFile(second)
+				require.NoError(t, err)
+				require.Equal(t, expectedContents, string(contents))
+			}
+
+			// No temporary files are left behind.
+			entries, err := os.ReadDir(filepath.Dir(first))
+			if err == nil {
+				for _, entry := range entries {
+					require.Equal(t, "config.toml", entry.Name())
+				}
+			}
+		})
+	}
+}
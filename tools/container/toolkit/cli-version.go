@@ -0,0 +1,105 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/info"
+)
+
+const containerCLIVersionTimeout = 10 * time.Second
+
+// checkContainerCLIVersion checks whether the version of the specified
+// nvidia-container-cli is compatible with the version of the toolkit being
+// installed. A warning is logged if this is not the case or if the version
+// could not be determined.
+func checkContainerCLIVersion(nvidiaContainerCliPath string) {
+	toolkitVersion := info.GetVersionParts()[0]
+
+	cliVersion, err := getContainerCLIVersion(nvidiaContainerCliPath, containerCLIVersionTimeout)
+	if err != nil {
+		log.Warningf("Could not determine the version of %v: %v", nvidiaContainerCliPath, err)
+		return
+	}
+
+	if !isCompatibleContainerCLIVersion(toolkitVersion, cliVersion) {
+		log.Warningf("The version of nvidia-container-cli (%v) may be incompatible with the NVIDIA Container Toolkit (%v)", cliVersion, toolkitVersion)
+		return
+	}
+	log.Infof("Using nvidia-container-cli version %v", cliVersion)
+}
+
+// getContainerCLIVersion runs nvidia-container-cli --version and returns the
+// reported cli-version.
+func getContainerCLIVersion(nvidiaContainerCliPath string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, nvidiaContainerCliPath, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %v --version: %v", nvidiaContainerCliPath, err)
+	}
+	return parseContainerCLIVersion(string(output))
+}
+
+// parseContainerCLIVersion extracts the cli-version from the output of
+// nvidia-container-cli --version.
+func parseContainerCLIVersion(output string) (string, error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(key) != "cli-version" {
+			continue
+		}
+		if version := strings.TrimSpace(value); version != "" {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("cli-version not found in output")
+}
+
+// isCompatibleContainerCLIVersion checks whether the nvidia-container-cli and
+// toolkit versions are compatible. Since these components are released
+// together, their major and minor versions are expected to match. If either
+// version cannot be parsed, they are assumed to be compatible.
+func isCompatibleContainerCLIVersion(toolkitVersion string, cliVersion string) bool {
+	toolkit := semver.MajorMinor(toSemver(toolkitVersion))
+	cli := semver.MajorMinor(toSemver(cliVersion))
+	if toolkit == "" || cli == "" {
+		return true
+	}
+	return toolkit == cli
+}
+
+// toSemver converts a version string to the form expected by the semver
+// package. Debian-style prerelease separators (~) are also handled.
+func toSemver(version string) string {
+	version = strings.ReplaceAll(version, "~", "-")
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
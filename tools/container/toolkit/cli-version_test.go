@@ -0,0 +1,115 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContainerCLIVersion(t *testing.T) {
+	testCases := []struct {
+		description     string
+		output          string
+		expectedVersion string
+		expectedError   bool
+	}{
+		{
+			description: "version is extracted",
+			output: `cli-version: 1.16.0
+lib-version: 1.16.0
+build date: 2024-07-15T13:41+00:00
+`,
+			expectedVersion: "1.16.0",
+		},
+		{
+			description:   "missing version is an error",
+			output:        "lib-version: 1.16.0\n",
+			expectedError: true,
+		},
+		{
+			description:   "empty version is an error",
+			output:        "cli-version:\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			version, err := parseContainerCLIVersion(tc.output)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestIsCompatibleContainerCLIVersion(t *testing.T) {
+	testCases := []struct {
+		description    string
+		toolkitVersion string
+		cliVersion     string
+		expected       bool
+	}{
+		{
+			description:    "matching versions are compatible",
+			toolkitVersion: "1.16.0",
+			cliVersion:     "1.16.0",
+			expected:       true,
+		},
+		{
+			description:    "patch versions may differ",
+			toolkitVersion: "1.16.1",
+			cliVersion:     "1.16.0",
+			expected:       true,
+		},
+		{
+			description:    "prerelease versions are compatible",
+			toolkitVersion: "1.16.0-rc.1",
+			cliVersion:     "1.16.0~rc.1",
+			expected:       true,
+		},
+		{
+			description:    "prerelease minor version mismatch is incompatible",
+			toolkitVersion: "1.16.0-rc.1",
+			cliVersion:     "1.15.0~rc.1",
+			expected:       false,
+		},
+		{
+			description:    "minor version mismatch is incompatible",
+			toolkitVersion: "1.16.0",
+			cliVersion:     "1.14.6",
+			expected:       false,
+		},
+		{
+			description:    "unknown toolkit version is assumed compatible",
+			toolkitVersion: "unknown",
+			cliVersion:     "1.14.6",
+			expected:       true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, isCompatibleContainerCLIVersion(tc.toolkitVersion, tc.cliVersion))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+const (
+	// devRootAuto is the value of the --dev-root option that triggers the
+	// detection of the root containing the NVIDIA device nodes.
+	devRootAuto = "auto"
+
+	nvidiaControlDevice = "nvidiactl"
+)
+
+// newControlDeviceLocator returns a locator for the NVIDIA control device
+// node at the specified root.
+func newControlDeviceLocator(root string) lookup.Locator {
+	return lookup.NewCharDeviceLocator(
+		lookup.WithLogger(log.StandardLogger()),
+		lookup.WithRoot(root),
+		lookup.WithCount(1),
+	)
+}
+
+// detectDevRoot returns the first of the candidate roots where the NVIDIA
+// control device node (/dev/nvidiactl) is found along with the path at which
+// this root is accessible in the container. Since the installer runs in a
+// container, the candidates are probed at their container paths and the host
+// path of the root that is found is returned for use in the generated config.
+// If the device node is not found at any of the candidates, the driver root is
+// returned instead.
+func detectDevRoot(driverRoot string, driverRootCtrPath string, newLocator func(string) lookup.Locator) (string, string) {
+	type candidate struct {
+		root    string
+		ctrPath string
+	}
+	candidates := []candidate{{driverRoot, driverRootCtrPath}}
+	switch {
+	case driverRoot == "/":
+	case driverRoot == driverRootCtrPath:
+		// The host paths are accessible as is (e.g. when not running in a
+		// container) which means that the host root can also be probed.
+		candidates = append(candidates, candidate{"/", "/"})
+	default:
+		log.Debugf("Skipping detection of dev-root at /: the host root is not accessible in the container")
+	}
+
+	for _, c := range candidates {
+		if _, err := newLocator(c.ctrPath).Locate(nvidiaControlDevice); err != nil {
+			log.Debugf("Control device not found at %v (%v in the container): %v", c.root, c.ctrPath, err)
+			continue
+		}
+		log.Infof("Detected dev-root %v (%v in the container)", c.root, c.ctrPath)
+		return c.root, c.ctrPath
+	}
+	log.Warningf("Failed to detect dev-root; using driver-root %v", driverRoot)
+	return driverRoot, driverRootCtrPath
+}
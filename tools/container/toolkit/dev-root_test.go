@@ -0,0 +1,98 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+func TestDetectDevRoot(t *testing.T) {
+	withControlDevice := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(withControlDevice, "dev"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(withControlDevice, "dev", "nvidiactl"), nil, 0644))
+
+	withoutControlDevice := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(withoutControlDevice, "dev"), 0755))
+
+	testCases := []struct {
+		description       string
+		driverRoot        string
+		driverRootCtrPath string
+		hostRoot          string
+		expectedRoot      string
+		expectedCtrPath   string
+	}{
+		{
+			description:       "driver root with control device is selected",
+			driverRoot:        "/run/nvidia/driver",
+			driverRootCtrPath: withControlDevice,
+			expectedRoot:      "/run/nvidia/driver",
+			expectedCtrPath:   withControlDevice,
+		},
+		{
+			description:       "host root with control device is selected",
+			driverRoot:        withoutControlDevice,
+			driverRootCtrPath: withoutControlDevice,
+			hostRoot:          withControlDevice,
+			expectedRoot:      "/",
+			expectedCtrPath:   "/",
+		},
+		{
+			description:       "host root is not probed if not accessible",
+			driverRoot:        "/run/nvidia/driver",
+			driverRootCtrPath: withoutControlDevice,
+			expectedRoot:      "/run/nvidia/driver",
+			expectedCtrPath:   withoutControlDevice,
+		},
+		{
+			description:       "no control device falls back to driver root",
+			driverRoot:        withoutControlDevice,
+			driverRootCtrPath: withoutControlDevice,
+			hostRoot:          withoutControlDevice,
+			expectedRoot:      withoutControlDevice,
+			expectedCtrPath:   withoutControlDevice,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			// Since the control device is not a char device in the test, we
+			// locate it as a regular file. The host root is also replaced so
+			// that the test does not depend on the host having a GPU.
+			newLocator := func(root string) lookup.Locator {
+				if root == "/" {
+					require.NotEmpty(t, tc.hostRoot, "unexpected probe of the host root")
+					root = tc.hostRoot
+				}
+				return lookup.NewFileLocator(
+					lookup.WithRoot(root),
+					lookup.WithSearchPaths("/dev"),
+					lookup.WithCount(1),
+				)
+			}
+			root, ctrPath := detectDevRoot(tc.driverRoot, tc.driverRootCtrPath, newLocator)
+			require.Equal(t, tc.expectedRoot, root)
+			require.Equal(t, tc.expectedCtrPath, ctrPath)
+		})
+	}
+}
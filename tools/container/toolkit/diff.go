@@ -0,0 +1,207 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/tools/container/operator"
+)
+
+// toolkitDiff describes the differences between the artifacts that would be
+// installed and those that are currently installed in the toolkit root.
+// Each list holds file names relative to the toolkit root and is sorted.
+type toolkitDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+// IsEmpty returns true if the installed artifacts match those that would be
+// installed.
+func (d *toolkitDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// printToolkitDiff writes the differences between the artifacts that would be
+// installed and those installed in the specified toolkit root to the writer.
+// The libraries for the specified architecture are preferred as for an install.
+func printToolkitDiff(toolkitRoot string, libraryArch string, w io.Writer) error {
+	artifacts, err := getToolkitArtifacts(libraryArch)
+	if err != nil {
+		return fmt.Errorf("error locating toolkit artifacts: %w", err)
+	}
+
+	diff, err := diffToolkitArtifacts(artifacts, toolkitRoot)
+	if err != nil {
+		return fmt.Errorf("error comparing toolkit artifacts to %v: %w", toolkitRoot, err)
+	}
+
+	return diff.write(w)
+}
+
+// write outputs the diff with one file per line, prefixed by + for added,
+// - for removed, and ~ for changed files.
+func (d *toolkitDiff) write(w io.Writer) error {
+	if d.IsEmpty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	for _, entries := range []struct {
+		prefix string
+		names  []string
+	}{
+		{"+", d.Added},
+		{"-", d.Removed},
+		{"~", d.Changed},
+	} {
+		for _, name := range entries.names {
+			if _, err := fmt.Fprintf(w, "%s %s\n", entries.prefix, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getToolkitArtifacts returns the files that an install copies to the toolkit
+// root as a map of the installed file name to the source path. Generated files
+// such as the executable wrappers and the toolkit config are not included.
+func getToolkitArtifacts(libraryArch string) (map[string]string, error) {
+	artifacts := make(map[string]string)
+	for _, l := range containerLibraries {
+		libraryPath, err := findLibrary("", l, libraryArch)
+		if err != nil {
+			return nil, fmt.Errorf("error locating NVIDIA container library: %w", err)
+		}
+		artifacts[filepath.Base(libraryPath)] = libraryPath
+	}
+
+	executables := []string{
+		"/usr/bin/nvidia-ctk",
+		"/usr/bin/nvidia-cdi-hook",
+		nvidiaContainerCliSource,
+		nvidiaContainerRuntimeHookSource,
+	}
+	for _, runtime := range operator.GetRuntimes() {
+		executables = append(executables, runtime.Path)
+	}
+	for _, e := range executables {
+		artifacts[filepath.Base(e)+".real"] = e
+	}
+
+	return artifacts, nil
+}
+
+// diffToolkitArtifacts compares the specified artifacts against the files
+// installed in the toolkit root. An artifact is added if it is not installed
+// and changed if the installed file differs in size or checksum. Installed
+// libraries and executables (.real files) that are not in the artifacts are
+// reported as removed. A missing toolkit root is treated as an empty one.
+func diffToolkitArtifacts(artifacts map[string]string, toolkitRoot string) (*toolkitDiff, error) {
+	diff := &toolkitDiff{}
+	for name, source := range artifacts {
+		installed := filepath.Join(toolkitRoot, name)
+		if _, err := os.Stat(installed); errors.Is(err, os.ErrNotExist) {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		same, err := sameFileContents(source, installed)
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+
+	entries, err := os.ReadDir(toolkitRoot)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error reading toolkit root: %w", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !isInstalledArtifact(name) {
+			continue
+		}
+		if _, ok := artifacts[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Changed)
+
+	return diff, nil
+}
+
+// isInstalledArtifact returns true if the specified file name in the toolkit
+// root corresponds to a file copied by the installer.
+func isInstalledArtifact(name string) bool {
+	return strings.HasSuffix(name, ".real") || strings.Contains(name, ".so")
+}
+
+// sameFileContents returns true if the specified files have the same size and
+// checksum.
+func sameFileContents(a string, b string) (bool, error) {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false, fmt.Errorf("error getting file info for %v: %w", a, err)
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false, fmt.Errorf("error getting file info for %v: %w", b, err)
+	}
+	if aInfo.Size() != bInfo.Size() {
+		return false, nil
+	}
+
+	aSum, err := fileChecksum(a)
+	if err != nil {
+		return false, err
+	}
+	bSum, err := fileChecksum(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aSum, bSum), nil
+}
+
+// fileChecksum returns the SHA-256 checksum of the specified file.
+func fileChecksum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening %v: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, fmt.Errorf("error reading %v: %w", path, err)
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,116 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffToolkitArtifacts(t *testing.T) {
+	testCases := []struct {
+		description    string
+		source         map[string]string
+		installed      map[string]string
+		noToolkitRoot  bool
+		expectedDiff   *toolkitDiff
+		expectedOutput string
+	}{
+		{
+			description: "identical trees",
+			source: map[string]string{
+				"libnvidia-container.so.1.2.3": "lib",
+				"nvidia-ctk.real":              "ctk",
+			},
+			installed: map[string]string{
+				"libnvidia-container.so.1.2.3": "lib",
+				"nvidia-ctk.real":              "ctk",
+				"nvidia-ctk":                   "wrapper",
+			},
+			expectedDiff:   &toolkitDiff{},
+			expectedOutput: "No changes\n",
+		},
+		{
+			description: "missing toolkit root",
+			source: map[string]string{
+				"nvidia-ctk.real": "ctk",
+			},
+			noToolkitRoot:  true,
+			expectedDiff:   &toolkitDiff{Added: []string{"nvidia-ctk.real"}},
+			expectedOutput: "+ nvidia-ctk.real\n",
+		},
+		{
+			description: "added, removed, and changed files",
+			source: map[string]string{
+				"libnvidia-container.so.1.2.4": "lib",
+				"nvidia-cdi-hook.real":         "hook",
+				"nvidia-ctk.real":              "ctk-v2",
+				"nvidia-container-cli.real":    "cli-b",
+			},
+			installed: map[string]string{
+				"libnvidia-container.so.1.2.3": "lib",
+				"nvidia-ctk.real":              "ctk",
+				"nvidia-ctk":                   "wrapper",
+				"nvidia-container-cli.real":    "cli-a",
+				"toolkit.pid":                  "1",
+			},
+			expectedDiff: &toolkitDiff{
+				Added:   []string{"libnvidia-container.so.1.2.4", "nvidia-cdi-hook.real"},
+				Removed: []string{"libnvidia-container.so.1.2.3"},
+				Changed: []string{"nvidia-container-cli.real", "nvidia-ctk.real"},
+			},
+			expectedOutput: `+ libnvidia-container.so.1.2.4
++ nvidia-cdi-hook.real
+- libnvidia-container.so.1.2.3
+~ nvidia-container-cli.real
+~ nvidia-ctk.real
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			sourceRoot := t.TempDir()
+			artifacts := make(map[string]string)
+			for name, contents := range tc.source {
+				path := filepath.Join(sourceRoot, name)
+				require.NoError(t, os.WriteFile(path, []byte(contents), 0755))
+				artifacts[name] = path
+			}
+
+			toolkitRoot := filepath.Join(t.TempDir(), "toolkit")
+			if !tc.noToolkitRoot {
+				require.NoError(t, os.Mkdir(toolkitRoot, 0755))
+			}
+			for name, contents := range tc.installed {
+				require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, name), []byte(contents), 0755))
+			}
+
+			diff, err := diffToolkitArtifacts(artifacts, toolkitRoot)
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedDiff, diff)
+
+			output := &bytes.Buffer{}
+			require.NoError(t, diff.write(output))
+			require.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const envFileFlagName = "env-file"
+
+// getEnvFilePath returns the path of the env file specified in the command line
+// arguments. Since the variables in the env file need to be applied before
+// the flags are resolved, the arguments are scanned before they are parsed.
+// If the flag is not specified, the ENV_FILE envvar is used.
+func getEnvFilePath(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		if name == envFileFlagName && i+1 < len(args) {
+			return args[i+1]
+		}
+		if value, ok := strings.CutPrefix(name, envFileFlagName+"="); ok {
+			return value
+		}
+	}
+	return os.Getenv("ENV_FILE")
+}
+
+// applyEnvFile loads the variables from the specified env file and sets them
+// in the environment so that they are picked up by the envvars associated
+// with each flag. Variables that are already set in the environment are not
+// overridden.
+func applyEnvFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	envs, err := parseEnvFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse env file %v: %w", path, err)
+	}
+	for _, env := range envs {
+		if _, exists := os.LookupEnv(env[0]); exists {
+			continue
+		}
+		if err := os.Setenv(env[0], env[1]); err != nil {
+			return fmt.Errorf("failed to set %v: %w", env[0], err)
+		}
+	}
+	return nil
+}
+
+// parseEnvFile parses KEY=VALUE lines from the specified reader. Empty lines
+// and lines starting with # are ignored and an optional leading export is
+// stripped. Values may be enclosed in single or double quotes. For unquoted
+// values, a # preceded by whitespace starts a comment.
+func parseEnvFile(r io.Reader) ([][2]string, error) {
+	var envs [][2]string
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || !isValidEnvName(key) {
+			return nil, fmt.Errorf("line %d: invalid entry %q", lineNumber, line)
+		}
+		value, err := parseEnvFileValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		envs = append(envs, [2]string{key, value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+func parseEnvFileValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '\'', '"':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value %v", value)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected characters after quoted value %v", value)
+		}
+		unquoted := value[1:end]
+		if quote == '"' {
+			unquoted = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n").Replace(unquoted)
+		}
+		return unquoted, nil
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	if i := strings.Index(value, "\t#"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), nil
+}
+
+func isValidEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	testCases := []struct {
+		description   string
+		contents      string
+		expectedEnvs  [][2]string
+		expectedError bool
+	}{
+		{
+			description: "comments and empty lines are skipped",
+			contents: `
+# A comment
+  # An indented comment
+
+FOO=bar
+`,
+			expectedEnvs: [][2]string{{"FOO", "bar"}},
+		},
+		{
+			description: "quoted values",
+			contents: `DOUBLE="a value # not a comment"
+SINGLE='a \n literal'
+ESCAPED="say \"hi\"\nbye"
+TRAILING="value" # a comment`,
+			expectedEnvs: [][2]string{
+				{"DOUBLE", "a value # not a comment"},
+				{"SINGLE", `a \n literal`},
+				{"ESCAPED", "say \"hi\"\nbye"},
+				{"TRAILING", "value"},
+			},
+		},
+		{
+			description: "unquoted values",
+			contents: `export EXPORTED=1
+SPACED = value with spaces  # a comment
+EMPTY=
+URL=http://example.com/#anchor`,
+			expectedEnvs: [][2]string{
+				{"EXPORTED", "1"},
+				{"SPACED", "value with spaces"},
+				{"EMPTY", ""},
+				{"URL", "http://example.com/#anchor"},
+			},
+		},
+		{
+			description:   "missing separator is an error",
+			contents:      "FOO",
+			expectedError: true,
+		},
+		{
+			description:   "invalid name is an error",
+			contents:      "1FOO=bar",
+			expectedError: true,
+		},
+		{
+			description:   "unterminated quote is an error",
+			contents:      `FOO="bar`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			envs, err := parseEnvFile(strings.NewReader(tc.contents))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedEnvs, envs)
+		})
+	}
+}
+
+func TestGetEnvFilePath(t *testing.T) {
+	t.Setenv("ENV_FILE", "/from/envvar")
+
+	require.Equal(t, "/from/flag", getEnvFilePath([]string{"install", "--env-file", "/from/flag", "/toolkit"}))
+	require.Equal(t, "/from/flag", getEnvFilePath([]string{"install", "-env-file=/from/flag"}))
+	require.Equal(t, "/from/envvar", getEnvFilePath([]string{"install", "/toolkit"}))
+	require.Equal(t, "/from/envvar", getEnvFilePath([]string{"install", "--", "--env-file", "/from/flag"}))
+}
+
+func TestApplyEnvFile(t *testing.T) {
+	// Ensure that the envvars are restored once the test completes.
+	for _, key := range []string{"CDI_ENABLED", "DRIVER_ROOT", "NVIDIA_DRIVER_ROOT", "CDI_OUTPUT_DIR"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+	t.Setenv("CDI_OUTPUT_DIR", "/from/environment")
+
+	envFile := filepath.Join(t.TempDir(), "toolkit.env")
+	require.NoError(t, os.WriteFile(envFile, []byte(`# Toolkit options
+CDI_ENABLED=true
+DRIVER_ROOT="/run/nvidia/driver"
+CDI_OUTPUT_DIR=/from/env-file
+`), 0600))
+
+	require.NoError(t, applyEnvFile(getEnvFilePath([]string{"install", "--env-file=" + envFile})))
+
+	opts := options{}
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "cdi-enabled",
+			Destination: &opts.cdiEnabled,
+			EnvVars:     []string{"CDI_ENABLED", "ENABLE_CDI"},
+		},
+		&cli.StringFlag{
+			Name:        "driver-root",
+			Value:       "/",
+			Destination: &opts.DriverRoot,
+			EnvVars:     []string{"NVIDIA_DRIVER_ROOT", "DRIVER_ROOT"},
+		},
+		&cli.StringFlag{
+			Name:        "cdi-output-dir",
+			Destination: &opts.cdiOutputDir,
+			EnvVars:     []string{"CDI_OUTPUT_DIR"},
+		},
+		&cli.StringFlag{
+			Name: envFileFlagName,
+		},
+	}
+	require.NoError(t, app.Run([]string{"toolkit", "--env-file=" + envFile}))
+
+	require.True(t, opts.cdiEnabled)
+	require.Equal(t, "/run/nvidia/driver", opts.DriverRoot)
+	require.Equal(t, "/from/environment", opts.cdiOutputDir)
+}
+
+func TestApplyEnvFileMissing(t *testing.T) {
+	require.NoError(t, applyEnvFile(""))
+	require.Error(t, applyEnvFile(filepath.Join(t.TempDir(), "missing.env")))
+}
@@ -0,0 +1,28 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import "errors"
+
+var (
+	// ErrArtifactNotFound indicates that a file required for the installation
+	// of the toolkit, such as a library or executable, could not be found.
+	ErrArtifactNotFound = errors.New("artifact not found")
+	// ErrCopyFailed indicates that a file could not be copied to the toolkit
+	// directory.
+	ErrCopyFailed = errors.New("copy failed")
+)
@@ -40,20 +40,21 @@ type executable struct {
 	argLines []string
 }
 
-// install installs an executable component of the NVIDIA container toolkit. The source executable
-// is copied to a `.real` file and a wapper is created to set up the environment as required.
-func (e executable) install(destFolder string) (string, error) {
+// install installs an executable component of the NVIDIA container toolkit using the specified
+// installer. The source executable is copied to a `.real` file and a wapper is created to set up
+// the environment as required.
+func (e executable) install(i *installer, destFolder string) (string, error) {
 	log.Infof("Installing executable '%v' to %v", e.source, destFolder)
 
 	dotfileName := e.dotfileName()
 
-	installedDotfileName, err := installFileToFolderWithName(destFolder, dotfileName, e.source)
+	installedDotfileName, err := i.installFileToFolderWithName(destFolder, dotfileName, e.source)
 	if err != nil {
-		return "", fmt.Errorf("error installing file '%v' as '%v': %v", e.source, dotfileName, err)
+		return "", fmt.Errorf("error installing file '%v' as '%v': %w", e.source, dotfileName, err)
 	}
 	log.Infof("Installed '%v'", installedDotfileName)
 
-	wrapperFilename, err := e.installWrapper(destFolder, installedDotfileName)
+	wrapperFilename, err := e.installWrapper(i, destFolder, installedDotfileName)
 	if err != nil {
 		return "", fmt.Errorf("error wrapping '%v': %v", installedDotfileName, err)
 	}
@@ -70,7 +71,7 @@ func (e executable) wrapperName() string {
 	return e.target.wrapperName
 }
 
-func (e executable) installWrapper(destFolder string, dotfileName string) (string, error) {
+func (e executable) installWrapper(i *installer, destFolder string, dotfileName string) (string, error) {
 	wrapperPath := filepath.Join(destFolder, e.wrapperName())
 	wrapper, err := os.Create(wrapperPath)
 	if err != nil {
@@ -87,6 +88,10 @@ func (e executable) installWrapper(destFolder string, dotfileName string) (strin
 	if err != nil {
 		return "", fmt.Errorf("error making wrapper executable: %v", err)
 	}
+
+	if err := i.setOwnership(wrapperPath); err != nil {
+		return "", err
+	}
 	return wrapperPath, nil
 }
 
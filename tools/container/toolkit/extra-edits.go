@@ -0,0 +1,63 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+// loadExtraEdits loads the container edits from the specified (partial) CDI
+// specification. Only the top-level containerEdits of the file are considered
+// and these are validated before being returned.
+func loadExtraEdits(path string) (*cdi.ContainerEdits, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read extra edits: %w", err)
+	}
+	raw, err := cdi.ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse extra edits from %v: %w", path, err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("no extra edits defined in %v", path)
+	}
+
+	edits := &cdi.ContainerEdits{ContainerEdits: &raw.ContainerEdits}
+	if err := edits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid extra edits in %v: %w", path, err)
+	}
+	return edits, nil
+}
+
+// mergeExtraEdits appends the container edits defined in the specified file to
+// the common edits of the specified CDI specification.
+func mergeExtraEdits(spec *specs.Spec, path string) error {
+	if path == "" {
+		return nil
+	}
+	extra, err := loadExtraEdits(path)
+	if err != nil {
+		return err
+	}
+	edits := &cdi.ContainerEdits{ContainerEdits: &spec.ContainerEdits}
+	edits.Append(extra)
+	return nil
+}
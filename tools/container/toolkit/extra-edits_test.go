@@ -0,0 +1,109 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/specs-go"
+)
+
+func TestMergeExtraEdits(t *testing.T) {
+	testCases := []struct {
+		description   string
+		extraEdits    string
+		expectedError bool
+		expected      specs.ContainerEdits
+	}{
+		{
+			description: "extra edits are appended",
+			extraEdits: `containerEdits:
+  env:
+  - FOO=bar
+  mounts:
+  - hostPath: /run/nvidia/mps
+    containerPath: /run/nvidia/mps
+    options: ["rw", "bind"]
+`,
+			expected: specs.ContainerEdits{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=void", "FOO=bar"},
+				Mounts: []*specs.Mount{
+					{HostPath: "/usr/bin/nvidia-smi", ContainerPath: "/usr/bin/nvidia-smi"},
+					{HostPath: "/run/nvidia/mps", ContainerPath: "/run/nvidia/mps", Options: []string{"rw", "bind"}},
+				},
+			},
+		},
+		{
+			description: "empty extra edits leave the spec unchanged",
+			extraEdits:  "containerEdits: {}\n",
+			expected: specs.ContainerEdits{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=void"},
+				Mounts: []*specs.Mount{
+					{HostPath: "/usr/bin/nvidia-smi", ContainerPath: "/usr/bin/nvidia-smi"},
+				},
+			},
+		},
+		{
+			description: "invalid extra edits return an error",
+			extraEdits: `containerEdits:
+  mounts:
+  - hostPath: /run/nvidia/mps
+`,
+			expectedError: true,
+		},
+		{
+			description:   "unknown fields return an error",
+			extraEdits:    "containerEdit: {}\n",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "extra-edits.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.extraEdits), 0644))
+
+			spec := &specs.Spec{
+				ContainerEdits: specs.ContainerEdits{
+					Env: []string{"NVIDIA_VISIBLE_DEVICES=void"},
+					Mounts: []*specs.Mount{
+						{HostPath: "/usr/bin/nvidia-smi", ContainerPath: "/usr/bin/nvidia-smi"},
+					},
+				},
+			}
+
+			err := mergeExtraEdits(spec, path)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, spec.ContainerEdits)
+		})
+	}
+}
+
+func TestMergeExtraEditsMissingFile(t *testing.T) {
+	spec := &specs.Spec{}
+	err := mergeExtraEdits(spec, filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, mergeExtraEdits(spec, ""))
+}
@@ -0,0 +1,188 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fileInstaller defines the interface for installing a file to the toolkit
+// directory.
+type fileInstaller interface {
+	installFile(dest string, src string) error
+}
+
+// fileInstallerFunc allows a function to be used as a fileInstaller.
+type fileInstallerFunc func(dest string, src string) error
+
+func (f fileInstallerFunc) installFile(dest string, src string) error {
+	return f(dest, src)
+}
+
+// installer installs the components of the NVIDIA container toolkit to the
+// toolkit directory.
+type installer struct {
+	fileInstaller fileInstaller
+	// replaceConflictingSymlinks specifies whether installSymlink replaces
+	// files or symlinks to a different target that exist at the symlink path.
+	// If this is false, an error is returned instead.
+	replaceConflictingSymlinks bool
+	// libraryArch specifies the architecture whose library directories are
+	// searched first for the toolkit libraries.
+	libraryArch string
+	// ownerUID and ownerGID specify the owner of the installed files and
+	// directories. A value of -1 leaves the corresponding value unchanged.
+	ownerUID int
+	ownerGID int
+	// concurrency specifies the maximum number of files that are installed
+	// concurrently. A value less than 1 uses GOMAXPROCS.
+	concurrency int
+}
+
+// InstallerOption defines a function for passing options to the newInstaller
+// call.
+type InstallerOption func(*installer)
+
+// WithFileInstaller sets the file installer used to install files to the
+// toolkit directory. If this is not set, files are copied.
+func WithFileInstaller(fileInstaller fileInstaller) InstallerOption {
+	return func(i *installer) {
+		i.fileInstaller = fileInstaller
+	}
+}
+
+// WithReplaceConflictingSymlinks sets whether files or symlinks to a different
+// target that exist at the path of an installed symlink are replaced. This is
+// enabled by default.
+func WithReplaceConflictingSymlinks(replace bool) InstallerOption {
+	return func(i *installer) {
+		i.replaceConflictingSymlinks = replace
+	}
+}
+
+// WithLibraryArch sets the architecture whose library directories are searched
+// first for the toolkit libraries. A value of "auto" (the default) selects the
+// architecture of the running binary.
+func WithLibraryArch(arch string) InstallerOption {
+	return func(i *installer) {
+		i.libraryArch = arch
+	}
+}
+
+// WithOwner sets the uid and gid of the owner of the installed files,
+// symlinks, and directories. A uid or gid of -1 (the default) leaves the
+// corresponding value unchanged.
+func WithOwner(uid int, gid int) InstallerOption {
+	return func(i *installer) {
+		i.ownerUID = uid
+		i.ownerGID = gid
+	}
+}
+
+// WithConcurrency sets the maximum number of files, such as libraries and
+// runtimes, that are installed concurrently. If this is less than 1 (the
+// default), GOMAXPROCS is used.
+func WithConcurrency(n int) InstallerOption {
+	return func(i *installer) {
+		i.concurrency = n
+	}
+}
+
+func newInstaller(opts ...InstallerOption) *installer {
+	i := &installer{
+		replaceConflictingSymlinks: true,
+		libraryArch:                "auto",
+		ownerUID:                   -1,
+		ownerGID:                   -1,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	if i.fileInstaller == nil {
+		i.fileInstaller = fileInstallerFunc(copyFile)
+	}
+	return i
+}
+
+// retryingFileInstaller wraps a file installer and retries installing a file
+// if a transient error occurs.
+type retryingFileInstaller struct {
+	fileInstaller
+	retries int
+	backoff time.Duration
+	sleep   func(time.Duration)
+}
+
+// RetryOption defines a function for passing options to the
+// newRetryingFileInstaller call.
+type RetryOption func(*retryingFileInstaller)
+
+// WithRetries sets the number of times that installing a file is retried if a
+// transient error occurs and the initial delay before the first retry. The
+// delay is doubled for each subsequent retry.
+func WithRetries(count int, backoff time.Duration) RetryOption {
+	return func(r *retryingFileInstaller) {
+		r.retries = count
+		r.backoff = backoff
+	}
+}
+
+func newRetryingFileInstaller(installer fileInstaller, opts ...RetryOption) fileInstaller {
+	r := &retryingFileInstaller{
+		fileInstaller: installer,
+		sleep:         time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.retries <= 0 {
+		return installer
+	}
+	return r
+}
+
+// installFile installs the file using the wrapped installer. Transient errors
+// are retried with an exponential backoff. Other errors are returned
+// immediately.
+func (r *retryingFileInstaller) installFile(dest string, src string) error {
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := r.fileInstaller.installFile(dest, src)
+		if err == nil || !isTransientCopyError(err) || attempt >= r.retries {
+			return err
+		}
+		log.Warningf("Retrying installation of '%v' in %v (attempt %d of %d): %v", src, backoff, attempt+1, r.retries, err)
+		r.sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// isTransientCopyError checks whether the specified error may succeed if the
+// operation is retried. This includes I/O errors and stale file handles that
+// are seen on network filesystems.
+func isTransientCopyError(err error) bool {
+	for _, transient := range []error{syscall.EIO, syscall.ESTALE, syscall.EAGAIN, syscall.EINTR, syscall.ETIMEDOUT} {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type failingFileInstaller struct {
+	errs  []error
+	calls int
+}
+
+func (f *failingFileInstaller) installFile(dest string, src string) error {
+	f.calls++
+	if f.calls > len(f.errs) {
+		return nil
+	}
+	return f.errs[f.calls-1]
+}
+
+func TestRetryingFileInstaller(t *testing.T) {
+	eio := &os.PathError{Op: "read", Path: "/artifacts/libfoo.so", Err: syscall.EIO}
+	estale := fmt.Errorf("error copying file: %w: %w", ErrCopyFailed, &os.PathError{Op: "read", Path: "/artifacts/libfoo.so", Err: syscall.ESTALE})
+	enoent := fmt.Errorf("error opening source: %w: %w", ErrArtifactNotFound, &os.PathError{Op: "open", Path: "/artifacts/libfoo.so", Err: syscall.ENOENT})
+
+	testCases := []struct {
+		description    string
+		retries        int
+		errs           []error
+		expectedError  error
+		expectedCalls  int
+		expectedSleeps []time.Duration
+	}{
+		{
+			description:    "transient errors are retried",
+			retries:        3,
+			errs:           []error{eio, estale},
+			expectedCalls:  3,
+			expectedSleeps: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			description:   "permanent errors are not retried",
+			retries:       3,
+			errs:          []error{enoent},
+			expectedError: ErrArtifactNotFound,
+			expectedCalls: 1,
+		},
+		{
+			description:    "the last error is returned if retries are exhausted",
+			retries:        1,
+			errs:           []error{eio, estale},
+			expectedError:  ErrCopyFailed,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{time.Second},
+		},
+		{
+			description:   "no retries by default",
+			errs:          []error{eio},
+			expectedError: syscall.EIO,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mock := &failingFileInstaller{errs: tc.errs}
+
+			var sleeps []time.Duration
+			installer := newRetryingFileInstaller(mock,
+				WithRetries(tc.retries, time.Second),
+				func(r *retryingFileInstaller) {
+					r.sleep = func(d time.Duration) {
+						sleeps = append(sleeps, d)
+					}
+				},
+			)
+
+			err := installer.installFile("/dest/libfoo.so", "/artifacts/libfoo.so")
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedCalls, mock.calls)
+			require.EqualValues(t, tc.expectedSleeps, sleeps)
+		})
+	}
+}
+
+func TestInstallerUsesFileInstaller(t *testing.T) {
+	mock := &failingFileInstaller{errs: []error{ErrCopyFailed}}
+	i := newInstaller(WithFileInstaller(mock))
+
+	_, err := i.installFileToFolder("/dest", "/artifacts/libfoo.so")
+	require.ErrorIs(t, err, ErrCopyFailed)
+	require.Equal(t, 1, mock.calls)
+}
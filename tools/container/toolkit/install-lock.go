@@ -0,0 +1,163 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// errInstallInProgress indicates that another live process owns the toolkit
+// directory.
+var errInstallInProgress = errors.New("another install is in progress")
+
+// installLock represents the ownership of a toolkit directory. Ownership is
+// recorded by holding an exclusive flock on the toolkit.pid file in the
+// directory which contains the PID of the owning process.
+type installLock struct {
+	path string
+	// file is the locked pid file if the lock is held by this process. The
+	// pid file is removed when the lock is released.
+	file *os.File
+}
+
+// acquireInstallLock takes an exclusive flock on the toolkit.pid file in the
+// specified toolkit directory and records the PID of the current process in
+// it. Since the lock is released by the kernel when a process exits, a pid
+// file left behind by a process that is no longer running does not prevent
+// the lock from being acquired. If the lock is held by the current process or
+// its parent (e.g. the nvidia-toolkit container entrypoint), the directory is
+// considered owned. If the lock is held by another process, an error is
+// returned unless force is specified.
+func acquireInstallLock(toolkitRoot string, force bool) (*installLock, error) {
+	if err := os.MkdirAll(toolkitRoot, 0755); err != nil {
+		return nil, fmt.Errorf("error creating toolkit directory: %w", err)
+	}
+	pidFile := filepath.Join(toolkitRoot, toolkitPidFilename)
+
+	// If the pid file is removed by its previous owner after it was opened
+	// here, the lock is taken on a file that is no longer reachable. A second
+	// attempt is made in this case.
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(pidFile, os.O_RDWR|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %v: %w", pidFile, err)
+		}
+
+		err = unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			f.Close()
+			return lockHeldByOther(toolkitRoot, pidFile, force)
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("unable to lock %v: %w", pidFile, err)
+		}
+
+		if !isSameFile(f, pidFile) {
+			f.Close()
+			continue
+		}
+
+		if err := writePid(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("unable to write PID to %v: %w", pidFile, err)
+		}
+		return &installLock{path: pidFile, file: f}, nil
+	}
+	return nil, fmt.Errorf("unable to lock %v: the file was recreated by another process", pidFile)
+}
+
+// lockHeldByOther handles the case where the lock on the specified pid file is
+// held by another open file. This is allowed if the recorded PID is that of the
+// current process or its parent, or if force is specified.
+func lockHeldByOther(toolkitRoot string, pidFile string, force bool) (*installLock, error) {
+	pid, err := readPidFile(pidFile)
+	switch {
+	case err == nil && (pid == os.Getpid() || pid == os.Getppid()):
+		log.Infof("Toolkit directory %v is already owned by this process (PID %d)", toolkitRoot, pid)
+		return &installLock{path: pidFile}, nil
+	case force:
+		log.Warningf("Forcing install to %v which is locked by another process", toolkitRoot)
+		return &installLock{path: pidFile}, nil
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v is locked by another process; use --force to override", errInstallInProgress, toolkitRoot)
+	default:
+		return nil, fmt.Errorf("%w: %v is owned by running process %d; use --force to override", errInstallInProgress, toolkitRoot, pid)
+	}
+}
+
+// release removes the pid file and releases the lock if the lock is held by
+// this process. The file is removed before it is closed so that another
+// process cannot lock it in between.
+func (l *installLock) release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	defer l.file.Close()
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove %v: %w", l.path, err)
+	}
+	return nil
+}
+
+// isSameFile checks whether the specified open file is the file at the
+// specified path.
+func isSameFile(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(opened, current)
+}
+
+// writePid replaces the contents of the specified file with the PID of the
+// current process.
+func writePid(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(fmt.Sprintf("%v\n", os.Getpid())), 0)
+	return err
+}
+
+// readPidFile reads the PID from the specified pid file.
+func readPidFile(path string) (int, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid PID %q: %w", strings.TrimSpace(string(contents)), err)
+	}
+	if pid <= 0 {
+		return 0, fmt.Errorf("invalid PID %d", pid)
+	}
+	return pid, nil
+}
@@ -0,0 +1,148 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestAcquireInstallLock(t *testing.T) {
+	running := exec.Command("sleep", "60")
+	require.NoError(t, running.Start())
+	defer func() {
+		_ = running.Process.Kill()
+		_ = running.Wait()
+	}()
+	livePid := running.Process.Pid
+
+	testCases := []struct {
+		description   string
+		existingPid   string
+		locked        bool
+		force         bool
+		expectedError error
+		expectedPid   int
+		expectRemoved bool
+	}{
+		{
+			description:   "missing pid file is created",
+			expectedPid:   os.Getpid(),
+			expectRemoved: true,
+		},
+		{
+			description:   "unlocked pid file is replaced",
+			existingPid:   fmt.Sprintf("%d\n", livePid),
+			expectedPid:   os.Getpid(),
+			expectRemoved: true,
+		},
+		{
+			description:   "invalid pid file is replaced",
+			existingPid:   "not-a-pid",
+			expectedPid:   os.Getpid(),
+			expectRemoved: true,
+		},
+		{
+			description:   "locked pid file is an error",
+			existingPid:   fmt.Sprintf("%d\n", livePid),
+			locked:        true,
+			expectedError: errInstallInProgress,
+			expectedPid:   livePid,
+		},
+		{
+			description:   "locked invalid pid file is an error",
+			existingPid:   "not-a-pid",
+			locked:        true,
+			expectedError: errInstallInProgress,
+		},
+		{
+			description: "locked pid file is kept if forced",
+			existingPid: fmt.Sprintf("%d\n", livePid),
+			locked:      true,
+			force:       true,
+			expectedPid: livePid,
+		},
+		{
+			description: "pid file locked by parent process is kept",
+			existingPid: fmt.Sprintf("%d\n", os.Getppid()),
+			locked:      true,
+			expectedPid: os.Getppid(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toolkitRoot := filepath.Join(t.TempDir(), "toolkit")
+			pidFile := filepath.Join(toolkitRoot, toolkitPidFilename)
+			if tc.existingPid != "" {
+				require.NoError(t, os.MkdirAll(toolkitRoot, 0755))
+				require.NoError(t, os.WriteFile(pidFile, []byte(tc.existingPid), 0644))
+			}
+			if tc.locked {
+				// A lock taken on a separate open file conflicts with the lock
+				// taken by acquireInstallLock, as if held by another process.
+				f, err := os.Open(pidFile)
+				require.NoError(t, err)
+				defer f.Close()
+				require.NoError(t, unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB))
+			}
+
+			lock, err := acquireInstallLock(toolkitRoot, tc.force)
+			require.ErrorIs(t, err, tc.expectedError)
+
+			if tc.expectedPid != 0 {
+				pid, err := readPidFile(pidFile)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedPid, pid)
+			}
+
+			if tc.expectedError != nil {
+				return
+			}
+
+			require.NoError(t, lock.release())
+			_, err = os.Stat(pidFile)
+			if tc.expectRemoved {
+				require.ErrorIs(t, err, os.ErrNotExist)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemoveInstallationKeepsPidFile(t *testing.T) {
+	toolkitRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, toolkitPidFilename), []byte("1\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(toolkitRoot, ".config", "nvidia-container-runtime"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, "nvidia-container-runtime"), nil, 0755))
+
+	require.NoError(t, removeInstallation(toolkitRoot))
+
+	entries, err := os.ReadDir(toolkitRoot)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, toolkitPidFilename, entries[0].Name())
+
+	require.NoError(t, removeInstallation(filepath.Join(toolkitRoot, "missing")))
+}
@@ -0,0 +1,57 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup"
+)
+
+// ldconfigSearchPaths are the directories in the driver root that are searched
+// for an ldconfig binary if the configured binary does not exist.
+var ldconfigSearchPaths = []string{"/sbin", "/usr/sbin"}
+
+// locateLdconfig locates an alternative to the specified ldconfig binary in the
+// driver root. The directory of the specified binary is searched first,
+// followed by the ldconfig search paths. For each directory, the .real binary
+// is preferred. The located path includes the driver root.
+func locateLdconfig(driverRoot string, ldconfigPath string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(ldconfigPath), ".real")
+	searchPaths := append([]string{filepath.Dir(ldconfigPath)}, ldconfigSearchPaths...)
+
+	for _, searchPath := range searchPaths {
+		locator := lookup.NewFileLocator(
+			lookup.WithLogger(log.StandardLogger()),
+			lookup.WithRoot(driverRoot),
+			lookup.WithSearchPaths(searchPath),
+			lookup.WithCount(1),
+		)
+		for _, candidate := range []string{name + ".real", name} {
+			located, err := locator.Locate(candidate)
+			if err != nil || len(located) == 0 {
+				continue
+			}
+			return located[0], nil
+		}
+	}
+	return "", fmt.Errorf("no %v binary found in %v", name, driverRoot)
+}
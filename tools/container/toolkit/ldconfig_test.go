@@ -0,0 +1,91 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDriverLdconfigPathFallback(t *testing.T) {
+	testCases := []struct {
+		description  string
+		ldconfigPath string
+		files        []string
+		expectedPath string
+	}{
+		{
+			description:  "configured ldconfig is used",
+			ldconfigPath: "@/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig", "/usr/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig",
+		},
+		{
+			description:  "configured ldconfig.real is used",
+			ldconfigPath: "@/sbin/ldconfig.real",
+			files:        []string{"/sbin/ldconfig.real", "/usr/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig.real",
+		},
+		{
+			description:  "ldconfig.real falls back to ldconfig",
+			ldconfigPath: "@/sbin/ldconfig.real",
+			files:        []string{"/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig",
+		},
+		{
+			description:  "ldconfig falls back to ldconfig.real",
+			ldconfigPath: "@/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig.real"},
+			expectedPath: "/sbin/ldconfig.real",
+		},
+		{
+			description:  "ldconfig falls back to another directory",
+			ldconfigPath: "@/sbin/ldconfig",
+			files:        []string{"/usr/sbin/ldconfig"},
+			expectedPath: "/usr/sbin/ldconfig",
+		},
+		{
+			description:  "ldconfig.real is preferred in another directory",
+			ldconfigPath: "@/sbin/ldconfig.real",
+			files:        []string{"/usr/sbin/ldconfig", "/usr/sbin/ldconfig.real"},
+			expectedPath: "/usr/sbin/ldconfig.real",
+		},
+		{
+			description:  "configured ldconfig is used if no alternative exists",
+			ldconfigPath: "@/sbin/ldconfig",
+			expectedPath: "/sbin/ldconfig",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			// The binaries are located in the container and the returned path
+			// refers to the driver root on the host.
+			driverRoot := "/run/nvidia/driver"
+			driverRootCtrPath := t.TempDir()
+			for _, f := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Join(driverRootCtrPath, filepath.Dir(f)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(driverRootCtrPath, f), nil, 0755))
+			}
+
+			require.Equal(t, "@"+filepath.Join(driverRoot, tc.expectedPath), getDriverLdconfigPath(driverRoot, driverRootCtrPath, tc.ldconfigPath))
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+)
+
+// getManagementSpecName returns the name (without extension) under which the
+// management spec for the specified vendor and class is saved. If a driver
+// version is specified, this is appended to the name so that specs generated
+// for different driver versions do not collide.
+func getManagementSpecName(vendor string, class string, driverVersion string) string {
+	name := cdi.GenerateSpecName(vendor, class)
+	if driverVersion == "" {
+		return name
+	}
+	return name + "-" + driverVersion
+}
+
+// getManagementDeviceSpecName returns the name (without extension) under which
+// the management spec for the GPU with the specified UUID is saved.
+func getManagementDeviceSpecName(vendor string, class string, driverVersion string, uuid string) string {
+	return getManagementSpecName(vendor, class, driverVersion) + "-" + uuid
+}
+
+// saveManagementSpecs saves the specified management specs to the output
+// directory under the names with which they are keyed. If an extra edits file
+// is specified, its edits are merged into each spec before it is saved. The
+// names of the saved specs are returned.
+func saveManagementSpecs(outputDir string, extraEdits string, specs map[string]spec.Interface) ([]string, error) {
+	names := getSortedSpecNames(specs)
+	for _, name := range names {
+		s := specs[name]
+		if err := mergeExtraEdits(s.Raw(), extraEdits); err != nil {
+			return nil, fmt.Errorf("failed to merge extra edits into CDI spec for management containers: %w", err)
+		}
+		if err := s.Save(filepath.Join(outputDir, name)); err != nil {
+			return nil, fmt.Errorf("failed to save CDI spec for management containers: %v", err)
+		}
+	}
+	return names, nil
+}
+
+// printManagementSpecs writes the specified management specs to the specified
+// writer instead of saving them. As is the case for saveManagementSpecs, the
+// extra edits are merged into each spec so that the output matches what would
+// be saved. The specs are written in the order of their names.
+func printManagementSpecs(w io.Writer, extraEdits string, specs map[string]spec.Interface) error {
+	for _, name := range getSortedSpecNames(specs) {
+		s := specs[name]
+		if err := mergeExtraEdits(s.Raw(), extraEdits); err != nil {
+			return fmt.Errorf("failed to merge extra edits into CDI spec for management containers: %w", err)
+		}
+		if _, err := s.WriteTo(w); err != nil {
+			return fmt.Errorf("failed to write CDI spec for management containers: %v", err)
+		}
+	}
+	return nil
+}
+
+func getSortedSpecNames(specs map[string]spec.Interface) []string {
+	var names []string
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pruneManagementSpecs removes the management specs for the specified vendor
+// and class from the output directory. This includes both unversioned and
+// versioned specs as well as per-device specs. The specs with the specified
+// names are kept.
+func pruneManagementSpecs(outputDir string, vendor string, class string, keep ...string) error {
+	contents, err := os.ReadDir(outputDir)
+	if err != nil {
+		return fmt.Errorf("failed to read the contents of %v: %w", outputDir, err)
+	}
+
+	baseName := cdi.GenerateSpecName(vendor, class)
+	keepNames := make(map[string]bool)
+	for _, name := range keep {
+		keepNames[name] = true
+	}
+
+	var errs []error
+	for _, content := range contents {
+		if content.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(content.Name())
+		if ext != ".yaml" && ext != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(content.Name(), ext)
+		if keepNames[name] || !isManagementSpecName(name, baseName) {
+			continue
+		}
+		filename := filepath.Join(outputDir, content.Name())
+		log.Infof("Removing stale management spec %v", filename)
+		if err := os.Remove(filename); err != nil {
+			errs = append(errs, fmt.Errorf("could not remove %v: %w", filename, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isManagementSpecName checks whether the specified name is that of an
+// unversioned, versioned, or per-device spec with the specified base name.
+func isManagementSpecName(name string, baseName string) bool {
+	if name == baseName {
+		return true
+	}
+	suffix := strings.TrimPrefix(name, baseName+"-")
+	if suffix == name || suffix == "" {
+		return false
+	}
+	if strings.HasPrefix(suffix, "GPU-") {
+		return true
+	}
+	return suffix[0] >= '0' && suffix[0] <= '9'
+}
@@ -0,0 +1,211 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"tags.cncf.io/container-device-interface/pkg/cdi"
+	"tags.cncf.io/container-device-interface/specs-go"
+
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
+)
+
+func TestGetManagementSpecName(t *testing.T) {
+	require.Equal(t, "management.nvidia.com-gpu", getManagementSpecName("management.nvidia.com", "gpu", ""))
+	require.Equal(t, "management.nvidia.com-gpu-550.54.15", getManagementSpecName("management.nvidia.com", "gpu", "550.54.15"))
+}
+
+func TestGetManagementDeviceSpecName(t *testing.T) {
+	require.Equal(t, "management.nvidia.com-gpu-GPU-1111", getManagementDeviceSpecName("management.nvidia.com", "gpu", "", "GPU-1111"))
+	require.Equal(t, "management.nvidia.com-gpu-550.54.15-GPU-1111", getManagementDeviceSpecName("management.nvidia.com", "gpu", "550.54.15", "GPU-1111"))
+}
+
+func TestSaveManagementSpecs(t *testing.T) {
+	outputDir := t.TempDir()
+
+	managementSpecs := make(map[string]spec.Interface)
+	for _, uuid := range []string{"GPU-2222", "GPU-1111"} {
+		s, err := spec.New(
+			spec.WithVendor("management.nvidia.com"),
+			spec.WithClass("gpu"),
+			spec.WithDeviceSpecs([]specs.Device{
+				{
+					Name: uuid,
+					ContainerEdits: specs.ContainerEdits{
+						Env: []string{"GPU=" + uuid},
+					},
+				},
+			}),
+			spec.WithEdits(specs.ContainerEdits{
+				Env: []string{"NVIDIA_VISIBLE_DEVICES=void"},
+			}),
+		)
+		require.NoError(t, err)
+		managementSpecs[getManagementDeviceSpecName("management.nvidia.com", "gpu", "", uuid)] = s
+	}
+
+	names, err := saveManagementSpecs(outputDir, "", managementSpecs)
+	require.NoError(t, err)
+	require.EqualValues(t, []string{"management.nvidia.com-gpu-GPU-1111", "management.nvidia.com-gpu-GPU-2222"}, names)
+
+	for _, uuid := range []string{"GPU-1111", "GPU-2222"} {
+		s, err := cdi.ReadSpec(filepath.Join(outputDir, "management.nvidia.com-gpu-"+uuid+".yaml"), 0)
+		require.NoError(t, err)
+		require.Len(t, s.Devices, 1)
+		require.Equal(t, uuid, s.Devices[0].Name)
+	}
+}
+
+func TestPrintManagementSpecs(t *testing.T) {
+	managementSpecs := make(map[string]spec.Interface)
+	for _, uuid := range []string{"GPU-2222", "GPU-1111"} {
+		s, err := spec.New(
+			spec.WithVendor("management.nvidia.com"),
+			spec.WithClass("gpu"),
+			spec.WithDeviceSpecs([]specs.Device{
+				{
+					Name: uuid,
+					ContainerEdits: specs.ContainerEdits{
+						Env: []string{"GPU=" + uuid},
+					},
+				},
+			}),
+		)
+		require.NoError(t, err)
+		managementSpecs[getManagementDeviceSpecName("management.nvidia.com", "gpu", "", uuid)] = s
+	}
+
+	var output bytes.Buffer
+	require.NoError(t, printManagementSpecs(&output, "", managementSpecs))
+
+	first := strings.Index(output.String(), "name: GPU-1111")
+	second := strings.Index(output.String(), "name: GPU-2222")
+	require.NotEqual(t, -1, first)
+	require.NotEqual(t, -1, second)
+	require.Less(t, first, second)
+}
+
+func TestGenerateCDISpecPrint(t *testing.T) {
+	driverRoot := t.TempDir()
+	libDir := filepath.Join(driverRoot, "/usr/lib64")
+	require.NoError(t, os.MkdirAll(libDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(libDir, "libcuda.so.999.88.77"), nil, 0644))
+
+	outputDir := t.TempDir()
+	opts := &options{
+		DriverRoot:             "/run/nvidia/driver",
+		DriverRootCtrPath:      driverRoot,
+		cdiEnabled:             true,
+		cdiOutputDir:           outputDir,
+		cdiVendor:              "management.nvidia.com",
+		cdiClass:               "gpu",
+		cdiManagementEditsOnly: true,
+		cdiPrint:               true,
+	}
+	require.NoError(t, generateCDISpec(opts, "/usr/bin/nvidia-cdi-hook"))
+
+	contents, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Empty(t, contents)
+}
+
+func TestPruneManagementSpecs(t *testing.T) {
+	testCases := []struct {
+		description       string
+		existing          []string
+		keep              []string
+		expectedRemaining []string
+	}{
+		{
+			description:       "other versions are removed",
+			existing:          []string{"management.nvidia.com-gpu-535.104.05.yaml", "management.nvidia.com-gpu-550.54.15.yaml"},
+			keep:              []string{"management.nvidia.com-gpu-550.54.15"},
+			expectedRemaining: []string{"management.nvidia.com-gpu-550.54.15.yaml"},
+		},
+		{
+			description:       "unversioned spec is removed",
+			existing:          []string{"management.nvidia.com-gpu.yaml", "management.nvidia.com-gpu-550.54.15.json"},
+			keep:              []string{"management.nvidia.com-gpu-550.54.15"},
+			expectedRemaining: []string{"management.nvidia.com-gpu-550.54.15.json"},
+		},
+		{
+			description:       "versioned specs are removed for unversioned name",
+			existing:          []string{"management.nvidia.com-gpu.yaml", "management.nvidia.com-gpu-550.54.15.yaml"},
+			keep:              []string{"management.nvidia.com-gpu"},
+			expectedRemaining: []string{"management.nvidia.com-gpu.yaml"},
+		},
+		{
+			description: "per-device specs are kept",
+			existing: []string{
+				"management.nvidia.com-gpu.yaml",
+				"management.nvidia.com-gpu-GPU-1111.yaml",
+				"management.nvidia.com-gpu-GPU-2222.yaml",
+				"management.nvidia.com-gpu-GPU-3333.yaml",
+			},
+			keep: []string{"management.nvidia.com-gpu-GPU-1111", "management.nvidia.com-gpu-GPU-2222"},
+			expectedRemaining: []string{
+				"management.nvidia.com-gpu-GPU-1111.yaml",
+				"management.nvidia.com-gpu-GPU-2222.yaml",
+			},
+		},
+		{
+			description: "other specs are kept",
+			existing: []string{
+				"management.nvidia.com-gpu-550.54.15.yaml",
+				"management.nvidia.com-gpu-535.104.05.yaml",
+				"management.nvidia.com-gpu-other.yaml",
+				"management.nvidia.com-gpus-535.104.05.yaml",
+				"nvidia.com-gpu.yaml",
+				"management.nvidia.com-gpu-535.104.05.txt",
+			},
+			keep: []string{"management.nvidia.com-gpu-550.54.15"},
+			expectedRemaining: []string{
+				"management.nvidia.com-gpu-535.104.05.txt",
+				"management.nvidia.com-gpu-550.54.15.yaml",
+				"management.nvidia.com-gpu-other.yaml",
+				"management.nvidia.com-gpus-535.104.05.yaml",
+				"nvidia.com-gpu.yaml",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			outputDir := t.TempDir()
+			for _, name := range tc.existing {
+				require.NoError(t, os.WriteFile(filepath.Join(outputDir, name), nil, 0644))
+			}
+
+			err := pruneManagementSpecs(outputDir, "management.nvidia.com", "gpu", tc.keep...)
+			require.NoError(t, err)
+
+			contents, err := os.ReadDir(outputDir)
+			require.NoError(t, err)
+			var remaining []string
+			for _, content := range contents {
+				remaining = append(remaining, content.Name())
+			}
+			require.EqualValues(t, tc.expectedRemaining, remaining)
+		})
+	}
+}
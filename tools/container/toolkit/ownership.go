@@ -0,0 +1,36 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// setOwnership sets the owner of the specified installed path to the uid and
+// gid configured for the installer. Symlinks are not followed. A uid or gid of
+// -1 leaves the corresponding value unchanged and if both are -1 no changes
+// are made.
+func (i *installer) setOwnership(path string) error {
+	if i.ownerUID < 0 && i.ownerGID < 0 {
+		return nil
+	}
+	if err := os.Lchown(path, i.ownerUID, i.ownerGID); err != nil {
+		return fmt.Errorf("failed to set ownership of %v: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOwnership(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("setting ownership requires root")
+	}
+
+	testCases := []struct {
+		description string
+		uid         int
+		gid         int
+		expectedUID uint32
+		expectedGID uint32
+	}{
+		{
+			description: "unset ownership is not changed",
+			uid:         -1,
+			gid:         -1,
+			expectedUID: 0,
+			expectedGID: 0,
+		},
+		{
+			description: "uid and gid are set",
+			uid:         1234,
+			gid:         5678,
+			expectedUID: 1234,
+			expectedGID: 5678,
+		},
+		{
+			description: "only gid is set",
+			uid:         -1,
+			gid:         5678,
+			expectedUID: 0,
+			expectedGID: 5678,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			sourceDir := t.TempDir()
+			source := filepath.Join(sourceDir, "libfoo.so.1")
+			require.NoError(t, os.WriteFile(source, nil, 0644))
+
+			toolkitRoot := t.TempDir()
+			configDir := filepath.Join(toolkitRoot, ".config", "nvidia-container-runtime")
+			// A symlink that already exists is also updated.
+			require.NoError(t, os.Symlink("libfoo.so.1", filepath.Join(toolkitRoot, "libfoo.so.existing")))
+
+			i := newInstaller(WithOwner(tc.uid, tc.gid))
+			require.NoError(t, i.createDirectories(toolkitRoot, filepath.Dir(configDir), configDir))
+			installed, err := i.installFileToFolder(toolkitRoot, source)
+			require.NoError(t, err)
+			require.NoError(t, i.installSymlink(toolkitRoot, "libfoo.so", installed))
+			require.NoError(t, i.installSymlink(toolkitRoot, "libfoo.so.existing", installed))
+			e := executable{
+				source: source,
+				target: executableTarget{
+					dotfileName: "foo.real",
+					wrapperName: "foo",
+				},
+			}
+			_, err = e.install(i, toolkitRoot)
+			require.NoError(t, err)
+
+			for _, path := range []string{
+				toolkitRoot,
+				configDir,
+				filepath.Dir(configDir),
+				filepath.Join(toolkitRoot, "libfoo.so.1"),
+				filepath.Join(toolkitRoot, "libfoo.so"),
+				filepath.Join(toolkitRoot, "libfoo.so.existing"),
+				filepath.Join(toolkitRoot, "foo.real"),
+				filepath.Join(toolkitRoot, "foo"),
+			} {
+				info, err := os.Lstat(path)
+				require.NoError(t, err)
+				stat := info.Sys().(*syscall.Stat_t)
+				require.Equal(t, tc.expectedUID, stat.Uid, path)
+				require.Equal(t, tc.expectedGID, stat.Gid, path)
+			}
+		})
+	}
+}
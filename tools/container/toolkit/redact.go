@@ -0,0 +1,101 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+const redactedConfigValue = "<redacted>"
+
+// defaultConfigRedactionPatterns defines the key patterns whose values are
+// masked when the installed config is echoed to STDOUT.
+var defaultConfigRedactionPatterns = []string{"token", "secret", "password"}
+
+// echoConfig writes the specified config to the specified writer with the
+// values of keys matching any of the specified patterns masked. The input
+// config is not modified.
+func echoConfig(w io.Writer, cfg *toml.Tree, patterns []string) error {
+	redacted, err := redactConfig(cfg, patterns)
+	if err != nil {
+		return err
+	}
+	_, err = redacted.WriteTo(w)
+	return err
+}
+
+// redactConfig returns a copy of the specified config where the values of all
+// keys containing any of the specified patterns are masked. Patterns are
+// matched case-insensitively against the last component of each key.
+func redactConfig(cfg *toml.Tree, patterns []string) (*toml.Tree, error) {
+	contents, err := cfg.ToTomlString()
+	if err != nil {
+		return nil, fmt.Errorf("error rendering config: %w", err)
+	}
+	redacted, err := toml.Load(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error copying config: %w", err)
+	}
+
+	var lowerPatterns []string
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		lowerPatterns = append(lowerPatterns, pattern)
+	}
+	if len(lowerPatterns) == 0 {
+		return redacted, nil
+	}
+
+	redactTree(redacted, lowerPatterns)
+	return redacted, nil
+}
+
+// redactTree masks the values of matching keys in the specified tree and all
+// of its subtrees.
+func redactTree(tree *toml.Tree, patterns []string) {
+	for _, key := range tree.Keys() {
+		switch value := tree.GetPath([]string{key}).(type) {
+		case *toml.Tree:
+			redactTree(value, patterns)
+		case []*toml.Tree:
+			for _, subtree := range value {
+				redactTree(subtree, patterns)
+			}
+		default:
+			if isSensitiveConfigKey(key, patterns) {
+				tree.SetPath([]string{key}, redactedConfigValue)
+			}
+		}
+	}
+}
+
+func isSensitiveConfigKey(key string, patterns []string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range patterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
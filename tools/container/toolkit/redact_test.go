@@ -0,0 +1,101 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEchoConfigRedactsSensitiveValues(t *testing.T) {
+	testCases := []struct {
+		description      string
+		patterns         []string
+		expectedRedacted []string
+		expectedVisible  []string
+	}{
+		{
+			description:      "default patterns",
+			patterns:         defaultConfigRedactionPatterns,
+			expectedRedacted: []string{"registry.auth-token", "registry.Client-Secret", "password", "nvidia-container-runtime.credentials.db-password"},
+			expectedVisible:  []string{"nvidia-container-cli.root", "nvidia-container-runtime.mode"},
+		},
+		{
+			description:      "custom patterns",
+			patterns:         []string{"ROOT"},
+			expectedRedacted: []string{"nvidia-container-cli.root"},
+			expectedVisible:  []string{"registry.auth-token", "password", "nvidia-container-runtime.mode"},
+		},
+		{
+			description:     "no patterns",
+			expectedVisible: []string{"registry.auth-token", "password", "nvidia-container-cli.root"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := toml.Load(`
+password = "hunter2"
+
+[nvidia-container-cli]
+root = "/run/nvidia/driver"
+
+[nvidia-container-runtime]
+mode = "cdi"
+
+[nvidia-container-runtime.credentials]
+db-password = "db-secret-value"
+
+[registry]
+auth-token = "abc123"
+Client-Secret = "s3cr3t"
+`)
+			require.NoError(t, err)
+
+			original, err := cfg.ToTomlString()
+			require.NoError(t, err)
+
+			var stdout bytes.Buffer
+			require.NoError(t, echoConfig(&stdout, cfg, tc.patterns))
+
+			echoed, err := toml.LoadBytes(stdout.Bytes())
+			require.NoError(t, err)
+			for _, key := range tc.expectedRedacted {
+				require.Equal(t, redactedConfigValue, echoed.Get(key), key)
+			}
+			for _, key := range tc.expectedVisible {
+				require.Equal(t, cfg.Get(key), echoed.Get(key), key)
+				require.NotEqual(t, redactedConfigValue, echoed.Get(key), key)
+			}
+
+			// The config that is written to the target file contains the real values.
+			var target bytes.Buffer
+			_, err = cfg.WriteTo(&target)
+			require.NoError(t, err)
+			written, err := toml.LoadBytes(target.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, "hunter2", written.Get("password"))
+			require.Equal(t, "abc123", written.Get("registry.auth-token"))
+			require.Equal(t, "db-secret-value", written.Get("nvidia-container-runtime.credentials.db-password"))
+
+			require.Equal(t, original, target.String())
+		})
+	}
+}
@@ -28,18 +28,20 @@ const (
 )
 
 // installContainerRuntimes sets up the NVIDIA container runtimes, copying the executables
-// and implementing the required wrapper
-func installContainerRuntimes(toolkitDir string, driverRoot string) error {
-	runtimes := operator.GetRuntimes()
-	for _, runtime := range runtimes {
+// and implementing the required wrapper. The runtimes are installed concurrently.
+func (i *installer) installContainerRuntimes(toolkitDir string, driverRoot string) error {
+	var installers []func() error
+	for _, runtime := range operator.GetRuntimes() {
 		r := newNvidiaContainerRuntimeInstaller(runtime.Path)
-
-		_, err := r.install(toolkitDir)
-		if err != nil {
-			return fmt.Errorf("error installing NVIDIA container runtime: %v", err)
-		}
+		installers = append(installers, func() error {
+			_, err := r.install(i, toolkitDir)
+			if err != nil {
+				return fmt.Errorf("error installing NVIDIA container runtime: %w", err)
+			}
+			return nil
+		})
 	}
-	return nil
+	return i.installConcurrently(installers...)
 }
 
 // newNVidiaContainerRuntimeInstaller returns a new executable installer for the NVIDIA container runtime.
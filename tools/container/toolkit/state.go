@@ -0,0 +1,114 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	installStateFilename = ".install-state"
+
+	phaseContainerLibraries = "container-libraries"
+	phaseContainerRuntimes  = "container-runtimes"
+	phaseToolkitConfig      = "toolkit-config"
+	phaseDeviceNodes        = "device-nodes"
+	phaseCDISpec            = "cdi-spec"
+)
+
+// installState records the install phases that have completed successfully.
+// This allows a failed install to be resumed without repeating the phases that
+// have already completed.
+type installState struct {
+	path      string
+	completed map[string]bool
+}
+
+// loadInstallState loads the install state from the specified toolkit root.
+// If no state has been recorded, an empty state is returned.
+func loadInstallState(toolkitRoot string) (*installState, error) {
+	s := &installState{
+		path:      filepath.Join(toolkitRoot, installStateFilename),
+		completed: make(map[string]bool),
+	}
+
+	contents, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install state: %v", err)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(contents)))
+	for scanner.Scan() {
+		phase := strings.TrimSpace(scanner.Text())
+		if phase == "" {
+			continue
+		}
+		s.completed[phase] = true
+	}
+	return s, nil
+}
+
+// run runs the specified install phase if it has not already completed. If
+// the phase completes successfully, this is recorded in the state file.
+func (s *installState) run(phase string, install func() error) error {
+	if s.completed[phase] {
+		log.Infof("Skipping completed install phase %v", phase)
+		return nil
+	}
+	if err := install(); err != nil {
+		return err
+	}
+	if err := s.markComplete(phase); err != nil {
+		log.Warningf("Failed to record completion of install phase %v: %v", phase, err)
+	}
+	return nil
+}
+
+// markComplete records the specified phase as having completed.
+func (s *installState) markComplete(phase string) error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, phase); err != nil {
+		return err
+	}
+	s.completed[phase] = true
+	return nil
+}
+
+// clear removes the recorded install state.
+func (s *installState) clear() error {
+	err := os.Remove(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	s.completed = make(map[string]bool)
+	return nil
+}
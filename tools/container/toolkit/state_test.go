@@ -0,0 +1,58 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallStateResume(t *testing.T) {
+	toolkitRoot := t.TempDir()
+
+	state, err := loadInstallState(toolkitRoot)
+	require.NoError(t, err)
+
+	var calls []string
+	phase := func(name string, err error) func() error {
+		return func() error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+
+	require.NoError(t, state.run(phaseContainerLibraries, phase(phaseContainerLibraries, nil)))
+	require.NoError(t, state.run(phaseContainerRuntimes, phase(phaseContainerRuntimes, nil)))
+	require.Error(t, state.run(phaseCDISpec, phase(phaseCDISpec, errors.New("failed"))))
+
+	resumed, err := loadInstallState(toolkitRoot)
+	require.NoError(t, err)
+
+	calls = nil
+	require.NoError(t, resumed.run(phaseContainerLibraries, phase(phaseContainerLibraries, nil)))
+	require.NoError(t, resumed.run(phaseContainerRuntimes, phase(phaseContainerRuntimes, nil)))
+	require.NoError(t, resumed.run(phaseCDISpec, phase(phaseCDISpec, nil)))
+	require.Equal(t, []string{phaseCDISpec}, calls)
+
+	require.NoError(t, resumed.clear())
+	_, err = os.Stat(filepath.Join(toolkitRoot, installStateFilename))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
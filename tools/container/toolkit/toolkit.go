@@ -22,18 +22,23 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	toml "github.com/pelletier/go-toml"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
-	"tags.cncf.io/container-device-interface/pkg/cdi"
 	"tags.cncf.io/container-device-interface/pkg/parser"
 
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/config"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/logger"
+	"github.com/NVIDIA/nvidia-container-toolkit/internal/lookup/root"
 	"github.com/NVIDIA/nvidia-container-toolkit/internal/system/nvdevices"
 	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi"
-	transformroot "github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/transform/root"
+	"github.com/NVIDIA/nvidia-container-toolkit/pkg/nvcdi/spec"
 )
 
 const (
@@ -55,6 +60,8 @@ type options struct {
 	DriverRootCtrPath string
 	DevRootCtrPath    string
 
+	resolveDriverRoot bool
+
 	ContainerRuntimeMode     string
 	ContainerRuntimeDebug    string
 	ContainerRuntimeLogLevel string
@@ -69,13 +76,47 @@ type options struct {
 	ContainerCLIDebug string
 	toolkitRoot       string
 
+	toolkitConfigSource   string
+	additionalConfigPaths cli.StringSlice
+	configRedactKeys      cli.StringSlice
+
 	cdiEnabled   bool
 	cdiOutputDir string
 	cdiKind      string
 	cdiVendor    string
 	cdiClass     string
 
+	cdiManagementEditsOnly bool
+	cdiVersionedSpecName   bool
+	cdiPruneStaleSpecs     bool
+	cdiPrint               bool
+	cdiExtraEdits          string
+	cdiPerDeviceSpecs      bool
+	cdiDevices             string
+	cdiDeviceIndices       []int
+	skipCDIKindValidation  bool
+
 	createDeviceNodes cli.StringSlice
+	deviceMinors      cli.Int64Slice
+
+	preserve cli.StringSlice
+
+	installConcurrency int
+	resumeInstall      bool
+	printConfig        bool
+	printDiff          bool
+	logFormat          string
+	force              bool
+
+	copyRetries      int
+	copyRetryBackoff time.Duration
+
+	failOnSymlinkConflict bool
+
+	libraryArch string
+
+	ownerUID int
+	ownerGID int
 
 	acceptNVIDIAVisibleDevicesWhenUnprivileged bool
 	acceptNVIDIAVisibleDevicesAsVolumeMounts   bool
@@ -99,6 +140,9 @@ func main() {
 	install.Usage = "Install the components of the NVIDIA container toolkit"
 	install.ArgsUsage = "<toolkit_directory>"
 	install.Before = func(c *cli.Context) error {
+		if err := setLogFormat(c, &opts); err != nil {
+			return err
+		}
 		return validateOptions(c, &opts)
 	}
 	install.Action = func(c *cli.Context) error {
@@ -111,6 +155,9 @@ func main() {
 	delete.Usage = "Delete the NVIDIA container toolkit"
 	delete.ArgsUsage = "<toolkit_directory>"
 	delete.Before = func(c *cli.Context) error {
+		if err := setLogFormat(c, &opts); err != nil {
+			return err
+		}
 		return validateOptions(c, &opts)
 	}
 	delete.Action = func(c *cli.Context) error {
@@ -137,9 +184,15 @@ func main() {
 			Destination: &opts.DriverRootCtrPath,
 			EnvVars:     []string{"DRIVER_ROOT_CTR_PATH"},
 		},
+		&cli.BoolFlag{
+			Name:        "resolve-driver-root",
+			Usage:       "resolve the driver-root if it is a symlink and use the resolved path in the generated config. This ensures that the config does not change if the symlink is updated.",
+			Destination: &opts.resolveDriverRoot,
+			EnvVars:     []string{"RESOLVE_DRIVER_ROOT"},
+		},
 		&cli.StringFlag{
 			Name:        "dev-root",
-			Usage:       "Specify the root where `/dev` is located. If this is not specified, the driver-root is assumed.",
+			Usage:       "Specify the root where `/dev` is located. If this is not specified, the driver-root is assumed. If this is set to 'auto', the driver-root and / are searched for /dev/nvidiactl and the first root where it is found is used. In this case the dev-root-ctr-path is set to the path of the detected root in the container.",
 			Destination: &opts.DevRoot,
 			EnvVars:     []string{"NVIDIA_DEV_ROOT", "DEV_ROOT"},
 		},
@@ -165,6 +218,7 @@ func main() {
 		&cli.StringFlag{
 			Name:        "nvidia-container-runtime.mode",
 			Aliases:     []string{"nvidia-container-runtime-mode"},
+			Usage:       "the mode of the NVIDIA Container Runtime. One of 'auto', 'legacy', 'csv', 'cdi', or 'none'. In 'none' mode no GPU injection is performed.",
 			Destination: &opts.ContainerRuntimeMode,
 			EnvVars:     []string{"NVIDIA_CONTAINER_RUNTIME_MODE"},
 		},
@@ -216,6 +270,31 @@ func main() {
 			Destination: &opts.toolkitRoot,
 			EnvVars:     []string{"TOOLKIT_ROOT"},
 		},
+		&cli.StringFlag{
+			Name:        "config-source",
+			Usage:       "The path to the config file that is used as the base for the installed toolkit config. If this is '-' the config is read from STDIN.",
+			Value:       nvidiaContainerToolkitConfigSource,
+			Destination: &opts.toolkitConfigSource,
+			EnvVars:     []string{"TOOLKIT_CONFIG_SOURCE"},
+		},
+		&cli.StringFlag{
+			Name:    envFileFlagName,
+			Usage:   "The path to a file containing KEY=VALUE lines that are applied as environment variables before the flags are resolved. Variables that are already set in the environment take precedence.",
+			EnvVars: []string{"ENV_FILE"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "additional-config-path",
+			Usage:       "Additional paths to which the installed toolkit config is written. This can be specified multiple times. If writing to any of these paths fails, the configs written to the other additional paths are restored.",
+			Destination: &opts.additionalConfigPaths,
+			EnvVars:     []string{"ADDITIONAL_CONFIG_PATHS"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "config-redact-key",
+			Usage:       "Patterns for config keys whose values are masked when the installed config is echoed to STDOUT. A key matches if it contains any of the patterns (case-insensitive). The installed config files are not affected.",
+			Value:       cli.NewStringSlice(defaultConfigRedactionPatterns...),
+			Destination: &opts.configRedactKeys,
+			EnvVars:     []string{"CONFIG_REDACT_KEYS"},
+		},
 		&cli.BoolFlag{
 			Name:        "cdi-enabled",
 			Aliases:     []string{"enable-cdi"},
@@ -237,6 +316,57 @@ func main() {
 			Destination: &opts.cdiKind,
 			EnvVars:     []string{"CDI_KIND"},
 		},
+		&cli.BoolFlag{
+			Name:        "cdi-management-edits-only",
+			Usage:       "(Only applicable with --cdi-enabled) generate a CDI specification for management containers that includes the driver mounts and hooks as common edits and no devices",
+			Destination: &opts.cdiManagementEditsOnly,
+			EnvVars:     []string{"CDI_MANAGEMENT_EDITS_ONLY"},
+		},
+		&cli.BoolFlag{
+			Name:        "cdi-versioned-spec-name",
+			Usage:       "(Only applicable with --cdi-enabled) include the driver version in the filename of the generated CDI specification for management containers",
+			Destination: &opts.cdiVersionedSpecName,
+			EnvVars:     []string{"CDI_VERSIONED_SPEC_NAME"},
+		},
+		&cli.BoolFlag{
+			Name:        "cdi-prune-stale-specs",
+			Usage:       "(Only applicable with --cdi-enabled) remove CDI specifications for management containers with the same vendor and class but a different filename from the output directory",
+			Destination: &opts.cdiPruneStaleSpecs,
+			EnvVars:     []string{"CDI_PRUNE_STALE_SPECS"},
+		},
+		&cli.BoolFlag{
+			Name:        "cdi-print",
+			Usage:       "(Only applicable with --cdi-enabled) print the generated CDI specification for management containers to STDOUT instead of saving it to the output directory",
+			Destination: &opts.cdiPrint,
+			EnvVars:     []string{"CDI_PRINT"},
+		},
+		&cli.BoolFlag{
+			Name:        "cdi-per-device-specs",
+			Usage:       "(Only applicable with --cdi-enabled) generate a CDI specification for management containers per GPU instead of a single specification. The device in each specification is named by the UUID of the GPU.",
+			Destination: &opts.cdiPerDeviceSpecs,
+			EnvVars:     []string{"CDI_PER_DEVICE_SPECS"},
+		},
+		&cli.StringFlag{
+			Name:        "cdi-devices",
+			Aliases:     []string{"devices"},
+			Usage:       "(Only applicable with --cdi-per-device-specs) a comma-separated list of the indices of the GPUs for which CDI specifications are generated, or 'all' for all GPUs.",
+			Value:       "all",
+			Destination: &opts.cdiDevices,
+			EnvVars:     []string{"CDI_DEVICES"},
+		},
+		&cli.StringFlag{
+			Name:        "cdi-extra-edits",
+			Aliases:     []string{"extra-edits"},
+			Usage:       "(Only applicable with --cdi-enabled) the path to a partial CDI specification whose containerEdits are merged into the common edits of the generated CDI specification for management containers",
+			Destination: &opts.cdiExtraEdits,
+			EnvVars:     []string{"CDI_EXTRA_EDITS"},
+		},
+		&cli.BoolFlag{
+			Name:        "skip-cdi-kind-validation",
+			Usage:       "skip the validation of the vendor and class of the CDI kind. Validation errors are logged as warnings instead. This is used for experimental purposes only.",
+			Hidden:      true,
+			Destination: &opts.skipCDIKindValidation,
+		},
 		&cli.BoolFlag{
 			Name:        "ignore-errors",
 			Usage:       "ignore errors when installing the NVIDIA Container toolkit. This is used for testing purposes only.",
@@ -245,67 +375,333 @@ func main() {
 		},
 		&cli.StringSliceFlag{
 			Name:        "create-device-nodes",
-			Usage:       "(Only applicable with --cdi-enabled) specifies which device nodes should be created. If any one of the options is set to '' or 'none', no device nodes will be created.",
+			Usage:       "specifies which device nodes should be created. If any one of the options is set to '' or 'none', no device nodes will be created. If this is not explicitly set, device nodes are only created if --cdi-enabled is set. An explicit value is applied independently of --cdi-enabled.",
 			Value:       cli.NewStringSlice("control"),
 			Destination: &opts.createDeviceNodes,
 			EnvVars:     []string{"CREATE_DEVICE_NODES"},
 		},
+		&cli.Int64SliceFlag{
+			Name:        "device-minors",
+			Usage:       "(Only applicable with --create-device-nodes) restrict the device nodes that are created to those with the specified minor numbers. If this is not specified, all device nodes for the selected modes are created.",
+			Destination: &opts.deviceMinors,
+			EnvVars:     []string{"DEVICE_MINORS"},
+		},
+		&cli.IntFlag{
+			Name:        "install-concurrency",
+			Usage:       "the maximum number of files to install concurrently. If this is not specified, GOMAXPROCS is used.",
+			Destination: &opts.installConcurrency,
+			EnvVars:     []string{"INSTALL_CONCURRENCY"},
+		},
+		&cli.IntFlag{
+			Name:        "copy-retries",
+			Usage:       "the number of times that copying a file is retried if a transient error (such as EIO or ESTALE on NFS-backed filesystems) occurs",
+			Destination: &opts.copyRetries,
+			EnvVars:     []string{"COPY_RETRIES"},
+		},
+		&cli.DurationFlag{
+			Name:        "copy-retry-backoff",
+			Usage:       "the initial delay before retrying a failed copy. The delay is doubled for each subsequent retry.",
+			Value:       time.Second,
+			Destination: &opts.copyRetryBackoff,
+			EnvVars:     []string{"COPY_RETRY_BACKOFF"},
+		},
+		&cli.BoolFlag{
+			Name:        "fail-on-symlink-conflict",
+			Usage:       "fail the install if a file or a symlink to a different target exists where a symlink is to be installed instead of replacing it.",
+			Destination: &opts.failOnSymlinkConflict,
+			EnvVars:     []string{"FAIL_ON_SYMLINK_CONFLICT"},
+		},
+		&cli.StringFlag{
+			Name:        "library-arch",
+			Usage:       "the architecture whose library directories are searched first when locating the NVIDIA container libraries. One of [auto | amd64 | arm64]. For auto, the architecture of the toolkit binary is used. The directories for other architectures are searched as fallbacks.",
+			Value:       "auto",
+			Destination: &opts.libraryArch,
+			EnvVars:     []string{"LIBRARY_ARCH"},
+		},
+		&cli.IntFlag{
+			Name:        "owner-uid",
+			Usage:       "the uid to set as the owner of the installed toolkit files. If this is -1 the owner is not changed.",
+			Value:       -1,
+			Destination: &opts.ownerUID,
+			EnvVars:     []string{"TOOLKIT_OWNER_UID"},
+		},
+		&cli.IntFlag{
+			Name:        "owner-gid",
+			Usage:       "the gid to set as the group of the installed toolkit files. If this is -1 the group is not changed.",
+			Value:       -1,
+			Destination: &opts.ownerGID,
+			EnvVars:     []string{"TOOLKIT_OWNER_GID"},
+		},
+		&cli.StringSliceFlag{
+			Name:        "preserve",
+			Usage:       "specify the names (or glob patterns) of files in the toolkit directory that should be preserved when the toolkit is deleted in addition to the toolkit.pid file. This flag can be specified multiple times.",
+			Destination: &opts.preserve,
+			EnvVars:     []string{"TOOLKIT_PRESERVE"},
+		},
+		&cli.BoolFlag{
+			Name:        "resume-install",
+			Usage:       "resume a previously failed install instead of removing the existing installation. Install phases that have already completed are skipped and files that are unchanged are not copied again.",
+			Destination: &opts.resumeInstall,
+			EnvVars:     []string{"RESUME_INSTALL"},
+		},
+		&cli.BoolFlag{
+			Name:        "force",
+			Usage:       "install the toolkit even if the toolkit directory is locked by another process through its toolkit.pid file.",
+			Destination: &opts.force,
+			EnvVars:     []string{"FORCE_INSTALL"},
+		},
+		&cli.StringFlag{
+			Name:        "log-format",
+			Usage:       "the format of the log output. One of [text | json]. For json, the component and operation are included as structured keys.",
+			Value:       logger.FormatText,
+			Destination: &opts.logFormat,
+			EnvVars:     []string{"LOG_FORMAT"},
+		},
 	}
 
 	// Update the subcommand flags with the common subcommand flags
 	install.Flags = append([]cli.Flag{}, flags...)
 	delete.Flags = append([]cli.Flag{}, flags...)
 
+	// Add the flags that only apply to the install subcommand
+	install.Flags = append(install.Flags,
+		&cli.BoolFlag{
+			Name:        "print-config",
+			Usage:       "print the toolkit config that would be installed for the specified options to STDOUT and exit. No files are installed and no device nodes or CDI specifications are created.",
+			Destination: &opts.printConfig,
+		},
+		&cli.BoolFlag{
+			Name:        "diff",
+			Usage:       "print the toolkit artifacts that would be added, removed, or changed in the toolkit root by an install to STDOUT and exit. Files are compared by name, size, and checksum. No files are installed.",
+			Destination: &opts.printDiff,
+		},
+	)
+
+	// Apply the env file before running the CLI so that the values are used
+	// when resolving the envvars associated with the flags.
+	if err := applyEnvFile(getEnvFilePath(os.Args[1:])); err != nil {
+		log.Fatal(fmt.Errorf("error: %v", err))
+	}
+
 	// Run the top-level CLI
 	if err := c.Run(os.Args); err != nil {
 		log.Fatal(fmt.Errorf("error: %v", err))
 	}
 }
 
+// supportedRuntimeModes are the supported modes of the NVIDIA Container
+// Runtime. In "none" mode, no GPU injection is performed.
+var supportedRuntimeModes = []string{"auto", "legacy", "csv", "cdi", "none"}
+
+// validateRuntimeMode checks whether the specified runtime mode is supported.
+// An empty mode is valid and indicates that the default is used.
+func validateRuntimeMode(mode string) error {
+	if mode == "" {
+		return nil
+	}
+	for _, supported := range supportedRuntimeModes {
+		if mode == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported mode %q; expected one of %v", mode, strings.Join(supportedRuntimeModes, ", "))
+}
+
+// setLogFormat sets the format of the log output for the specified command.
+func setLogFormat(c *cli.Context, opts *options) error {
+	fields := log.Fields{
+		"component": "toolkit",
+		"operation": c.Command.Name,
+	}
+	if err := logger.SetFormat(log.StandardLogger(), opts.logFormat, fields); err != nil {
+		return fmt.Errorf("invalid --log-format option: %v", err)
+	}
+	return nil
+}
+
 // validateOptions checks whether the specified options are valid
 func validateOptions(c *cli.Context, opts *options) error {
+	var errs []error
 	if opts.toolkitRoot == "" {
-		return fmt.Errorf("invalid --toolkit-root option: %v", opts.toolkitRoot)
+		errs = append(errs, fmt.Errorf("invalid --toolkit-root option: %v", opts.toolkitRoot))
+	}
+
+	opts.createDeviceNodes = splitCommaSeparated(opts.createDeviceNodes)
+	opts.ContainerRuntimeRuntimes = splitCommaSeparated(opts.ContainerRuntimeRuntimes)
+	opts.additionalConfigPaths = splitCommaSeparated(opts.additionalConfigPaths)
+	opts.configRedactKeys = splitCommaSeparated(opts.configRedactKeys)
+	opts.ContainerRuntimeModesCDIAnnotationPrefixes = splitCommaSeparated(opts.ContainerRuntimeModesCDIAnnotationPrefixes)
+
+	if opts.ownerUID < -1 {
+		errs = append(errs, fmt.Errorf("invalid --owner-uid option: %v", opts.ownerUID))
+	}
+	if opts.ownerGID < -1 {
+		errs = append(errs, fmt.Errorf("invalid --owner-gid option: %v", opts.ownerGID))
 	}
 
 	vendor, class := parser.ParseQualifier(opts.cdiKind)
+	var kindErrs []error
 	if err := parser.ValidateVendorName(vendor); err != nil {
-		return fmt.Errorf("invalid CDI vendor name: %v", err)
+		kindErrs = append(kindErrs, fmt.Errorf("invalid CDI vendor name: %v", err))
 	}
 	if err := parser.ValidateClassName(class); err != nil {
-		return fmt.Errorf("invalid CDI class name: %v", err)
+		kindErrs = append(kindErrs, fmt.Errorf("invalid CDI class name: %v", err))
+	}
+	if opts.skipCDIKindValidation {
+		for _, err := range kindErrs {
+			log.Warningf("Ignoring CDI kind validation error since --skip-cdi-kind-validation is set; the generated CDI spec may not be usable: %v", err)
+		}
+		kindErrs = nil
+	}
+	if len(kindErrs) == 0 {
+		opts.cdiVendor = vendor
+		opts.cdiClass = class
+	}
+	errs = append(errs, kindErrs...)
+
+	if err := validateLibraryArch(opts.libraryArch); err != nil {
+		errs = append(errs, fmt.Errorf("invalid --library-arch option: %v", err))
+	}
+
+	if err := validateRuntimeMode(opts.ContainerRuntimeMode); err != nil {
+		errs = append(errs, fmt.Errorf("invalid --nvidia-container-runtime.mode option: %v", err))
+	}
+
+	for _, prefix := range opts.ContainerRuntimeModesCDIAnnotationPrefixes.Value() {
+		if err := validateAnnotationPrefix(prefix); err != nil {
+			errs = append(errs, fmt.Errorf("invalid CDI annotation prefix: %v", err))
+		}
+	}
+
+	deviceIndices, err := parseDeviceIndices(opts.cdiDevices)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("invalid --cdi-devices option: %v", err))
+	} else if len(deviceIndices) > 0 && !opts.cdiPerDeviceSpecs {
+		errs = append(errs, fmt.Errorf("--cdi-devices requires --cdi-per-device-specs"))
+	} else {
+		opts.cdiDeviceIndices = deviceIndices
+	}
+	if opts.cdiManagementEditsOnly && opts.cdiPerDeviceSpecs {
+		errs = append(errs, fmt.Errorf("--cdi-management-edits-only cannot be used with --cdi-per-device-specs"))
+	}
+
+	if opts.DevRoot == devRootAuto {
+		opts.DevRoot, opts.DevRootCtrPath = detectDevRoot(opts.DriverRoot, opts.DriverRootCtrPath, newControlDeviceLocator)
 	}
-	opts.cdiVendor = vendor
-	opts.cdiClass = class
 
 	if opts.cdiEnabled && opts.cdiOutputDir == "" {
 		log.Warning("Skipping CDI spec generation (no output directory specified)")
 		opts.cdiEnabled = false
 	}
 
-	isDisabled := false
-	for _, mode := range opts.createDeviceNodes.Value() {
+	explicitDeviceNodes := c != nil && c.IsSet("create-device-nodes")
+	modes, err := getDeviceNodeModes(opts.createDeviceNodes.Value(), explicitDeviceNodes, opts.cdiEnabled)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		opts.createDeviceNodes = *cli.NewStringSlice(modes...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// getDeviceNodeModes returns the device node modes to create. If any mode is
+// empty or 'none', no device nodes are created. Device node creation is
+// independent of CDI spec generation if the modes are explicitly specified.
+// Otherwise the default modes are only used if CDI spec generation is enabled.
+func getDeviceNodeModes(modes []string, explicit bool, cdiEnabled bool) ([]string, error) {
+	for _, mode := range modes {
 		if mode != "" && mode != "none" && mode != "control" {
-			return fmt.Errorf("invalid --create-device-nodes value: %v", mode)
+			return nil, fmt.Errorf("invalid --create-device-nodes value: %v", mode)
 		}
 		if mode == "" || mode == "none" {
-			isDisabled = true
-			break
+			return nil, nil
 		}
 	}
-	if !opts.cdiEnabled && !isDisabled {
-		log.Info("disabling device node creation since --cdi-enabled=false")
-		isDisabled = true
+	if !explicit && !cdiEnabled {
+		log.Info("disabling device node creation since --cdi-enabled=false and --create-device-nodes is not set")
+		return nil, nil
 	}
-	if isDisabled {
-		opts.createDeviceNodes = *cli.NewStringSlice()
+	return modes, nil
+}
+
+// splitCommaSeparated splits each element of the specified slice on commas so
+// that comma-separated values are handled in the same way as repeated flags.
+// This is required for values that are not set through the command line, for
+// example when the options are constructed directly. Empty elements are kept
+// so that an empty value retains its meaning.
+func splitCommaSeparated(s cli.StringSlice) cli.StringSlice {
+	if len(s.Value()) == 0 {
+		return s
+	}
+	var values []string
+	for _, value := range s.Value() {
+		for _, v := range strings.Split(value, ",") {
+			values = append(values, strings.TrimSpace(v))
+		}
 	}
+	return *cli.NewStringSlice(values...)
+}
 
+// parseDeviceIndices parses a comma-separated list of device indices. If the
+// value is empty or 'all', nil is returned to indicate that all devices are
+// selected. Duplicate indices are removed.
+func parseDeviceIndices(value string) ([]int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "all" {
+		return nil, nil
+	}
+	seen := make(map[int]bool)
+	var indices []int
+	for _, field := range strings.Split(value, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("%q is not a valid device index", strings.TrimSpace(field))
+		}
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+		indices = append(indices, index)
+	}
+	return indices, nil
+}
+
+// validateAnnotationPrefix checks whether the specified prefix is a valid
+// prefix for CDI annotation keys. A prefix consists of a DNS subdomain
+// followed by a trailing '/' as is the case for the default cdi.k8s.io/.
+func validateAnnotationPrefix(prefix string) error {
+	domain, found := strings.CutSuffix(prefix, "/")
+	if !found {
+		return fmt.Errorf("%q does not end with '/'", prefix)
+	}
+	if domain == "" {
+		return fmt.Errorf("%q has an empty domain", prefix)
+	}
+	if len(domain) > 253 {
+		return fmt.Errorf("%q has a domain longer than 253 characters", prefix)
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" {
+			return fmt.Errorf("%q contains an empty domain label", prefix)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("%q contains a domain label %q that starts or ends with '-'", prefix, label)
+		}
+		for _, c := range label {
+			if !parser.IsAlphaNumeric(c) && c != '-' {
+				return fmt.Errorf("%q contains invalid character %q", prefix, c)
+			}
+		}
+	}
 	return nil
 }
 
 // TryDelete attempts to remove the specified toolkit folder.
-// A toolkit.pid file -- if present -- is skipped.
+// The toolkit.pid file and files matching the configured preserve patterns are
+// skipped. The toolkit folder itself is removed if it is empty once the other
+// files have been removed. All removals are attempted and any errors are
+// returned together unless errors are being ignored.
 func TryDelete(cli *cli.Context, opts *options) error {
 	log.Infof("Attempting to delete NVIDIA container toolkit from '%v'", opts.toolkitRoot)
 
@@ -316,143 +712,317 @@ func TryDelete(cli *cli.Context, opts *options) error {
 		return fmt.Errorf("failed to read the contents of %v: %w", opts.toolkitRoot, err)
 	}
 
+	// The toolkit.pid file records the ownership of the toolkit directory and
+	// is always preserved.
+	preserve := append([]string{toolkitPidFilename}, opts.preserve.Value()...)
+
+	var errs []error
 	for _, content := range contents {
-		if content.Name() == toolkitPidFilename {
+		if isPreserved(content.Name(), preserve) {
+			log.Infof("Preserving %v", content.Name())
 			continue
 		}
 		name := filepath.Join(opts.toolkitRoot, content.Name())
 		if err := os.RemoveAll(name); err != nil {
-			log.Warningf("could not remove %v: %v", name, err)
+			errs = append(errs, fmt.Errorf("could not remove %v: %w", name, err))
 		}
 	}
-	if err := os.RemoveAll(opts.toolkitRoot); err != nil {
-		log.Warningf("could not remove %v: %v", opts.toolkitRoot, err)
+	if err := removeIfEmpty(opts.toolkitRoot); err != nil {
+		errs = append(errs, fmt.Errorf("could not remove %v: %w", opts.toolkitRoot, err))
 	}
-	return nil
+
+	err = errors.Join(errs...)
+	if err != nil && opts.ignoreErrors {
+		log.Warningf("Ignoring error: %v", err)
+		return nil
+	}
+	return err
+}
+
+// removeIfEmpty removes the specified directory if it is empty.
+func removeIfEmpty(dir string) error {
+	contents, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	if len(contents) > 0 {
+		return nil
+	}
+	return os.Remove(dir)
+}
+
+// isPreserved checks whether the specified filename matches any of the
+// specified names or glob patterns.
+func isPreserved(filename string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if match, _ := filepath.Match(pattern, filename); match {
+			return true
+		}
+	}
+	return false
 }
 
 // Install installs the components of the NVIDIA container toolkit.
-// Any existing installation is removed.
+// Any existing installation is removed unless the install is being resumed.
 func Install(cli *cli.Context, opts *options) error {
+	if opts.printConfig {
+		return printToolkitConfig(cli, opts, os.Stdout)
+	}
+	if opts.printDiff {
+		return printToolkitDiff(opts.toolkitRoot, opts.libraryArch, os.Stdout)
+	}
+
 	log.Infof("Installing NVIDIA container toolkit to '%v'", opts.toolkitRoot)
 
-	log.Infof("Removing existing NVIDIA container toolkit installation")
-	err := os.RemoveAll(opts.toolkitRoot)
+	i := newInstaller(
+		WithFileInstaller(
+			newRetryingFileInstaller(
+				fileInstallerFunc(copyFile),
+				WithRetries(opts.copyRetries, opts.copyRetryBackoff),
+			),
+		),
+		WithReplaceConflictingSymlinks(!opts.failOnSymlinkConflict),
+		WithLibraryArch(opts.libraryArch),
+		WithOwner(opts.ownerUID, opts.ownerGID),
+		WithConcurrency(opts.installConcurrency),
+	)
+
+	lock, err := acquireInstallLock(opts.toolkitRoot, opts.force)
+	if err != nil {
+		return fmt.Errorf("error locking toolkit directory: %w", err)
+	}
+	defer func() {
+		if err := lock.release(); err != nil {
+			log.Warningf("Failed to release toolkit directory: %v", err)
+		}
+	}()
+
+	state, err := getInstallState(opts)
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error removing toolkit directory: %v", err)
+		return err
 	} else if err != nil {
-		log.Errorf("Ignoring error: %v", fmt.Errorf("error removing toolkit directory: %v", err))
+		log.Errorf("Ignoring error: %v", err)
+		state = &installState{
+			path:      filepath.Join(opts.toolkitRoot, installStateFilename),
+			completed: make(map[string]bool),
+		}
 	}
 
 	toolkitConfigDir := filepath.Join(opts.toolkitRoot, ".config", "nvidia-container-runtime")
 	toolkitConfigPath := filepath.Join(toolkitConfigDir, configFilename)
 
-	err = createDirectories(opts.toolkitRoot, toolkitConfigDir)
+	err = i.createDirectories(opts.toolkitRoot, filepath.Dir(toolkitConfigDir), toolkitConfigDir)
 	if err != nil && !opts.ignoreErrors {
 		return fmt.Errorf("could not create required directories: %v", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("could not create required directories: %v", err))
 	}
 
-	err = installContainerLibraries(opts.toolkitRoot)
+	err = state.run(phaseContainerLibraries, func() error {
+		return i.installContainerLibraries(opts.toolkitRoot)
+	})
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA container library: %v", err)
+		return fmt.Errorf("error installing NVIDIA container library: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA container library: %v", err))
 	}
 
-	err = installContainerRuntimes(opts.toolkitRoot, opts.DriverRoot)
+	err = state.run(phaseContainerRuntimes, func() error {
+		return i.installContainerRuntimes(opts.toolkitRoot, opts.DriverRoot)
+	})
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA container runtime: %v", err)
+		return fmt.Errorf("error installing NVIDIA container runtime: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA container runtime: %v", err))
 	}
 
-	nvidiaContainerCliExecutable, err := installContainerCLI(opts.toolkitRoot)
+	nvidiaContainerCliExecutable, err := i.installContainerCLI(opts.toolkitRoot)
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA container CLI: %v", err)
+		return fmt.Errorf("error installing NVIDIA container CLI: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA container CLI: %v", err))
 	}
+	if err == nil {
+		checkContainerCLIVersion(nvidiaContainerCliExecutable)
+	}
 
-	nvidiaContainerRuntimeHookPath, err := installRuntimeHook(opts.toolkitRoot, toolkitConfigPath)
+	nvidiaContainerRuntimeHookPath, err := i.installRuntimeHook(opts.toolkitRoot, toolkitConfigPath)
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA container runtime hook: %v", err)
+		return fmt.Errorf("error installing NVIDIA container runtime hook: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA container runtime hook: %v", err))
 	}
 
-	nvidiaCTKPath, err := installContainerToolkitCLI(opts.toolkitRoot)
+	nvidiaCTKPath, err := i.installContainerToolkitCLI(opts.toolkitRoot)
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA Container Toolkit CLI: %v", err)
+		return fmt.Errorf("error installing NVIDIA Container Toolkit CLI: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA Container Toolkit CLI: %v", err))
 	}
 
-	nvidiaCDIHookPath, err := installContainerCDIHookCLI(opts.toolkitRoot)
+	nvidiaCDIHookPath, err := i.installContainerCDIHookCLI(opts.toolkitRoot)
 	if err != nil && !opts.ignoreErrors {
-		return fmt.Errorf("error installing NVIDIA Container CDI Hook CLI: %v", err)
+		return fmt.Errorf("error installing NVIDIA Container CDI Hook CLI: %w", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA Container CDI Hook CLI: %v", err))
 	}
 
-	err = installToolkitConfig(cli, toolkitConfigPath, nvidiaContainerCliExecutable, nvidiaCTKPath, nvidiaContainerRuntimeHookPath, opts)
+	err = state.run(phaseToolkitConfig, func() error {
+		return i.installToolkitConfig(cli, toolkitConfigPath, nvidiaContainerCliExecutable, nvidiaCTKPath, nvidiaContainerRuntimeHookPath, opts)
+	})
 	if err != nil && !opts.ignoreErrors {
 		return fmt.Errorf("error installing NVIDIA container toolkit config: %v", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error installing NVIDIA container toolkit config: %v", err))
 	}
 
-	err = createDeviceNodes(opts)
+	err = state.run(phaseDeviceNodes, func() error {
+		return createDeviceNodes(opts)
+	})
 	if err != nil && !opts.ignoreErrors {
 		return fmt.Errorf("error creating device nodes: %v", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error creating device nodes: %v", err))
 	}
 
-	err = generateCDISpec(opts, nvidiaCDIHookPath)
+	err = state.run(phaseCDISpec, func() error {
+		return generateCDISpec(opts, nvidiaCDIHookPath)
+	})
 	if err != nil && !opts.ignoreErrors {
 		return fmt.Errorf("error generating CDI specification: %v", err)
 	} else if err != nil {
 		log.Errorf("Ignoring error: %v", fmt.Errorf("error generating CDI specification: %v", err))
 	}
 
+	if err := state.clear(); err != nil {
+		log.Warningf("Failed to remove install state: %v", err)
+	}
+
+	return nil
+}
+
+// getInstallState returns the state used to track the progress of an install.
+// If the install is being resumed, the previously recorded state is loaded and
+// the existing installation is kept. Otherwise the existing installation is
+// removed.
+func getInstallState(opts *options) (*installState, error) {
+	if opts.resumeInstall {
+		log.Infof("Resuming NVIDIA container toolkit installation")
+		return loadInstallState(opts.toolkitRoot)
+	}
+
+	log.Infof("Removing existing NVIDIA container toolkit installation")
+	if err := removeInstallation(opts.toolkitRoot); err != nil {
+		return nil, fmt.Errorf("error removing toolkit directory: %v", err)
+	}
+	return loadInstallState(opts.toolkitRoot)
+}
+
+// removeInstallation removes the contents of the specified toolkit directory.
+// The toolkit.pid file is kept since this records the ownership of the
+// directory for the duration of the install.
+func removeInstallation(toolkitRoot string) error {
+	contents, err := os.ReadDir(toolkitRoot)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, content := range contents {
+		if content.Name() == toolkitPidFilename {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(toolkitRoot, content.Name())); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// containerLibraries lists the NVIDIA container libraries that are installed
+// to the toolkit root.
+var containerLibraries = []string{
+	"libnvidia-container.so.1",
+	"libnvidia-container-go.so.1",
+}
+
 // installContainerLibraries locates and installs the libraries that are part of
 // the nvidia-container-toolkit.
 // A predefined set of library candidates are considered, with the first one
 // resulting in success being installed to the toolkit folder. The install process
 // resolves the symlink for the library and copies the versioned library itself.
-func installContainerLibraries(toolkitRoot string) error {
+// The libraries are installed concurrently.
+func (i *installer) installContainerLibraries(toolkitRoot string) error {
 	log.Infof("Installing NVIDIA container library to '%v'", toolkitRoot)
 
-	libs := []string{
-		"libnvidia-container.so.1",
-		"libnvidia-container-go.so.1",
+	var installers []func() error
+	for _, l := range containerLibraries {
+		l := l
+		installers = append(installers, func() error {
+			err := i.installLibrary(l, toolkitRoot)
+			if err != nil {
+				return fmt.Errorf("failed to install %s: %w", l, err)
+			}
+			return nil
+		})
 	}
 
-	for _, l := range libs {
-		err := installLibrary(l, toolkitRoot)
-		if err != nil {
-			return fmt.Errorf("failed to install %s: %v", l, err)
-		}
+	return i.installConcurrently(installers...)
+}
+
+// installConcurrently runs the specified installers using a pool of at most
+// i.concurrency workers. If this is less than 1, GOMAXPROCS is used.
+// All installers are run and the errors encountered are joined.
+// Note that an installer is run to completion by a single worker, meaning that
+// steps that depend on each other (e.g. creating a symlink to an installed
+// file) should be performed by the same installer.
+func (i *installer) installConcurrently(installers ...func() error) error {
+	concurrency := i.concurrency
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var errs []error
+
+	workers := make(chan struct{}, concurrency)
+	for _, install := range installers {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(install func() error) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			if err := install(); err != nil {
+				lock.Lock()
+				defer lock.Unlock()
+				errs = append(errs, err)
+			}
+		}(install)
 	}
+	wg.Wait()
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // installLibrary installs the specified library to the toolkit directory.
-func installLibrary(libName string, toolkitRoot string) error {
-	libraryPath, err := findLibrary("", libName)
+func (i *installer) installLibrary(libName string, toolkitRoot string) error {
+	libraryPath, err := findLibrary("", libName, i.libraryArch)
 	if err != nil {
-		return fmt.Errorf("error locating NVIDIA container library: %v", err)
+		return fmt.Errorf("error locating NVIDIA container library: %w", err)
 	}
+	return i.installLibraryFile(libraryPath, libName, toolkitRoot)
+}
 
-	installedLibPath, err := installFileToFolder(toolkitRoot, libraryPath)
+// installLibraryFile installs the specified library file to the toolkit
+// directory. If the name of the file differs from the specified library name,
+// a symlink with the library name is created once the file is installed.
+func (i *installer) installLibraryFile(libraryPath string, libName string, toolkitRoot string) error {
+	installedLibPath, err := i.installFileToFolder(toolkitRoot, libraryPath)
 	if err != nil {
-		return fmt.Errorf("error installing %v to %v: %v", libraryPath, toolkitRoot, err)
+		return fmt.Errorf("error installing %v to %v: %w", libraryPath, toolkitRoot, err)
 	}
 	log.Infof("Installed '%v' to '%v'", libraryPath, installedLibPath)
 
@@ -460,7 +1030,7 @@ func installLibrary(libName string, toolkitRoot string) error {
 		return nil
 	}
 
-	err = installSymlink(toolkitRoot, libName, installedLibPath)
+	err = i.installSymlink(toolkitRoot, libName, installedLibPath)
 	if err != nil {
 		return fmt.Errorf("error installing symlink for NVIDIA container library: %v", err)
 	}
@@ -470,12 +1040,12 @@ func installLibrary(libName string, toolkitRoot string) error {
 
 // installToolkitConfig installs the config file for the NVIDIA container toolkit ensuring
 // that the settings are updated to match the desired install and nvidia driver directories.
-func installToolkitConfig(c *cli.Context, toolkitConfigPath string, nvidiaContainerCliExecutablePath string, nvidiaCTKPath string, nvidaContainerRuntimeHookPath string, opts *options) error {
+func (i *installer) installToolkitConfig(c *cli.Context, toolkitConfigPath string, nvidiaContainerCliExecutablePath string, nvidiaCTKPath string, nvidaContainerRuntimeHookPath string, opts *options) error {
 	log.Infof("Installing NVIDIA container toolkit config '%v'", toolkitConfigPath)
 
-	cfg, err := loadConfig(nvidiaContainerToolkitConfigSource)
+	cfg, err := getToolkitConfig(c, nvidiaContainerCliExecutablePath, nvidiaCTKPath, nvidaContainerRuntimeHookPath, opts)
 	if err != nil {
-		return fmt.Errorf("could not open source config file: %v", err)
+		return err
 	}
 
 	targetConfig, err := os.Create(toolkitConfigPath)
@@ -484,18 +1054,99 @@ func installToolkitConfig(c *cli.Context, toolkitConfigPath string, nvidiaContai
 	}
 	defer targetConfig.Close()
 
+	if _, err := cfg.WriteTo(targetConfig); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+	if err := i.setOwnership(toolkitConfigPath); err != nil {
+		return err
+	}
+
+	if err := writeAdditionalConfigs(cfg, opts.additionalConfigPaths.Value(), opts.ignoreErrors); err != nil {
+		return fmt.Errorf("error writing additional configs: %w", err)
+	}
+
+	os.Stdout.WriteString("Using config:\n")
+	if err := echoConfig(os.Stdout, cfg, opts.configRedactKeys.Value()); err != nil {
+		log.Warningf("Failed to output config to STDOUT: %v", err)
+	}
+
+	return nil
+}
+
+// printToolkitConfig writes the toolkit config that would be installed for the
+// specified options to the specified writer. No files are installed or
+// modified.
+func printToolkitConfig(c *cli.Context, opts *options, w io.Writer) error {
+	cfg, err := getToolkitConfig(
+		c,
+		filepath.Join(opts.toolkitRoot, "nvidia-container-cli"),
+		filepath.Join(opts.toolkitRoot, "nvidia-ctk"),
+		filepath.Join(opts.toolkitRoot, "nvidia-container-runtime-hook"),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	return echoConfig(w, cfg, opts.configRedactKeys.Value())
+}
+
+// getDriverLdconfigPath returns the normalized path of the specified ldconfig
+// binary in the driver root. The path in the source config may or may not be
+// prefixed with @ to indicate a host path. The returned path is always a host
+// path and refers to the .real binary if this exists in the driver root. If
+// neither the configured binary nor its .real variant exist, an alternative
+// ldconfig binary is located in the driver root. Since the installer runs in a
+// container, the binaries are located at the path of the driver root in the
+// container and the result is translated to the driver root on the host.
+func getDriverLdconfigPath(driverRoot string, driverRootCtrPath string, ldconfigPath string) string {
+	hostPath := filepath.Join("/", strings.TrimPrefix(ldconfigPath, "@"))
+	normalized := strings.TrimPrefix(config.NormalizeLDConfigPath("@"+filepath.Join(driverRootCtrPath, hostPath)), "@")
+	configured := toHostDriverPath(driverRoot, driverRootCtrPath, normalized)
+	if _, err := os.Stat(normalized); err == nil {
+		return "@" + configured
+	}
+
+	located, err := locateLdconfig(driverRootCtrPath, hostPath)
+	if err != nil {
+		log.Warningf("Using configured ldconfig %v: %v", configured, err)
+		return "@" + configured
+	}
+	located = toHostDriverPath(driverRoot, driverRootCtrPath, located)
+	log.Infof("Configured ldconfig %v does not exist; using %v", configured, located)
+	return "@" + located
+}
+
+// toHostDriverPath translates a path in the driver root in the container to the
+// corresponding path in the driver root on the host.
+func toHostDriverPath(driverRoot string, driverRootCtrPath string, path string) string {
+	relative, err := filepath.Rel(driverRootCtrPath, path)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(driverRoot, relative)
+}
+
+// getToolkitConfig loads the source config for the NVIDIA container toolkit
+// and updates the settings to match the desired install and nvidia driver
+// directories.
+func getToolkitConfig(c *cli.Context, nvidiaContainerCliExecutablePath string, nvidiaCTKPath string, nvidaContainerRuntimeHookPath string, opts *options) (*toml.Tree, error) {
+	cfg, err := loadConfig(opts.toolkitConfigSource)
+	if err != nil {
+		return nil, fmt.Errorf("could not open source config file: %v", err)
+	}
+
 	// Read the ldconfig path from the config as this may differ per platform
 	// On ubuntu-based systems this ends in `.real`
 	ldconfigPath := fmt.Sprintf("%s", cfg.GetDefault("nvidia-container-cli.ldconfig", "/sbin/ldconfig"))
-	// Use the driver run root as the root:
-	driverLdconfigPath := config.NormalizeLDConfigPath("@" + filepath.Join(opts.DriverRoot, strings.TrimPrefix(ldconfigPath, "@/")))
+	driverRoot := getConfigDriverRoot(opts.DriverRoot, opts.DriverRootCtrPath, opts.resolveDriverRoot)
+	driverLdconfigPath := getDriverLdconfigPath(driverRoot, opts.DriverRootCtrPath, ldconfigPath)
 
 	configValues := map[string]interface{}{
 		// Set the options in the root toml table
 		"accept-nvidia-visible-devices-envvar-when-unprivileged": opts.acceptNVIDIAVisibleDevicesWhenUnprivileged,
 		"accept-nvidia-visible-devices-as-volume-mounts":         opts.acceptNVIDIAVisibleDevicesAsVolumeMounts,
 		// Set the nvidia-container-cli options
-		"nvidia-container-cli.root":     opts.DriverRoot,
+		"nvidia-container-cli.root":     driverRoot,
 		"nvidia-container-cli.path":     nvidiaContainerCliExecutablePath,
 		"nvidia-container-cli.ldconfig": driverLdconfigPath,
 		// Set nvidia-ctk options
@@ -545,19 +1196,48 @@ func installToolkitConfig(c *cli.Context, toolkitConfigPath string, nvidiaContai
 		cfg.Set(key, value)
 	}
 
-	if _, err := cfg.WriteTo(targetConfig); err != nil {
-		return fmt.Errorf("error writing config: %v", err)
-	}
+	return cfg, nil
+}
 
-	os.Stdout.WriteString("Using config:\n")
-	if _, err = cfg.WriteTo(os.Stdout); err != nil {
-		log.Warningf("Failed to output config to STDOUT: %v", err)
+// getConfigDriverRoot returns the driver root to use in the generated config.
+// If resolve is set and the driver root is a symlink, the target of the symlink
+// is returned. This means that the generated config refers to a concrete path
+// and is not affected if the symlink is repointed (e.g. on driver upgrades).
+// Since the installer runs in a container, the symlink is read at the path of
+// the driver root in the container and a relative target is interpreted
+// relative to the driver root on the host. Only a single level of symlinks is
+// resolved. If resolution fails, the specified driver root is returned.
+func getConfigDriverRoot(driverRoot string, driverRootCtrPath string, resolve bool) string {
+	if !resolve {
+		return driverRoot
+	}
+	info, err := os.Lstat(driverRootCtrPath)
+	if err != nil {
+		log.Warningf("Failed to resolve driver root %v; using it as is: %v", driverRoot, err)
+		return driverRoot
 	}
-
-	return nil
+	if info.Mode()&os.ModeSymlink == 0 {
+		return driverRoot
+	}
+	target, err := os.Readlink(driverRootCtrPath)
+	if err != nil {
+		log.Warningf("Failed to resolve driver root %v; using it as is: %v", driverRoot, err)
+		return driverRoot
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(driverRoot), target)
+	}
+	resolved := filepath.Clean(target)
+	log.Infof("Resolved driver root %v to %v", driverRoot, resolved)
+	return resolved
 }
 
+// loadConfig loads the config from the specified path. If the path is '-', the
+// config is read from STDIN. A missing config file results in an empty config.
 func loadConfig(path string) (*toml.Tree, error) {
+	if path == "-" {
+		return loadConfigFrom(os.Stdin)
+	}
 	_, err := os.Stat(path)
 	if err == nil {
 		return toml.LoadFile(path)
@@ -567,8 +1247,22 @@ func loadConfig(path string) (*toml.Tree, error) {
 	return nil, err
 }
 
+// loadConfigFrom loads the config from the specified reader.
+// Since an empty input is most likely an error in the pipeline producing the
+// config, this is treated as an error instead of as an empty config.
+func loadConfigFrom(reader io.Reader) (*toml.Tree, error) {
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	if len(strings.TrimSpace(string(contents))) == 0 {
+		return nil, fmt.Errorf("empty config read from STDIN")
+	}
+	return toml.LoadBytes(contents)
+}
+
 // installContainerToolkitCLI installs the nvidia-ctk CLI executable and wrapper.
-func installContainerToolkitCLI(toolkitDir string) (string, error) {
+func (i *installer) installContainerToolkitCLI(toolkitDir string) (string, error) {
 	e := executable{
 		source: "/usr/bin/nvidia-ctk",
 		target: executableTarget{
@@ -577,11 +1271,11 @@ func installContainerToolkitCLI(toolkitDir string) (string, error) {
 		},
 	}
 
-	return e.install(toolkitDir)
+	return e.install(i, toolkitDir)
 }
 
 // installContainerCDIHookCLI installs the nvidia-cdi-hook CLI executable and wrapper.
-func installContainerCDIHookCLI(toolkitDir string) (string, error) {
+func (i *installer) installContainerCDIHookCLI(toolkitDir string) (string, error) {
 	e := executable{
 		source: "/usr/bin/nvidia-cdi-hook",
 		target: executableTarget{
@@ -590,12 +1284,12 @@ func installContainerCDIHookCLI(toolkitDir string) (string, error) {
 		},
 	}
 
-	return e.install(toolkitDir)
+	return e.install(i, toolkitDir)
 }
 
 // installContainerCLI sets up the NVIDIA container CLI executable, copying the executable
 // and implementing the required wrapper
-func installContainerCLI(toolkitRoot string) (string, error) {
+func (i *installer) installContainerCLI(toolkitRoot string) (string, error) {
 	log.Infof("Installing NVIDIA container CLI from '%v'", nvidiaContainerCliSource)
 
 	env := map[string]string{
@@ -611,16 +1305,16 @@ func installContainerCLI(toolkitRoot string) (string, error) {
 		env: env,
 	}
 
-	installedPath, err := e.install(toolkitRoot)
+	installedPath, err := e.install(i, toolkitRoot)
 	if err != nil {
-		return "", fmt.Errorf("error installing NVIDIA container CLI: %v", err)
+		return "", fmt.Errorf("error installing NVIDIA container CLI: %w", err)
 	}
 	return installedPath, nil
 }
 
 // installRuntimeHook sets up the NVIDIA runtime hook, copying the executable
 // and implementing the required wrapper
-func installRuntimeHook(toolkitRoot string, configFilePath string) (string, error) {
+func (i *installer) installRuntimeHook(toolkitRoot string, configFilePath string) (string, error) {
 	log.Infof("Installing NVIDIA container runtime hook from '%v'", nvidiaContainerRuntimeHookSource)
 
 	argLines := []string{
@@ -636,12 +1330,12 @@ func installRuntimeHook(toolkitRoot string, configFilePath string) (string, erro
 		argLines: argLines,
 	}
 
-	installedPath, err := e.install(toolkitRoot)
+	installedPath, err := e.install(i, toolkitRoot)
 	if err != nil {
-		return "", fmt.Errorf("error installing NVIDIA container runtime hook: %v", err)
+		return "", fmt.Errorf("error installing NVIDIA container runtime hook: %w", err)
 	}
 
-	err = installSymlink(toolkitRoot, "nvidia-container-toolkit", installedPath)
+	err = i.installSymlink(toolkitRoot, "nvidia-container-toolkit", installedPath)
 	if err != nil {
 		return "", fmt.Errorf("error installing symlink to NVIDIA container runtime hook: %v", err)
 	}
@@ -651,15 +1345,54 @@ func installRuntimeHook(toolkitRoot string, configFilePath string) (string, erro
 
 // installSymlink creates a symlink in the toolkitDirectory that points to the specified target.
 // Note: The target is assumed to be local to the toolkit directory
-func installSymlink(toolkitRoot string, link string, target string) error {
+func (i *installer) installSymlink(toolkitRoot string, link string, target string) error {
 	symlinkPath := filepath.Join(toolkitRoot, link)
 	targetPath := filepath.Base(target)
+
+	info, err := os.Lstat(symlinkPath)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("error checking existing path '%v': %v", symlinkPath, err)
+	case info.Mode()&os.ModeSymlink != 0:
+		existing, err := os.Readlink(symlinkPath)
+		if err != nil {
+			return fmt.Errorf("error reading existing symlink '%v': %v", symlinkPath, err)
+		}
+		if existing == targetPath {
+			log.Infof("Skipping existing symlink '%v' -> '%v'", symlinkPath, targetPath)
+			return i.setOwnership(symlinkPath)
+		}
+		if err := i.removeConflictingPath(symlinkPath, fmt.Sprintf("existing symlink to '%v'", existing)); err != nil {
+			return err
+		}
+	case info.IsDir():
+		return fmt.Errorf("error creating symlink '%v' => '%v': a directory exists at the path", symlinkPath, targetPath)
+	default:
+		if err := i.removeConflictingPath(symlinkPath, "existing file"); err != nil {
+			return err
+		}
+	}
 	log.Infof("Creating symlink '%v' -> '%v'", symlinkPath, targetPath)
 
-	err := os.Symlink(targetPath, symlinkPath)
+	err = os.Symlink(targetPath, symlinkPath)
 	if err != nil {
 		return fmt.Errorf("error creating symlink '%v' => '%v': %v", symlinkPath, targetPath, err)
 	}
+	return i.setOwnership(symlinkPath)
+}
+
+// removeConflictingPath removes the specified path so that a symlink can be
+// created in its place. If conflicting paths are not to be replaced, an error
+// is returned.
+func (i *installer) removeConflictingPath(path string, description string) error {
+	if !i.replaceConflictingSymlinks {
+		return fmt.Errorf("error creating symlink '%v': %v conflicts with the symlink to be installed", path, description)
+	}
+	log.Warningf("Replacing %v at '%v'", description, path)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("error removing %v at '%v': %v", description, path, err)
+	}
 	return nil
 }
 
@@ -667,50 +1400,88 @@ func installSymlink(toolkitRoot string, link string, target string) error {
 // The path of the input file is ignored.
 // e.g. installFileToFolder("/some/path/file.txt", "/output/path")
 // will result in a file "/output/path/file.txt" being generated
-func installFileToFolder(destFolder string, src string) (string, error) {
+func (i *installer) installFileToFolder(destFolder string, src string) (string, error) {
 	name := filepath.Base(src)
-	return installFileToFolderWithName(destFolder, name, src)
+	return i.installFileToFolderWithName(destFolder, name, src)
 }
 
 // cp src destFolder/name
-func installFileToFolderWithName(destFolder string, name, src string) (string, error) {
+func (i *installer) installFileToFolderWithName(destFolder string, name, src string) (string, error) {
 	dest := filepath.Join(destFolder, name)
-	err := installFile(dest, src)
+	err := i.installFile(dest, src)
 	if err != nil {
-		return "", fmt.Errorf("error copying '%v' to '%v': %v", src, dest, err)
+		return "", fmt.Errorf("error copying '%v' to '%v': %w", src, dest, err)
 	}
 	return dest, nil
 }
 
-// installFile copies a file from src to dest and maintains
-// file modes
-func installFile(dest string, src string) error {
+// installFile installs a file from src to dest using the configured file
+// installer and sets the ownership of the installed file.
+func (i *installer) installFile(dest string, src string) error {
+	if err := i.fileInstaller.installFile(dest, src); err != nil {
+		return err
+	}
+	return i.setOwnership(dest)
+}
+
+// copyFile copies a file from src to dest and maintains
+// file modes. If dest already matches src, the copy is skipped.
+func copyFile(dest string, src string) error {
+	if isUnchanged(dest, src) {
+		log.Infof("Skipping unchanged '%v'", dest)
+		return nil
+	}
 	log.Infof("Installing '%v' to '%v'", src, dest)
 
 	source, err := os.Open(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error opening source: %w: %w", ErrArtifactNotFound, err)
+	}
 	if err != nil {
-		return fmt.Errorf("error opening source: %v", err)
+		return fmt.Errorf("error opening source: %w: %w", ErrCopyFailed, err)
 	}
 	defer source.Close()
 
 	destination, err := os.Create(dest)
 	if err != nil {
-		return fmt.Errorf("error creating destination: %v", err)
+		return fmt.Errorf("error creating destination: %w: %w", ErrCopyFailed, err)
 	}
 	defer destination.Close()
 
 	_, err = io.Copy(destination, source)
 	if err != nil {
-		return fmt.Errorf("error copying file: %v", err)
+		return fmt.Errorf("error copying file: %w: %w", ErrCopyFailed, err)
 	}
 
 	err = applyModeFromSource(dest, src)
 	if err != nil {
-		return fmt.Errorf("error setting destination file mode: %v", err)
+		return fmt.Errorf("error setting destination file mode: %w: %w", ErrCopyFailed, err)
 	}
 	return nil
 }
 
+// isUnchanged checks whether dest exists and has the same mode and contents as
+// src. The sizes of the files are compared before their contents are, which
+// are compared by checksum so that the files are not read into memory.
+func isUnchanged(dest string, src string) bool {
+	destInfo, err := os.Stat(dest)
+	if err != nil {
+		return false
+	}
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return false
+	}
+	if destInfo.Mode() != srcInfo.Mode() {
+		return false
+	}
+	same, err := sameFileContents(dest, src)
+	if err != nil {
+		return false
+	}
+	return same
+}
+
 // applyModeFromSource sets the file mode for a destination file
 // to match that of a specified source file
 func applyModeFromSource(dest string, src string) error {
@@ -725,18 +1496,82 @@ func applyModeFromSource(dest string, src string) error {
 	return nil
 }
 
-// findLibrary searches a set of candidate libraries in the specified root for
-// a given library name
-func findLibrary(root string, libName string) (string, error) {
-	log.Infof("Finding library %v (root=%v)", libName, root)
+// goarch is the architecture of the running binary. It is a variable so that
+// it can be overridden in tests.
+var goarch = runtime.GOARCH
+
+// defaultLibrarySearchDirs are the directories searched for the toolkit
+// libraries in their default order.
+var defaultLibrarySearchDirs = []string{
+	"/usr/lib64",
+	"/usr/lib/x86_64-linux-gnu",
+	"/usr/lib/aarch64-linux-gnu",
+}
+
+// libraryArchDirs maps the supported architectures to their architecture
+// specific library directories.
+var libraryArchDirs = map[string]string{
+	"amd64": "/usr/lib/x86_64-linux-gnu",
+	"arm64": "/usr/lib/aarch64-linux-gnu",
+}
+
+// validateLibraryArch checks whether the specified library architecture is
+// supported. An empty architecture is equivalent to "auto".
+func validateLibraryArch(arch string) error {
+	if arch == "" || arch == "auto" {
+		return nil
+	}
+	if _, ok := libraryArchDirs[arch]; ok {
+		return nil
+	}
+	return fmt.Errorf("unsupported architecture %q; expected one of auto, amd64, arm64", arch)
+}
+
+// getLibrarySearchDirs returns the directories searched for the toolkit
+// libraries. The directory for the specified architecture is moved ahead of
+// those for other architectures, which are kept as fallbacks. For "auto", the
+// architecture of the running binary is used. If the architecture has no
+// specific directory, the full list is returned in its default order.
+func getLibrarySearchDirs(arch string) []string {
+	if arch == "" || arch == "auto" {
+		arch = goarch
+	}
+
+	native, ok := libraryArchDirs[arch]
+	if !ok {
+		return append([]string{}, defaultLibrarySearchDirs...)
+	}
+
+	var dirs []string
+	var fallbacks []string
+	for _, dir := range defaultLibrarySearchDirs {
+		if dir != native && isArchLibraryDir(dir) {
+			fallbacks = append(fallbacks, dir)
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, fallbacks...)
+}
 
-	candidateDirs := []string{
-		"/usr/lib64",
-		"/usr/lib/x86_64-linux-gnu",
-		"/usr/lib/aarch64-linux-gnu",
+// isArchLibraryDir returns true if the specified directory is specific to one
+// of the supported architectures.
+func isArchLibraryDir(dir string) bool {
+	for _, archDir := range libraryArchDirs {
+		if dir == archDir {
+			return true
+		}
 	}
+	return false
+}
 
-	for _, d := range candidateDirs {
+// findLibrary searches a set of candidate libraries in the specified root for
+// a given library name. The library directories for the specified architecture
+// are searched first.
+func findLibrary(root string, libName string, arch string) (string, error) {
+	log.Infof("Finding library %v (root=%v)", libName, root)
+
+	for _, d := range getLibrarySearchDirs(arch) {
 		l := filepath.Join(root, d, libName)
 		log.Infof("Checking library candidate '%v'", l)
 
@@ -749,7 +1584,7 @@ func findLibrary(root string, libName string) (string, error) {
 		return libraryCandidate, nil
 	}
 
-	return "", fmt.Errorf("error locating library '%v'", libName)
+	return "", fmt.Errorf("error locating library '%v': %w", libName, ErrArtifactNotFound)
 }
 
 // resolveLink finds the target of a symlink or the file itself in the
@@ -766,13 +1601,18 @@ func resolveLink(l string) (string, error) {
 	return resolved, nil
 }
 
-func createDirectories(dir ...string) error {
+// createDirectories creates the specified directories and sets their
+// ownership. Parent directories that are created implicitly are not modified.
+func (i *installer) createDirectories(dir ...string) error {
 	for _, d := range dir {
 		log.Infof("Creating directory '%v'", d)
 		err := os.MkdirAll(d, 0755)
 		if err != nil {
 			return fmt.Errorf("error creating directory: %v", err)
 		}
+		if err := i.setOwnership(d); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -785,6 +1625,7 @@ func createDeviceNodes(opts *options) error {
 
 	devices, err := nvdevices.New(
 		nvdevices.WithDevRoot(opts.DevRootCtrPath),
+		nvdevices.WithDeviceMinors(opts.deviceMinors.Value()...),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create library: %v", err)
@@ -796,53 +1637,121 @@ func createDeviceNodes(opts *options) error {
 			log.Warningf("Unrecognised device mode: %v", mode)
 			continue
 		}
+		if err := createControlDeviceNodes(devices, controlDeviceNodeAttempts, controlDeviceNodeRetryInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	// controlDeviceNodeAttempts is the number of times that the creation of
+	// the control device nodes is attempted before failing.
+	controlDeviceNodeAttempts = 5
+	// controlDeviceNodeRetryInterval is the time to wait between attempts to
+	// create the control device nodes.
+	controlDeviceNodeRetryInterval = 500 * time.Millisecond
+)
+
+// controlDeviceNodeCreator defines the interface for creating and verifying
+// the NVIDIA control device nodes.
+type controlDeviceNodeCreator interface {
+	CreateNVIDIAControlDevices() error
+	MissingNVIDIAControlDevices() ([]string, error)
+}
+
+// createControlDeviceNodes creates the NVIDIA control device nodes and
+// verifies that they are present. Since device nodes may appear
+// asynchronously on some kernels, creation is retried up to the specified
+// number of attempts. If nodes are still missing after the final attempt, an
+// error listing these nodes is returned.
+func createControlDeviceNodes(devices controlDeviceNodeCreator, attempts int, interval time.Duration) error {
+	var missing []string
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			log.Warningf("Retrying creation of control device nodes in %v (attempt %d of %d); missing %v", interval, attempt, attempts, missing)
+			time.Sleep(interval)
+		}
 		if err := devices.CreateNVIDIAControlDevices(); err != nil {
 			return fmt.Errorf("failed to create control device nodes: %v", err)
 		}
+		var err error
+		missing, err = devices.MissingNVIDIAControlDevices()
+		if err != nil {
+			return fmt.Errorf("failed to verify control device nodes: %v", err)
+		}
+		if len(missing) == 0 {
+			return nil
+		}
 	}
-	return nil
+	return fmt.Errorf("control device nodes missing after %d attempts: %v", attempts, strings.Join(missing, ", "))
 }
 
-// generateCDISpec generates a CDI spec for use in management containers
+// generateCDISpec generates a CDI spec for use in management containers. If
+// printing is requested the spec is written to STDOUT instead of being saved.
 func generateCDISpec(opts *options, nvidiaCDIHookPath string) error {
 	if !opts.cdiEnabled {
 		return nil
 	}
 	log.Info("Generating CDI spec for management containers")
-	cdilib, err := nvcdi.New(
-		nvcdi.WithMode(nvcdi.ModeManagement),
-		nvcdi.WithDriverRoot(opts.DriverRootCtrPath),
-		nvcdi.WithDevRoot(opts.DevRootCtrPath),
-		nvcdi.WithNVIDIACDIHookPath(nvidiaCDIHookPath),
-		nvcdi.WithVendor(opts.cdiVendor),
-		nvcdi.WithClass(opts.cdiClass),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create CDI library for management containers: %v", err)
+	specConfig := nvcdi.ManagementSpecConfig{
+		DriverRoot:        opts.DriverRootCtrPath,
+		TargetDriverRoot:  opts.DriverRoot,
+		DevRoot:           opts.DevRootCtrPath,
+		TargetDevRoot:     opts.DevRoot,
+		NVIDIACDIHookPath: nvidiaCDIHookPath,
+		Vendor:            opts.cdiVendor,
+		Class:             opts.cdiClass,
+		DeviceIndices:     opts.cdiDeviceIndices,
+	}
+
+	var driverVersion string
+	if opts.cdiVersionedSpecName {
+		var err error
+		driverVersion, err = root.New(root.WithDriverRoot(opts.DriverRootCtrPath)).Version()
+		if err != nil {
+			return fmt.Errorf("failed to determine driver version for CDI spec name: %v", err)
+		}
 	}
 
-	spec, err := cdilib.GetSpec()
-	if err != nil {
-		return fmt.Errorf("failed to genereate CDI spec for management containers: %v", err)
+	specs := make(map[string]spec.Interface)
+	if opts.cdiPerDeviceSpecs {
+		deviceSpecs, err := nvcdi.GetManagementDeviceSpecs(
+			log.StandardLogger(),
+			specConfig,
+			nvcdi.WithManagementEditsOnly(opts.cdiManagementEditsOnly),
+		)
+		if err != nil {
+			return err
+		}
+		for uuid, s := range deviceSpecs {
+			specs[getManagementDeviceSpecName(opts.cdiVendor, opts.cdiClass, driverVersion, uuid)] = s
+		}
+	} else {
+		s, err := nvcdi.GetManagementSpec(
+			log.StandardLogger(),
+			specConfig,
+			nvcdi.WithManagementEditsOnly(opts.cdiManagementEditsOnly),
+		)
+		if err != nil {
+			return err
+		}
+		specs[getManagementSpecName(opts.cdiVendor, opts.cdiClass, driverVersion)] = s
 	}
 
-	transformer := transformroot.NewDriverTransformer(
-		transformroot.WithDriverRoot(opts.DriverRootCtrPath),
-		transformroot.WithTargetDriverRoot(opts.DriverRoot),
-		transformroot.WithDevRoot(opts.DevRootCtrPath),
-		transformroot.WithTargetDevRoot(opts.DevRoot),
-	)
-	if err := transformer.Transform(spec.Raw()); err != nil {
-		return fmt.Errorf("failed to transform driver root in CDI spec: %v", err)
+	if opts.cdiPrint {
+		return printManagementSpecs(os.Stdout, opts.cdiExtraEdits, specs)
 	}
 
-	name, err := cdi.GenerateNameForSpec(spec.Raw())
+	names, err := saveManagementSpecs(opts.cdiOutputDir, opts.cdiExtraEdits, specs)
 	if err != nil {
-		return fmt.Errorf("failed to generate CDI name for management containers: %v", err)
+		return err
 	}
-	err = spec.Save(filepath.Join(opts.cdiOutputDir, name))
-	if err != nil {
-		return fmt.Errorf("failed to save CDI spec for management containers: %v", err)
+
+	if opts.cdiPruneStaleSpecs {
+		if err := pruneManagementSpecs(opts.cdiOutputDir, opts.cdiVendor, opts.cdiClass, names...); err != nil {
+			return fmt.Errorf("failed to prune stale CDI specs for management containers: %v", err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,1184 @@
+/**
+# Copyright (c) NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	toml "github.com/pelletier/go-toml"
+	log "github.com/sirupsen/logrus"
+	testlog "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+// recordingFileInstaller is a fileInstaller mock that records the peak number
+// of files being installed at the same time. It also records the installed
+// files in order and whether the symlink to a file already existed when it
+// was installed.
+type recordingFileInstaller struct {
+	sync.Mutex
+	inFlight int
+	peak     int
+
+	installed []string
+	// links maps the path of an installed file to the path of its symlink.
+	links map[string]string
+	// linksBeforeTarget lists the symlinks that existed before their target
+	// was installed.
+	linksBeforeTarget []string
+}
+
+func (r *recordingFileInstaller) installFile(dest string, src string) error {
+	r.Lock()
+	r.inFlight++
+	if r.inFlight > r.peak {
+		r.peak = r.inFlight
+	}
+	r.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+	_, linkErr := os.Lstat(r.links[dest])
+	err := os.WriteFile(dest, []byte(src), 0644)
+
+	r.Lock()
+	defer r.Unlock()
+	r.inFlight--
+	r.installed = append(r.installed, dest)
+	if linkErr == nil {
+		r.linksBeforeTarget = append(r.linksBeforeTarget, r.links[dest])
+	}
+	return err
+}
+
+func TestInstallConcurrently(t *testing.T) {
+	const concurrency = 4
+	const count = 16
+
+	sourceDir := t.TempDir()
+	toolkitRoot := t.TempDir()
+
+	mock := &recordingFileInstaller{links: make(map[string]string)}
+	i := newInstaller(WithFileInstaller(mock), WithConcurrency(concurrency))
+
+	var expectedInstalled []string
+	var installers []func() error
+	for n := 0; n < count; n++ {
+		libName := fmt.Sprintf("libnvidia-foo%d.so.1", n)
+		libraryPath := filepath.Join(sourceDir, fmt.Sprintf("libnvidia-foo%d.so.999.88.77", n))
+		target := filepath.Join(toolkitRoot, filepath.Base(libraryPath))
+		mock.links[target] = filepath.Join(toolkitRoot, libName)
+		expectedInstalled = append(expectedInstalled, target)
+
+		installers = append(installers, func() error {
+			return i.installLibraryFile(libraryPath, libName, toolkitRoot)
+		})
+	}
+
+	require.NoError(t, i.installConcurrently(installers...))
+
+	require.Equal(t, concurrency, mock.peak)
+	require.ElementsMatch(t, expectedInstalled, mock.installed)
+	require.Empty(t, mock.linksBeforeTarget)
+
+	for target, link := range mock.links {
+		require.FileExists(t, target)
+		linkTarget, err := os.Readlink(link)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Base(target), linkTarget)
+	}
+}
+
+func TestInstallConcurrentlyJoinsErrors(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	var called int32
+	err := newInstaller().installConcurrently(
+		func() error { atomic.AddInt32(&called, 1); return errFirst },
+		func() error { atomic.AddInt32(&called, 1); return nil },
+		func() error { atomic.AddInt32(&called, 1); return errSecond },
+	)
+	require.ErrorIs(t, err, errFirst)
+	require.ErrorIs(t, err, errSecond)
+	require.Equal(t, int32(3), called)
+}
+
+func TestGetConfigDriverRoot(t *testing.T) {
+	// The driver root is accessible at a different path in the container. The
+	// symlinks refer to the paths of their targets on the host.
+	ctrRoot := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(ctrRoot, "driver-550.54.15"), 0755))
+	require.NoError(t, os.Symlink("/run/nvidia/driver-550.54.15", filepath.Join(ctrRoot, "driver")))
+	require.NoError(t, os.Symlink("driver-550.54.15", filepath.Join(ctrRoot, "relative")))
+
+	testCases := []struct {
+		description       string
+		driverRoot        string
+		driverRootCtrPath string
+		resolve           bool
+		expected          string
+	}{
+		{
+			description:       "symlink is preserved by default",
+			driverRoot:        "/run/nvidia/driver",
+			driverRootCtrPath: filepath.Join(ctrRoot, "driver"),
+			expected:          "/run/nvidia/driver",
+		},
+		{
+			description:       "symlink is resolved",
+			driverRoot:        "/run/nvidia/driver",
+			driverRootCtrPath: filepath.Join(ctrRoot, "driver"),
+			resolve:           true,
+			expected:          "/run/nvidia/driver-550.54.15",
+		},
+		{
+			description:       "relative symlink is resolved on the host",
+			driverRoot:        "/run/nvidia/relative",
+			driverRootCtrPath: filepath.Join(ctrRoot, "relative"),
+			resolve:           true,
+			expected:          "/run/nvidia/driver-550.54.15",
+		},
+		{
+			description:       "regular directory is unchanged",
+			driverRoot:        "/run/nvidia/driver-550.54.15",
+			driverRootCtrPath: filepath.Join(ctrRoot, "driver-550.54.15"),
+			resolve:           true,
+			expected:          "/run/nvidia/driver-550.54.15",
+		},
+		{
+			description:       "missing driver root is returned as is",
+			driverRoot:        "/run/nvidia/missing",
+			driverRootCtrPath: filepath.Join(ctrRoot, "missing"),
+			resolve:           true,
+			expected:          "/run/nvidia/missing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, getConfigDriverRoot(tc.driverRoot, tc.driverRootCtrPath, tc.resolve))
+		})
+	}
+}
+
+func TestGetDriverLdconfigPath(t *testing.T) {
+	testCases := []struct {
+		description  string
+		ldconfigPath string
+		files        []string
+		expectedPath string
+	}{
+		{
+			description:  "plain path",
+			ldconfigPath: "/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig",
+		},
+		{
+			description:  "host path",
+			ldconfigPath: "@/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig",
+		},
+		{
+			description:  "host path without leading slash",
+			ldconfigPath: "@sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig"},
+			expectedPath: "/sbin/ldconfig",
+		},
+		{
+			description:  "plain path prefers .real binary",
+			ldconfigPath: "/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig", "/sbin/ldconfig.real"},
+			expectedPath: "/sbin/ldconfig.real",
+		},
+		{
+			description:  "host path prefers .real binary",
+			ldconfigPath: "@/sbin/ldconfig",
+			files:        []string{"/sbin/ldconfig", "/sbin/ldconfig.real"},
+			expectedPath: "/sbin/ldconfig.real",
+		},
+		{
+			description:  ".real path",
+			ldconfigPath: "/sbin/ldconfig.real",
+			files:        []string{"/sbin/ldconfig.real"},
+			expectedPath: "/sbin/ldconfig.real",
+		},
+		{
+			description:  "missing .real binary",
+			ldconfigPath: "/sbin/ldconfig.real",
+			expectedPath: "/sbin/ldconfig",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			driverRoot := t.TempDir()
+			for _, f := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Join(driverRoot, filepath.Dir(f)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(driverRoot, f), nil, 0755))
+			}
+
+			require.Equal(t, "@"+filepath.Join(driverRoot, tc.expectedPath), getDriverLdconfigPath(driverRoot, driverRoot, tc.ldconfigPath))
+		})
+	}
+}
+
+func TestLoadConfigFrom(t *testing.T) {
+	testCases := []struct {
+		description   string
+		input         string
+		expectedError bool
+		expectedRoot  interface{}
+	}{
+		{
+			description:   "empty input is an error",
+			input:         "",
+			expectedError: true,
+		},
+		{
+			description:   "whitespace input is an error",
+			input:         "  \n",
+			expectedError: true,
+		},
+		{
+			description:   "invalid toml is an error",
+			input:         "[nvidia-container-cli",
+			expectedError: true,
+		},
+		{
+			description:  "valid config is loaded",
+			input:        "[nvidia-container-cli]\nroot = \"/run/nvidia/driver\"\n",
+			expectedRoot: "/run/nvidia/driver",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg, err := loadConfigFrom(strings.NewReader(tc.input))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedRoot, cfg.Get("nvidia-container-cli.root"))
+		})
+	}
+}
+
+func TestTryDeletePreserve(t *testing.T) {
+	testCases := []struct {
+		description       string
+		files             []string
+		preserve          []string
+		expectedRemaining []string
+		expectRemoved     bool
+	}{
+		{
+			description:       "toolkit.pid is preserved by default",
+			expectedRemaining: []string{"toolkit.pid"},
+		},
+		{
+			description:       "named files are preserved",
+			preserve:          []string{"license.txt"},
+			expectedRemaining: []string{"license.txt", "toolkit.pid"},
+		},
+		{
+			description:       "glob patterns are preserved",
+			preserve:          []string{"*.pem"},
+			expectedRemaining: []string{"ca.pem", "extra.pem", "toolkit.pid"},
+		},
+		{
+			description:   "empty toolkit directory is removed",
+			files:         []string{"license.txt", "libnvidia-container.so.1", ".config/config.toml"},
+			expectRemoved: true,
+		},
+		{
+			description:   "unmatched preserve patterns remove the toolkit directory",
+			files:         []string{"license.txt", "libnvidia-container.so.1", ".config/config.toml"},
+			preserve:      []string{"*.pem"},
+			expectRemoved: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toolkitRoot := filepath.Join(t.TempDir(), "toolkit")
+			require.NoError(t, os.MkdirAll(filepath.Join(toolkitRoot, ".config"), 0755))
+			files := tc.files
+			if files == nil {
+				files = []string{"toolkit.pid", "license.txt", "ca.pem", "extra.pem", "libnvidia-container.so.1", ".config/config.toml"}
+			}
+			for _, name := range files {
+				require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, name), nil, 0644))
+			}
+
+			opts := &options{
+				toolkitRoot: toolkitRoot,
+				preserve:    *cli.NewStringSlice(tc.preserve...),
+			}
+			require.NoError(t, TryDelete(nil, opts))
+
+			if tc.expectRemoved {
+				_, err := os.Stat(toolkitRoot)
+				require.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+
+			contents, err := os.ReadDir(toolkitRoot)
+			require.NoError(t, err)
+			var remaining []string
+			for _, content := range contents {
+				remaining = append(remaining, content.Name())
+			}
+			require.EqualValues(t, tc.expectedRemaining, remaining)
+		})
+	}
+}
+
+func TestTryDeleteReturnsErrors(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("removal errors cannot be forced when running as root")
+	}
+
+	testCases := []struct {
+		description   string
+		ignoreErrors  bool
+		expectedError bool
+	}{
+		{
+			description:   "removal errors are returned",
+			expectedError: true,
+		},
+		{
+			description:  "removal errors are ignored",
+			ignoreErrors: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toolkitRoot := filepath.Join(t.TempDir(), "toolkit")
+			readOnly := filepath.Join(toolkitRoot, "read-only")
+			require.NoError(t, os.MkdirAll(readOnly, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(readOnly, "file"), nil, 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(toolkitRoot, "removable"), nil, 0644))
+			require.NoError(t, os.Chmod(readOnly, 0555))
+			t.Cleanup(func() {
+				_ = os.Chmod(readOnly, 0755)
+			})
+
+			opts := &options{
+				toolkitRoot:  toolkitRoot,
+				ignoreErrors: tc.ignoreErrors,
+			}
+			err := TryDelete(nil, opts)
+			if tc.expectedError {
+				require.Error(t, err)
+				require.ErrorIs(t, err, os.ErrPermission)
+			} else {
+				require.NoError(t, err)
+			}
+
+			_, err = os.Stat(filepath.Join(toolkitRoot, "removable"))
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestValidateAnnotationPrefix(t *testing.T) {
+	testCases := []struct {
+		prefix        string
+		expectedError bool
+	}{
+		{prefix: "cdi.k8s.io/"},
+		{prefix: "nvidia.cdi.k8s.io/"},
+		{prefix: "my-domain.example.com/"},
+		{prefix: "localhost/"},
+		{prefix: "cdi.k8s.io", expectedError: true},
+		{prefix: "/", expectedError: true},
+		{prefix: "", expectedError: true},
+		{prefix: "cdi..k8s.io/", expectedError: true},
+		{prefix: "-cdi.k8s.io/", expectedError: true},
+		{prefix: "cdi-.k8s.io/", expectedError: true},
+		{prefix: "cdi_k8s.io/", expectedError: true},
+		{prefix: "cdi.k8s.io/devices/", expectedError: true},
+		{prefix: strings.Repeat("a.", 127) + "a/", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.prefix, func(t *testing.T) {
+			err := validateAnnotationPrefix(tc.prefix)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateOptionsAnnotationPrefixes(t *testing.T) {
+	testCases := []struct {
+		description   string
+		prefixes      []string
+		expectedError bool
+	}{
+		{
+			description: "no prefixes are valid",
+		},
+		{
+			description: "valid prefixes",
+			prefixes:    []string{"cdi.k8s.io/", "nvidia.cdi.k8s.io/"},
+		},
+		{
+			description:   "invalid prefix is an error",
+			prefixes:      []string{"cdi.k8s.io/", "cdi.k8s.io"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := &options{
+				toolkitRoot: "/usr/local/nvidia/toolkit",
+				cdiKind:     "management.nvidia.com/gpu",
+				ContainerRuntimeModesCDIAnnotationPrefixes: *cli.NewStringSlice(tc.prefixes...),
+			}
+			err := validateOptions(nil, opts)
+			if tc.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateOptionsReportsAllErrors(t *testing.T) {
+	opts := &options{
+		cdiKind: "-invalid/-invalid",
+		ContainerRuntimeModesCDIAnnotationPrefixes: *cli.NewStringSlice("cdi.k8s.io", "nvidia.cdi.k8s.io"),
+		createDeviceNodes:                          *cli.NewStringSlice("invalid"),
+	}
+
+	err := validateOptions(nil, opts)
+	require.Error(t, err)
+
+	for _, expected := range []string{
+		"invalid --toolkit-root option",
+		"invalid CDI vendor name",
+		"invalid CDI class name",
+		"invalid CDI annotation prefix: \"cdi.k8s.io\"",
+		"invalid CDI annotation prefix: \"nvidia.cdi.k8s.io\"",
+		"invalid --create-device-nodes value: invalid",
+	} {
+		require.ErrorContains(t, err, expected)
+	}
+	require.Empty(t, opts.cdiVendor)
+	require.Empty(t, opts.cdiClass)
+	require.EqualValues(t, []string{"invalid"}, opts.createDeviceNodes.Value())
+}
+
+func TestValidateOptionsSkipCDIKindValidation(t *testing.T) {
+	testCases := []struct {
+		description     string
+		skip            bool
+		expectedError   bool
+		expectedVendor  string
+		expectedClass   string
+		expectedWarning bool
+	}{
+		{
+			description:   "invalid kind is rejected by default",
+			expectedError: true,
+		},
+		{
+			description:     "invalid kind is accepted with a warning if validation is skipped",
+			skip:            true,
+			expectedVendor:  "example_vendor",
+			expectedClass:   "-gpu",
+			expectedWarning: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logHook := testlog.NewGlobal()
+			defer logHook.Reset()
+
+			opts := &options{
+				toolkitRoot:           "/usr/local/nvidia/toolkit",
+				cdiKind:               "example_vendor/-gpu",
+				skipCDIKindValidation: tc.skip,
+			}
+			err := validateOptions(nil, opts)
+			if tc.expectedError {
+				require.ErrorContains(t, err, "invalid CDI class name")
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedVendor, opts.cdiVendor)
+			require.Equal(t, tc.expectedClass, opts.cdiClass)
+
+			var warnings []string
+			for _, entry := range logHook.AllEntries() {
+				if entry.Level == log.WarnLevel {
+					warnings = append(warnings, entry.Message)
+				}
+			}
+			if tc.expectedWarning {
+				require.Len(t, warnings, 1)
+				require.Contains(t, warnings[0], "--skip-cdi-kind-validation")
+			} else {
+				require.Empty(t, warnings)
+			}
+		})
+	}
+}
+
+func TestSplitCommaSeparated(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       []string
+		expected    []string
+	}{
+		{
+			description: "empty slice is unchanged",
+			expected:    []string{},
+		},
+		{
+			description: "comma-separated value is split",
+			input:       []string{"control,uvm"},
+			expected:    []string{"control", "uvm"},
+		},
+		{
+			description: "already split values are unchanged",
+			input:       []string{"control", "uvm"},
+			expected:    []string{"control", "uvm"},
+		},
+		{
+			description: "mixed values are split",
+			input:       []string{"control, uvm", "modeset"},
+			expected:    []string{"control", "uvm", "modeset"},
+		},
+		{
+			description: "empty value is kept",
+			input:       []string{""},
+			expected:    []string{""},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			s := splitCommaSeparated(*cli.NewStringSlice(tc.input...))
+			require.EqualValues(t, tc.expected, s.Value())
+		})
+	}
+}
+
+func TestGetDeviceNodeModes(t *testing.T) {
+	testCases := []struct {
+		description   string
+		modes         []string
+		explicit      bool
+		cdiEnabled    bool
+		expectedModes []string
+		expectedError bool
+	}{
+		{
+			description:   "default modes with CDI enabled creates device nodes",
+			modes:         []string{"control"},
+			cdiEnabled:    true,
+			expectedModes: []string{"control"},
+		},
+		{
+			description: "default modes with CDI disabled creates no device nodes",
+			modes:       []string{"control"},
+		},
+		{
+			description: "explicit none with CDI enabled creates no device nodes",
+			modes:       []string{"none"},
+			explicit:    true,
+			cdiEnabled:  true,
+		},
+		{
+			description:   "explicit modes with CDI disabled creates device nodes",
+			modes:         []string{"control"},
+			explicit:      true,
+			expectedModes: []string{"control"},
+		},
+		{
+			description: "empty mode creates no device nodes",
+			modes:       []string{"control", ""},
+			explicit:    true,
+		},
+		{
+			description:   "invalid mode is an error",
+			modes:         []string{"invalid"},
+			explicit:      true,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			modes, err := getDeviceNodeModes(tc.modes, tc.explicit, tc.cdiEnabled)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedModes, modes)
+		})
+	}
+}
+
+func TestInstallErrors(t *testing.T) {
+	t.Run("missing library is not found", func(t *testing.T) {
+		_, err := findLibrary(t.TempDir(), "libnotfound.so.1", "auto")
+		require.ErrorIs(t, err, ErrArtifactNotFound)
+	})
+
+	t.Run("missing source is not found", func(t *testing.T) {
+		source := filepath.Join(t.TempDir(), "missing")
+		_, err := newInstaller().installFileToFolder(t.TempDir(), source)
+		require.ErrorIs(t, err, ErrArtifactNotFound)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		require.NotErrorIs(t, err, ErrCopyFailed)
+	})
+
+	t.Run("missing destination is a copy failure", func(t *testing.T) {
+		source := filepath.Join(t.TempDir(), "source")
+		require.NoError(t, os.WriteFile(source, []byte("source"), 0644))
+		destFolder := filepath.Join(t.TempDir(), "missing")
+		_, err := newInstaller().installFileToFolder(destFolder, source)
+		require.ErrorIs(t, err, ErrCopyFailed)
+		require.NotErrorIs(t, err, ErrArtifactNotFound)
+	})
+
+	t.Run("executable errors are propagated", func(t *testing.T) {
+		e := executable{
+			source: filepath.Join(t.TempDir(), "missing"),
+			target: executableTarget{
+				dotfileName: "missing.real",
+				wrapperName: "missing",
+			},
+		}
+		_, err := e.install(newInstaller(), t.TempDir())
+		require.ErrorIs(t, err, ErrArtifactNotFound)
+	})
+}
+
+func TestInstallFileSkipsUnchanged(t *testing.T) {
+	sourceDir := t.TempDir()
+	destDir := t.TempDir()
+
+	source := filepath.Join(sourceDir, "libfoo.so.1")
+	require.NoError(t, os.WriteFile(source, []byte("foo"), 0755))
+
+	i := newInstaller()
+	dest, err := i.installFileToFolder(destDir, source)
+	require.NoError(t, err)
+	require.True(t, isUnchanged(dest, source))
+
+	require.NoError(t, os.Chmod(source, 0644))
+	require.False(t, isUnchanged(dest, source))
+	require.NoError(t, os.Chmod(source, 0755))
+
+	require.NoError(t, os.WriteFile(source, []byte("foobar"), 0755))
+	require.False(t, isUnchanged(dest, source))
+
+	require.NoError(t, os.WriteFile(source, []byte("bar"), 0755))
+	require.False(t, isUnchanged(dest, source))
+
+	_, err = i.installFileToFolder(destDir, source)
+	require.NoError(t, err)
+	contents, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	require.Equal(t, "bar", string(contents))
+
+	require.NoError(t, i.installSymlink(destDir, "libfoo.so", dest))
+	require.NoError(t, i.installSymlink(destDir, "libfoo.so", dest))
+}
+
+func TestInstallPrintConfig(t *testing.T) {
+	toolkitRoot := t.TempDir()
+	opts := options{
+		toolkitRoot:         toolkitRoot,
+		toolkitConfigSource: filepath.Join(t.TempDir(), "missing.toml"),
+		DriverRoot:          "/run/nvidia/driver",
+		printConfig:         true,
+	}
+
+	var output strings.Builder
+	app := cli.NewApp()
+	app.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "nvidia-container-runtime.mode",
+			Destination: &opts.ContainerRuntimeMode,
+		},
+		&cli.StringFlag{
+			Name:        "nvidia-container-cli.debug",
+			Destination: &opts.ContainerCLIDebug,
+		},
+	}
+	app.Action = func(c *cli.Context) error {
+		require.NoError(t, printToolkitConfig(c, &opts, &output))
+		return Install(c, &opts)
+	}
+	require.NoError(t, app.Run([]string{"toolkit", "--nvidia-container-runtime.mode=cdi"}))
+
+	cfg, err := toml.Load(output.String())
+	require.NoError(t, err)
+	require.Equal(t, "cdi", cfg.Get("nvidia-container-runtime.mode"))
+	require.Equal(t, "/run/nvidia/driver", cfg.Get("nvidia-container-cli.root"))
+	require.Equal(t, filepath.Join(toolkitRoot, "nvidia-container-cli"), cfg.Get("nvidia-container-cli.path"))
+	require.Equal(t, filepath.Join(toolkitRoot, "nvidia-ctk"), cfg.Get("nvidia-ctk.path"))
+	require.Equal(t, filepath.Join(toolkitRoot, "nvidia-container-runtime-hook"), cfg.Get("nvidia-container-runtime-hook.path"))
+	require.Nil(t, cfg.Get("nvidia-container-cli.debug"))
+
+	entries, err := os.ReadDir(toolkitRoot)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestInstallSymlink(t *testing.T) {
+	testCases := []struct {
+		description    string
+		existing       func(string) error
+		failOnConflict bool
+		expectedError  bool
+		expectedLink   string
+	}{
+		{
+			description:  "symlink is created",
+			expectedLink: "libfoo.so.1",
+		},
+		{
+			description: "matching symlink is kept",
+			existing: func(path string) error {
+				return os.Symlink("libfoo.so.1", path)
+			},
+			expectedLink: "libfoo.so.1",
+		},
+		{
+			description: "symlink to different target is replaced",
+			existing: func(path string) error {
+				return os.Symlink("libfoo.so.0", path)
+			},
+			expectedLink: "libfoo.so.1",
+		},
+		{
+			description: "existing file is replaced",
+			existing: func(path string) error {
+				return os.WriteFile(path, []byte("conflict"), 0644)
+			},
+			expectedLink: "libfoo.so.1",
+		},
+		{
+			description: "existing file is an error if conflicts are not replaced",
+			existing: func(path string) error {
+				return os.WriteFile(path, []byte("conflict"), 0644)
+			},
+			failOnConflict: true,
+			expectedError:  true,
+		},
+		{
+			description: "symlink to different target is an error if conflicts are not replaced",
+			existing: func(path string) error {
+				return os.Symlink("libfoo.so.0", path)
+			},
+			failOnConflict: true,
+			expectedError:  true,
+		},
+		{
+			description: "existing directory is an error",
+			existing: func(path string) error {
+				return os.Mkdir(path, 0755)
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			toolkitRoot := t.TempDir()
+			linkPath := filepath.Join(toolkitRoot, "libfoo.so")
+			if tc.existing != nil {
+				require.NoError(t, tc.existing(linkPath))
+			}
+
+			i := newInstaller(WithReplaceConflictingSymlinks(!tc.failOnConflict))
+			err := i.installSymlink(toolkitRoot, "libfoo.so", filepath.Join(toolkitRoot, "libfoo.so.1"))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			link, err := os.Readlink(linkPath)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLink, link)
+		})
+	}
+}
+
+func TestParseDeviceIndices(t *testing.T) {
+	testCases := []struct {
+		value         string
+		expected      []int
+		expectedError bool
+	}{
+		{value: ""},
+		{value: "all"},
+		{value: "0", expected: []int{0}},
+		{value: "2, 0,2", expected: []int{2, 0}},
+		{value: "-1", expectedError: true},
+		{value: "0,gpu1", expectedError: true},
+		{value: "0,", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			indices, err := parseDeviceIndices(tc.value)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expected, indices)
+		})
+	}
+}
+
+func TestValidateOptionsCDIDevices(t *testing.T) {
+	testCases := []struct {
+		description     string
+		devices         string
+		perDeviceSpecs  bool
+		expectedError   bool
+		expectedIndices []int
+	}{
+		{
+			description: "all devices",
+			devices:     "all",
+		},
+		{
+			description:     "device indices with per-device specs",
+			devices:         "1,3",
+			perDeviceSpecs:  true,
+			expectedIndices: []int{1, 3},
+		},
+		{
+			description:   "device indices require per-device specs",
+			devices:       "1",
+			expectedError: true,
+		},
+		{
+			description:    "invalid device index",
+			devices:        "one",
+			perDeviceSpecs: true,
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			opts := &options{
+				toolkitRoot:       "/usr/local/nvidia/toolkit",
+				cdiKind:           "management.nvidia.com/gpu",
+				cdiDevices:        tc.devices,
+				cdiPerDeviceSpecs: tc.perDeviceSpecs,
+			}
+			err := validateOptions(nil, opts)
+			if tc.expectedError {
+				require.ErrorContains(t, err, "--cdi-devices")
+				return
+			}
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedIndices, opts.cdiDeviceIndices)
+		})
+	}
+}
+
+func TestValidateOptionsCDIManagementEditsOnly(t *testing.T) {
+	opts := &options{
+		toolkitRoot:            "/usr/local/nvidia/toolkit",
+		cdiKind:                "management.nvidia.com/gpu",
+		cdiDevices:             "all",
+		cdiManagementEditsOnly: true,
+	}
+	require.NoError(t, validateOptions(nil, opts))
+
+	opts.cdiPerDeviceSpecs = true
+	require.ErrorContains(t, validateOptions(nil, opts), "--cdi-management-edits-only")
+}
+
+func TestValidateOptionsRuntimeMode(t *testing.T) {
+	testCases := []struct {
+		mode          string
+		expectedError bool
+	}{
+		{mode: ""},
+		{mode: "auto"},
+		{mode: "legacy"},
+		{mode: "csv"},
+		{mode: "cdi"},
+		{mode: "none"},
+		{mode: "unknown", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.mode, func(t *testing.T) {
+			opts := &options{
+				toolkitRoot:          "/usr/local/nvidia/toolkit",
+				cdiKind:              "management.nvidia.com/gpu",
+				cdiDevices:           "all",
+				ContainerRuntimeMode: tc.mode,
+			}
+			err := validateOptions(nil, opts)
+			if tc.expectedError {
+				require.ErrorContains(t, err, "--nvidia-container-runtime.mode")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGetLibrarySearchDirs(t *testing.T) {
+	testCases := []struct {
+		description  string
+		goarch       string
+		arch         string
+		expectedDirs []string
+	}{
+		{
+			description: "auto on amd64",
+			goarch:      "amd64",
+			arch:        "auto",
+			expectedDirs: []string{
+				"/usr/lib64",
+				"/usr/lib/x86_64-linux-gnu",
+				"/usr/lib/aarch64-linux-gnu",
+			},
+		},
+		{
+			description: "auto on arm64",
+			goarch:      "arm64",
+			arch:        "auto",
+			expectedDirs: []string{
+				"/usr/lib64",
+				"/usr/lib/aarch64-linux-gnu",
+				"/usr/lib/x86_64-linux-gnu",
+			},
+		},
+		{
+			description: "empty is auto",
+			goarch:      "arm64",
+			expectedDirs: []string{
+				"/usr/lib64",
+				"/usr/lib/aarch64-linux-gnu",
+				"/usr/lib/x86_64-linux-gnu",
+			},
+		},
+		{
+			description: "override takes precedence over GOARCH",
+			goarch:      "amd64",
+			arch:        "arm64",
+			expectedDirs: []string{
+				"/usr/lib64",
+				"/usr/lib/aarch64-linux-gnu",
+				"/usr/lib/x86_64-linux-gnu",
+			},
+		},
+		{
+			description:  "auto on other architecture uses the full list",
+			goarch:       "ppc64le",
+			arch:         "auto",
+			expectedDirs: defaultLibrarySearchDirs,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			defer setGOARCH(tc.goarch)()
+			require.EqualValues(t, tc.expectedDirs, getLibrarySearchDirs(tc.arch))
+		})
+	}
+}
+
+func TestFindLibraryPrefersNativeArch(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"/usr/lib/x86_64-linux-gnu", "/usr/lib/aarch64-linux-gnu"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "libtest.so.1"), []byte(dir), 0644))
+	}
+
+	defer setGOARCH("arm64")()
+	found, err := findLibrary(root, "libtest.so.1", "auto")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "/usr/lib/aarch64-linux-gnu/libtest.so.1"), found)
+
+	found, err = findLibrary(root, "libtest.so.1", "amd64")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(root, "/usr/lib/x86_64-linux-gnu/libtest.so.1"), found)
+}
+
+// setGOARCH overrides the architecture of the running binary and returns a
+// function that restores it.
+func setGOARCH(arch string) func() {
+	original := goarch
+	goarch = arch
+	return func() {
+		goarch = original
+	}
+}
+
+func TestValidateOptionsLibraryArch(t *testing.T) {
+	testCases := []struct {
+		arch          string
+		expectedError bool
+	}{
+		{arch: ""},
+		{arch: "auto"},
+		{arch: "amd64"},
+		{arch: "arm64"},
+		{arch: "ppc64le", expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.arch, func(t *testing.T) {
+			opts := &options{
+				toolkitRoot: "/usr/local/nvidia/toolkit",
+				cdiKind:     "management.nvidia.com/gpu",
+				cdiDevices:  "all",
+				libraryArch: tc.arch,
+			}
+			err := validateOptions(nil, opts)
+			if tc.expectedError {
+				require.ErrorContains(t, err, "--library-arch")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// fakeControlDevices is a controlDeviceNodeCreator for which the device nodes
+// only appear at the dev root after the specified number of attempts.
+type fakeControlDevices struct {
+	devRoot     string
+	appearAfter int
+	createErr   error
+	attempts    int
+}
+
+var fakeControlDeviceNodes = []string{"nvidiactl", "nvidia-uvm"}
+
+func (f *fakeControlDevices) CreateNVIDIAControlDevices() error {
+	f.attempts++
+	if f.createErr != nil {
+		return f.createErr
+	}
+	if f.attempts < f.appearAfter {
+		return nil
+	}
+	for _, node := range fakeControlDeviceNodes {
+		if err := os.WriteFile(filepath.Join(f.devRoot, node), nil, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeControlDevices) MissingNVIDIAControlDevices() ([]string, error) {
+	var missing []string
+	for _, node := range fakeControlDeviceNodes {
+		path := filepath.Join(f.devRoot, node)
+		if _, err := os.Stat(path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	return missing, nil
+}
+
+func TestCreateControlDeviceNodes(t *testing.T) {
+	testCases := []struct {
+		description      string
+		appearAfter      int
+		createErr        error
+		expectedError    string
+		expectedAttempts int
+	}{
+		{
+			description:      "nodes present after first attempt",
+			appearAfter:      1,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "nodes present after retries",
+			appearAfter:      3,
+			expectedAttempts: 3,
+		},
+		{
+			description:      "missing nodes are reported",
+			appearAfter:      5,
+			expectedError:    "control device nodes missing after 4 attempts",
+			expectedAttempts: 4,
+		},
+		{
+			description:      "creation errors are not retried",
+			createErr:        fmt.Errorf("mknod failed"),
+			expectedError:    "mknod failed",
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			devices := &fakeControlDevices{
+				devRoot:     t.TempDir(),
+				appearAfter: tc.appearAfter,
+				createErr:   tc.createErr,
+			}
+
+			err := createControlDeviceNodes(devices, 4, 0)
+			require.Equal(t, tc.expectedAttempts, devices.attempts)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.expectedError)
+			if tc.createErr == nil {
+				for _, node := range fakeControlDeviceNodes {
+					require.ErrorContains(t, err, filepath.Join(devices.devRoot, node))
+				}
+			}
+		})
+	}
+}